@@ -0,0 +1,338 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	calendarHeaderHeight = 24
+	calendarEntryHeight  = 16
+)
+
+var deadlineStateColors = map[int]Color{
+	DeadlineStateTimeRemains: NewColor(90, 170, 255, 255),
+	DeadlineStateDueToday:    NewColor(255, 175, 50, 255),
+	DeadlineStateOverdue:     NewColor(240, 50, 50, 255),
+	DeadlineStateDone:        NewColor(100, 200, 100, 255),
+}
+
+type calendarEntry struct {
+	Card *Card
+	Day  int // Index of the day cell the entry is in
+	Rect *sdl.FRect
+}
+
+// Calendar is a MenuElement that shows a month grid, listing the complete-able Cards due on each day, colored by their deadline state.
+// Dragging a Card to another day reschedules its deadline, while clicking it focuses on the Card.
+type Calendar struct {
+	Rect  *sdl.FRect
+	Month time.Time // The first day of the month being displayed
+
+	entries    []*calendarEntry
+	dragEntry  *calendarEntry
+	dragOrigin Point
+	dragged    bool
+}
+
+func NewCalendar(rect *sdl.FRect) *Calendar {
+	calendar := &Calendar{Rect: rect}
+	calendar.GoToDate(time.Now())
+	return calendar
+}
+
+// GoToDate sets the Calendar to show the month the given date falls in.
+func (calendar *Calendar) GoToDate(date time.Time) {
+	calendar.Month = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.Local)
+}
+
+// MoveMonths moves the Calendar forward or backward by the given number of months.
+func (calendar *Calendar) MoveMonths(months int) {
+	calendar.Month = calendar.Month.AddDate(0, months, 0)
+}
+
+// FirstDay returns the date shown in the Calendar's top-left cell (the Sunday on or before the first of the month).
+func (calendar *Calendar) FirstDay() time.Time {
+	return calendar.Month.AddDate(0, 0, -int(calendar.Month.Weekday()))
+}
+
+func (calendar *Calendar) cellRect(day int) *sdl.FRect {
+	w := calendar.Rect.W / 7
+	h := (calendar.Rect.H - calendarHeaderHeight) / 6
+	return &sdl.FRect{
+		calendar.Rect.X + float32(day%7)*w,
+		calendar.Rect.Y + calendarHeaderHeight + float32(day/7)*h,
+		w,
+		h,
+	}
+}
+
+func (calendar *Calendar) dayAt(pos Point) int {
+	for day := 0; day < 42; day++ {
+		if pos.Inside(calendar.cellRect(day)) {
+			return day
+		}
+	}
+	return -1
+}
+
+func (calendar *Calendar) Update() {
+
+	calendar.entries = calendar.buildEntries()
+
+	pos := globals.Mouse.Position()
+	button := globals.Mouse.Button(sdl.BUTTON_LEFT)
+
+	if pos.Inside(calendar.Rect) {
+
+		var hovered *calendarEntry
+
+		for _, entry := range calendar.entries {
+			if entry.Rect != nil && pos.Inside(entry.Rect) {
+				hovered = entry
+				globals.Mouse.SetCursor(CursorHand)
+				break
+			}
+		}
+
+		if button.Pressed() && hovered != nil {
+			button.Consume()
+			calendar.dragEntry = hovered
+			calendar.dragOrigin = pos
+			calendar.dragged = false
+		}
+
+	}
+
+	if calendar.dragEntry != nil {
+
+		delta := pos.Sub(calendar.dragOrigin)
+
+		if math.Abs(float64(delta.X)) > 4 || math.Abs(float64(delta.Y)) > 4 {
+			calendar.dragged = true
+			globals.Mouse.SetCursor(CursorHandGrab)
+		}
+
+		if !button.HeldRaw() {
+
+			card := calendar.dragEntry.Card
+
+			if !calendar.dragged {
+				card.Page.Project.Camera.FocusOn(false, card)
+				card.Page.Selection.Clear()
+				card.Page.Selection.Add(card)
+			} else if day := calendar.dayAt(pos); day >= 0 && day != calendar.dragEntry.Day {
+
+				deadline := calendar.FirstDay().AddDate(0, 0, day)
+				card.SetDeadlineDate(deadline)
+
+				// Capture the undo state immediately, as the Card may not be on the current Page
+				card.HandleUndos()
+
+				globals.EventLog.Log("Deadline for [%s] moved to %s.", false, card.Name(), deadline.Format("2006-01-02"))
+
+			}
+
+			calendar.dragEntry = nil
+
+		}
+
+	}
+
+}
+
+func (calendar *Calendar) Draw() {
+
+	rect := &sdl.Rect{int32(calendar.Rect.X), int32(calendar.Rect.Y), int32(calendar.Rect.W), int32(calendar.Rect.H)}
+
+	// Combine our clipping rectangle with the Container's, so we don't draw outside of it
+	if len(globals.ClipRects) > 0 {
+		if clipped, ok := rect.Intersect(globals.ClipRects[len(globals.ClipRects)-1]); ok {
+			rect = &clipped
+		} else {
+			return
+		}
+	}
+
+	globals.Renderer.SetClipRect(rect)
+	globals.ClipRects = append(globals.ClipRects, rect)
+
+	fontColor := getThemeColor(GUIFontColor)
+	menuColor := getThemeColor(GUIMenuColor)
+
+	FillRect(calendar.Rect.X, calendar.Rect.Y, calendar.Rect.W, calendar.Rect.H, getThemeColor(GUIBGColor))
+	FillRect(calendar.Rect.X, calendar.Rect.Y, calendar.Rect.W, calendarHeaderHeight, menuColor)
+
+	for i, weekday := range []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"} {
+		cell := calendar.cellRect(i)
+		globals.TextRenderer.QuickRenderText(weekday, Point{cell.X + cell.W/2, calendar.Rect.Y + 2}, 0.5, fontColor, nil, AlignCenter)
+	}
+
+	pos := globals.Mouse.Position()
+	firstDay := calendar.FirstDay()
+
+	for day := 0; day < 42; day++ {
+
+		cell := calendar.cellRect(day)
+		date := firstDay.AddDate(0, 0, day)
+
+		if DatesAreEqual(date, time.Now()) {
+			FillRect(cell.X, cell.Y, cell.W, cell.H, menuColor.Accent())
+		}
+
+		if calendar.dragEntry != nil && calendar.dragged && pos.Inside(cell) {
+			highlight := fontColor.Clone()
+			highlight[3] = 48
+			FillRect(cell.X, cell.Y, cell.W, cell.H, highlight)
+		}
+
+		lineColor := fontColor.Clone()
+		lineColor[3] = 64
+		globals.Renderer.SetDrawColor(lineColor.RGBA())
+		globals.Renderer.DrawRectF(cell)
+
+		dayColor := fontColor.Clone()
+		if date.Month() != calendar.Month.Month() {
+			dayColor[3] = 96
+		}
+
+		globals.TextRenderer.QuickRenderText(strconv.Itoa(date.Day()), Point{cell.X + 4, cell.Y + 2}, 0.5, dayColor, nil, AlignLeft)
+
+	}
+
+	for _, entry := range calendar.entries {
+		// Entries are rebuilt every frame, so the dragged entry is found by its Card
+		if entry.Rect != nil && (calendar.dragEntry == nil || entry.Card != calendar.dragEntry.Card || !calendar.dragged) {
+			calendar.drawEntry(entry, entry.Rect)
+		}
+	}
+
+	// Entries that don't fit in their day's cell are summarized instead
+	hidden := map[int]int{}
+	for _, entry := range calendar.entries {
+		if entry.Rect == nil {
+			hidden[entry.Day]++
+		}
+	}
+
+	for day, count := range hidden {
+		cell := calendar.cellRect(day)
+		globals.TextRenderer.QuickRenderText("+"+strconv.Itoa(count)+" more", Point{cell.X + cell.W - 4, cell.Y + 2}, 0.5, fontColor, nil, AlignRight)
+	}
+
+	if calendar.dragEntry != nil && calendar.dragged && calendar.dragEntry.Rect != nil {
+		dragRect := *calendar.dragEntry.Rect
+		dragRect.X += pos.X - calendar.dragOrigin.X
+		dragRect.Y += pos.Y - calendar.dragOrigin.Y
+		calendar.drawEntry(calendar.dragEntry, &dragRect)
+	}
+
+	globals.ClipRects[len(globals.ClipRects)-1] = nil
+	globals.ClipRects = globals.ClipRects[:len(globals.ClipRects)-1]
+	if len(globals.ClipRects) > 0 {
+		globals.Renderer.SetClipRect(globals.ClipRects[len(globals.ClipRects)-1])
+	} else {
+		globals.Renderer.SetClipRect(nil)
+	}
+
+}
+
+func (calendar *Calendar) drawEntry(entry *calendarEntry, rect *sdl.FRect) {
+
+	color := deadlineStateColors[entry.Card.DeadlineState()]
+
+	FillRect(rect.X, rect.Y, rect.W, rect.H, color)
+
+	if entry.Card.selected {
+		globals.Renderer.SetDrawColor(getThemeColor(GUIFontColor).RGBA())
+		globals.Renderer.DrawRectF(rect)
+	}
+
+	clip := &sdl.Rect{int32(rect.X), int32(rect.Y), int32(rect.W), int32(rect.H)}
+	if clipped, ok := clip.Intersect(globals.ClipRects[len(globals.ClipRects)-1]); ok {
+		globals.Renderer.SetClipRect(&clipped)
+		globals.TextRenderer.QuickRenderText(strings.ReplaceAll(entry.Card.Name(), "\n", " - "), Point{rect.X + 2, rect.Y}, 0.5, ColorBlack, nil, AlignLeft)
+		globals.Renderer.SetClipRect(globals.ClipRects[len(globals.ClipRects)-1])
+	}
+
+}
+
+func (calendar *Calendar) Rectangle() *sdl.FRect {
+	return calendar.Rect
+}
+
+func (calendar *Calendar) SetRectangle(rect *sdl.FRect) {
+	calendar.Rect = rect
+}
+
+func (calendar *Calendar) Destroy() {}
+
+// buildEntries returns entries for the complete-able Cards due on the days currently shown, with entries that don't fit in their
+// day's cell having no Rect.
+func (calendar *Calendar) buildEntries() []*calendarEntry {
+
+	entries := []*calendarEntry{}
+
+	if globals.Project == nil {
+		return entries
+	}
+
+	firstDay := calendar.FirstDay()
+
+	for _, page := range globals.Project.Pages {
+
+		if !page.Valid() {
+			continue
+		}
+
+		for _, card := range page.Cards {
+
+			deadline, hasDeadline := card.Deadline()
+			if !hasDeadline {
+				continue
+			}
+
+			day := int(timelineDay(deadline) - timelineDay(firstDay))
+
+			if day >= 0 && day < 42 {
+				entries = append(entries, &calendarEntry{Card: card, Day: day})
+			}
+
+		}
+
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Day != entries[j].Day {
+			return entries[i].Day < entries[j].Day
+		}
+		if entries[i].Card.Priority() != entries[j].Card.Priority() {
+			return entries[i].Card.Priority() > entries[j].Card.Priority()
+		}
+		return entries[i].Card.ID < entries[j].Card.ID
+	})
+
+	dayCounts := map[int]int{}
+
+	for _, entry := range entries {
+
+		cell := calendar.cellRect(entry.Day)
+		index := dayCounts[entry.Day]
+		y := cell.Y + 20 + float32(index*(calendarEntryHeight+2))
+
+		if y+calendarEntryHeight <= cell.Y+cell.H {
+			entry.Rect = &sdl.FRect{cell.X + 2, y, cell.W - 4, calendarEntryHeight}
+		}
+
+		dayCounts[entry.Day]++
+
+	}
+
+	return entries
+
+}
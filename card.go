@@ -33,6 +33,32 @@ const (
 	DeadlineStateDone
 )
 
+const (
+	PriorityNone = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityCritical
+)
+
+var priorityNames = []string{"None", "Low", "Medium", "High", "Critical"}
+
+var priorityColors = []Color{
+	ColorTransparent,
+	NewColor(90, 170, 255, 255),
+	NewColor(255, 215, 70, 255),
+	NewColor(255, 140, 40, 255),
+	NewColor(240, 50, 50, 255),
+}
+
+const (
+	StatusNotStarted = iota
+	StatusInProgress
+	StatusDone
+)
+
+var statusNames = []string{"Not Started", "In Progress", "Done"}
+
 type LinkJoint struct {
 	Position   Point
 	Dragging   bool
@@ -53,9 +79,10 @@ func (joint *LinkJoint) StartDragging() {
 }
 
 type LinkEnding struct {
-	Start  *Card
-	End    *Card
-	Joints []*LinkJoint
+	Start    *Card
+	End      *Card
+	Joints   []*LinkJoint
+	Blocking bool // If the link is blocking, the Start Card can't be completed until the End Card is
 }
 
 func NewLinkEnding(start, end *Card) *LinkEnding {
@@ -178,6 +205,10 @@ func (le *LinkEnding) Draw() {
 			outlineColor = ColorBlack
 		}
 
+		if le.Blocking {
+			outlineColor = blockingLinkColor
+		}
+
 		points := []Point{}
 		if len(le.Joints) == 0 {
 			points = append(points, le.Start.NearestPointInRect(le.End.Center(), true), le.End.NearestPointInRect(le.Start.Center(), true))
@@ -329,6 +360,11 @@ type Card struct {
 	LinkRectPercentage float32
 
 	changedProperty *Property
+
+	wasCompleted      bool
+	completionTracked bool
+
+	hidden bool // Whether the Card is hidden inside of a collapsed Frame
 }
 
 var globalCardID = int64(0)
@@ -373,6 +409,17 @@ func NewCard(page *Page, contentType string) *Card {
 
 func (card *Card) Update() {
 
+	// Cards in collapsed Frames can't be interacted with, but their contents still update (with the mouse hidden from them) so Timers keep running
+	if card.hidden {
+		globals.Mouse.HiddenPosition = true
+		if card.Contents != nil && (card.Page.IsCurrent() || card.ContentType == ContentTypeTimer) {
+			card.Contents.Update()
+		}
+		globals.Mouse.HiddenPosition = false
+		card.updateCompletion()
+		return
+	}
+
 	if card.Page.IsCurrent() {
 
 		card.LinkRectPercentage += globals.DeltaTime
@@ -699,6 +746,8 @@ func (card *Card) Update() {
 		card.Contents.Update()
 	}
 
+	card.updateCompletion()
+
 	if card.Page.IsCurrent() {
 
 		if card.selected && globals.Keybindings.Pressed(KBUnlinkCard) && globals.State == StateNeutral {
@@ -1007,7 +1056,7 @@ func (card *Card) UnlinkAll() {
 
 func (card *Card) DrawShadow() {
 
-	if !globals.Settings.Get(SettingsCardShadows).AsBool() || !card.Onscreen() {
+	if card.hidden || !globals.Settings.Get(SettingsCardShadows).AsBool() || !card.Onscreen() {
 		return
 	}
 
@@ -1113,6 +1162,105 @@ func (card *Card) NearestPointInRect(in Point, perpendicular bool) Point {
 
 }
 
+// Deadline returns the date the Card is due, along with whether the Card has a (valid) deadline.
+func (card *Card) Deadline() (time.Time, bool) {
+
+	if !card.Completable() || !card.Properties.Has("deadline") {
+		return time.Time{}, false
+	}
+
+	deadline, _, err := ParseDeadline(card.Properties.Get("deadline").AsString())
+
+	return time.Date(deadline.Year(), deadline.Month(), deadline.Day(), 0, 0, 0, 0, time.Local), err == nil
+
+}
+
+// DeadlineTime returns the moment the Card is due, along with whether the Card has a (valid) deadline. Deadlines without a time of day
+// are due at the end of their day.
+func (card *Card) DeadlineTime() (time.Time, bool) {
+
+	if !card.Completable() || !card.Properties.Has("deadline") {
+		return time.Time{}, false
+	}
+
+	deadline, hasTime, err := ParseDeadline(card.Properties.Get("deadline").AsString())
+
+	if !hasTime {
+		deadline = deadline.AddDate(0, 0, 1)
+	}
+
+	return deadline, err == nil
+
+}
+
+// SetDeadlineDate sets the date the Card is due on, keeping the time of day of its existing deadline, if it has one.
+func (card *Card) SetDeadlineDate(date time.Time) {
+
+	if card.Properties.Has("deadline") {
+		if deadline, hasTime, err := ParseDeadline(card.Properties.Get("deadline").AsString()); err == nil && hasTime {
+			date = time.Date(date.Year(), date.Month(), date.Day(), deadline.Hour(), deadline.Minute(), 0, 0, time.Local)
+			card.Properties.Get("deadline").Set(FormatDeadline(date, true))
+			return
+		}
+	}
+
+	card.Properties.Get("deadline").Set(FormatDeadline(date, false))
+
+}
+
+// ReminderSnooze returns when a snoozed deadline reminder for the Card should be sent again, or the zero time if it hasn't been snoozed.
+func (card *Card) ReminderSnooze() time.Time {
+
+	if !card.Properties.Has("reminder snooze") {
+		return time.Time{}
+	}
+
+	snooze, err := time.Parse(time.RFC3339, card.Properties.Get("reminder snooze").AsString())
+	if err != nil {
+		return time.Time{}
+	}
+
+	return snooze
+
+}
+
+// sentReminders returns the reminder lead times that have already been sent for the given deadline; changing the deadline resets them.
+func (card *Card) sentReminders(deadline string) []string {
+
+	if !card.Properties.Has("reminders sent") {
+		return []string{}
+	}
+
+	state := strings.SplitN(card.Properties.Get("reminders sent").AsString(), "|", 2)
+
+	if len(state) < 2 || state[0] != deadline {
+		return []string{}
+	}
+
+	return ParseTags(state[1])
+
+}
+
+// setReminderState sets a property tracking the Card's deadline reminders. This doesn't create an undo state, as undoing shouldn't
+// resend reminders, but it does mark the project as modified so the state is saved.
+func (card *Card) setReminderState(property string, value string) {
+	card.Properties.Get(property).SetRaw(value)
+	card.Page.Project.SetModifiedState()
+}
+
+// StartDate returns the date work on the Card is planned to begin, along with whether the Card has a (valid) start date.
+func (card *Card) StartDate() (time.Time, bool) {
+
+	if !card.Completable() || !card.Properties.Has("start date") {
+		return time.Time{}, false
+	}
+
+	start, err := time.ParseInLocation("2006-01-02", card.Properties.Get("start date").AsString(), time.Local)
+
+	return start, err == nil
+
+}
+
 func (card *Card) DeadlineState() int {
 
 	state := DeadlineStateDone
@@ -1123,11 +1271,15 @@ func (card *Card) DeadlineState() int {
 
 		now := time.Now()
 		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-		deadline, _ := time.ParseInLocation("2006-01-02", card.Properties.Get("deadline").AsString(), today.Location())
-		timeDiffDuration := deadline.Sub(today).Round(time.Hour * 24)
+		deadline, hasTime, _ := ParseDeadline(card.Properties.Get("deadline").AsString())
+		deadlineDay := time.Date(deadline.Year(), deadline.Month(), deadline.Day(), 0, 0, 0, 0, today.Location())
+		timeDiffDuration := deadlineDay.Sub(today).Round(time.Hour * 24)
 
 		if timeDiffDuration == 0 {
 			state = DeadlineStateDueToday
+			if hasTime && now.After(deadline) {
+				state = DeadlineStateOverdue
+			}
 		} else if timeDiffDuration < 0 {
 			state = DeadlineStateOverdue
 		} else {
@@ -1146,12 +1298,23 @@ func (card *Card) DeadlineText() string {
 		return ""
 	}
 
+	text, _ := card.deadlineText()
+
+	return text
+
+}
+
+// deadlineText returns the text to display for the Card's deadline, according to the deadline display setting, along with the number of
+// days (as a duration) until the day the deadline falls on.
+func (card *Card) deadlineText() (string, time.Duration) {
+
 	now := time.Now()
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	deadline, _ := time.ParseInLocation("2006-01-02", card.Properties.Get("deadline").AsString(), today.Location())
-	pureDeadlineDisplay := deadline.Format("2006-01-02")
+	deadline, hasTime, _ := ParseDeadline(card.Properties.Get("deadline").AsString())
+	deadlineDay := time.Date(deadline.Year(), deadline.Month(), deadline.Day(), 0, 0, 0, 0, today.Location())
+	pureDeadlineDisplay := FormatDeadline(deadline, hasTime)
 
-	timeDiffDuration := deadline.Sub(today).Round(time.Hour * 24)
+	timeDiffDuration := deadlineDay.Sub(today).Round(time.Hour * 24)
 
 	timeDiff := durafmt.Parse(timeDiffDuration)
 	if timeDiffDuration < 0 {
@@ -1167,6 +1330,13 @@ func (card *Card) DeadlineText() string {
 
 		if timeDiffDuration == 0 {
 			text = "Due today!"
+			if hasTime {
+				if now.After(deadline) {
+					text = "Overdue since " + deadline.Format(DeadlineTimeFormat) + "!"
+				} else {
+					text = "Due today at " + deadline.Format(DeadlineTimeFormat) + "!"
+				}
+			}
 		} else if timeDiffDuration < 0 {
 			text = "Overdue by " + timeDiff.String() + "!"
 		}
@@ -1175,12 +1345,16 @@ func (card *Card) DeadlineText() string {
 		text = "Due on " + pureDeadlineDisplay
 	}
 
-	return text
+	return text, timeDiffDuration
 
 }
 
 func (card *Card) DrawCard() {
 
+	if card.hidden {
+		return
+	}
+
 	if card.Completable() && card.Properties.Has("deadline") {
 
 		deadlineTarget := 0.0
@@ -1195,12 +1369,8 @@ func (card *Card) DrawCard() {
 
 		if card.deadlineFade > 0.01 {
 
-			now := time.Now()
-			today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-			deadline, _ := time.ParseInLocation("2006-01-02", card.Properties.Get("deadline").AsString(), today.Location())
-			pureDeadlineDisplay := deadline.Format("2006-01-02")
-
-			timeDiffDuration := deadline.Sub(today).Round(time.Hour * 24)
+			text, timeDiffDuration := card.deadlineText()
+			overdue := timeDiffDuration < 0 || (timeDiffDuration == 0 && card.DeadlineState() == DeadlineStateOverdue)
 
 			start := card.Page.Project.Camera.TranslateRect(&sdl.FRect{card.DisplayRect.X - globals.GridSize, card.DisplayRect.Y, 32, 32})
 			left := card.Page.Project.Camera.TranslatePoint(Point{card.DisplayRect.X, card.DisplayRect.Y}).X
@@ -1209,33 +1379,10 @@ func (card *Card) DrawCard() {
 
 			if deadlineDisplaySetting != DeadlineDisplayIcons {
 
-				timeDiff := durafmt.Parse(timeDiffDuration)
-				if timeDiffDuration < 0 {
-					timeDiff = durafmt.Parse(-timeDiffDuration)
-				}
-
-				text := ""
-
-				if deadlineDisplaySetting == DeadlineDisplayCountdown {
-					text = "Due in " + timeDiff.String()
-				} else {
-					text = "Due on " + pureDeadlineDisplay
-				}
-
 				deadlineColor := getThemeColor(GUIMenuColor)
 
 				if timeDiffDuration <= 0 {
 
-					if timeDiffDuration == 0 {
-						if deadlineDisplaySetting == DeadlineDisplayCountdown {
-							text = "Due today!"
-						}
-					} else {
-						if deadlineDisplaySetting == DeadlineDisplayCountdown {
-							text = "Overdue by " + timeDiff.String() + "!"
-						}
-					}
-
 					deadlineColor = getThemeColor(GUICompletedColor)
 
 					if activeScreenshot == nil && globals.Settings.Get(SettingsFlashDeadlines).AsBool() {
@@ -1285,7 +1432,7 @@ func (card *Card) DrawCard() {
 			globals.GUITexture.Texture.SetAlphaMod(255)
 
 			src := &sdl.Rect{240, 160, 32, 32}
-			if timeDiffDuration < 0 {
+			if overdue {
 				src.X = 304
 			} else if timeDiffDuration == 0 {
 				src.X = 272
@@ -1311,14 +1458,41 @@ func (card *Card) DrawCard() {
 		color = color.Sub(uint8(math.Sin(globals.Time*math.Pi*2+float64((card.Rect.X+card.Rect.Y)*0.004))*15 + 15))
 	}
 
+	// Blocked Cards are dimmed, as they can't be completed yet
+	if card.Blocked() && color[3] != 0 {
+		color = color.Mix(getThemeColor(GUIBGColor), 0.5)
+	}
+
 	if color[3] != 0 {
 		card.Result.Texture.SetColorMod(color.RGB())
 		card.Result.Texture.SetAlphaMod(color[3])
 		globals.Renderer.CopyF(card.Result.Texture, nil, tp)
 	}
 
+	if priority := card.Priority(); priority != PriorityNone {
+
+		// The priority marker is a colored strip along the right edge of the Card
+		priorityColor := priorityColors[priority]
+		if card.Completed() {
+			priorityColor = priorityColor.Clone()
+			priorityColor[3] = 96
+		}
+
+		globals.Renderer.SetDrawColor(priorityColor.RGBA())
+		globals.Renderer.FillRectF(card.Page.Project.Camera.TranslateRect(&sdl.FRect{card.DisplayRect.X + card.DisplayRect.W - 8, card.DisplayRect.Y + 4, 4, card.DisplayRect.H - 8}))
+
+	}
+
 	card.DrawContents()
 
+	if card.Page.CriticalPath[card] {
+		ThickRect(int32(tp.X-4), int32(tp.Y-4), int32(tp.W+8), int32(tp.H+8), 4, blockingLinkColor)
+	}
+
+	if card.Blocked() {
+		DrawLockIcon(Point{tp.X + tp.W - 12, tp.Y - 12}, getThemeColor(GUIMenuColor))
+	}
+
 }
 
 func (card *Card) Onscreen() bool {
@@ -1330,8 +1504,11 @@ func (card *Card) Onscreen() bool {
 }
 
 func (card *Card) DrawLinks() {
+	if card.hidden {
+		return
+	}
 	for _, link := range card.Links {
-		if link.Start == card && link.End.Valid {
+		if link.Start == card && link.End.Valid && !link.End.hidden {
 			link.Draw()
 		}
 	}
@@ -1427,6 +1604,10 @@ func (card *Card) HandleUndos() {
 
 func (card *Card) PostDraw() {
 
+	if card.hidden {
+		return
+	}
+
 	if card.Page.Arrowing == card {
 
 		translatedStart := card.Page.Project.Camera.TranslatePoint(Point{card.DisplayRect.X + (card.DisplayRect.W / 2), card.DisplayRect.Y + (card.DisplayRect.H / 2)})
@@ -1447,6 +1628,77 @@ func (card *Card) PostDraw() {
 
 	}
 
+	if tags := card.Tags(); len(tags) > 0 && card.Onscreen() {
+
+		// Tags are drawn as pills along the bottom edge of the Card, from right to left
+		pos := card.Page.Project.Camera.TranslatePoint(Point{card.DisplayRect.X + card.DisplayRect.W - (globals.GridSize * 0.25), card.DisplayRect.Y + card.DisplayRect.H - 12})
+
+		for i := len(tags) - 1; i >= 0; i-- {
+
+			width := globals.TextRenderer.MeasureText([]rune(tags[i]), 0.5).X + 16
+			if width < 16 {
+				width = 16
+			}
+
+			pos.X -= width
+			DrawLabel(pos, tags[i])
+			pos.X -= 4
+
+		}
+
+	}
+
+	// Tracked time is shown along the top edge of the Card while it's being tracked, or while the Card is selected
+	if tracking := card.IsTracking(); (tracking || card.selected) && card.Onscreen() {
+
+		text := ""
+
+		if tracking {
+			text = "Tracking : " + FormatTrackedTime(card.TrackedTime())
+		} else if total := card.TotalTrackedTime(); total > 0 {
+			text = "Tracked : " + FormatTrackedTime(total)
+		}
+
+		if text != "" {
+			pos := card.Page.Project.Camera.TranslatePoint(Point{card.DisplayRect.X + card.DisplayRect.W - globals.GridSize, card.DisplayRect.Y - 12})
+			pos.X -= globals.TextRenderer.MeasureText([]rune(text), 0.5).X + 16
+			DrawLabel(pos, text)
+		}
+
+	}
+
+	if card.Page.IsCurrent() && card.Onscreen() && globals.Mouse.WorldPosition().Inside(card.DisplayRect) {
+
+		lines := []string{}
+
+		if blockers := card.IncompleteBlockers(); len(blockers) > 0 && !card.Completed() {
+			names := []string{}
+			for _, blocker := range blockers {
+				names = append(names, strings.ReplaceAll(blocker.Name(), "\n", " - "))
+			}
+			lines = append(lines, "Blocked by : "+strings.Join(names, ", "))
+		}
+
+		if rule := card.Recurrence(); rule != nil {
+
+			lines = append(lines, rule.Description())
+
+			if history := card.RecurrenceHistory(); len(history) > 0 {
+				lines = append(lines, "Completed : "+strings.Join(history, ", "))
+			}
+
+		}
+
+		// Details like the recurrence rule and history are shown above the Card (and its numbering) while hovering over it
+		pos := card.Page.Project.Camera.TranslatePoint(Point{card.DisplayRect.X, card.DisplayRect.Y - 40})
+
+		for i := len(lines) - 1; i >= 0; i-- {
+			DrawLabel(pos, lines[i])
+			pos.Y -= 28
+		}
+
+	}
+
 	alwaysShowNumbering := globals.Settings.Get(SettingsAlwaysShowNumbering).AsBool()
 	numberableCards := card.Stack.Any(func(card *Card) bool { return card.Numberable() })
 
@@ -1508,7 +1760,12 @@ func (card *Card) PostDraw() {
 }
 
 func (card *Card) Numberable() bool {
-	return card.ContentType == ContentTypeCheckbox || card.ContentType == ContentTypeNumbered // Or table
+	// Notes only count as tasks if they have an inline checklist
+	if nc, ok := card.Contents.(*NoteContents); ok {
+		_, items := nc.ChecklistProgress()
+		return items > 0
+	}
+	return card.ContentType == ContentTypeCheckbox || card.ContentType == ContentTypeNumbered || card.ContentType == ContentTypeTable
 }
 
 func (card *Card) CompletionLevel() float32 {
@@ -1516,6 +1773,10 @@ func (card *Card) CompletionLevel() float32 {
 		return card.Contents.(*CheckboxContents).CompletionLevel()
 	} else if card.ContentType == ContentTypeNumbered {
 		return card.Contents.(*NumberedContents).CompletionLevel()
+	} else if card.ContentType == ContentTypeTable {
+		return card.Contents.(*TableContents).CompletionLevel()
+	} else if card.ContentType == ContentTypeNote {
+		return card.Contents.(*NoteContents).CompletionLevel()
 	}
 	return 0
 }
@@ -1525,6 +1786,10 @@ func (card *Card) MaximumCompletionLevel() float32 {
 		return card.Contents.(*CheckboxContents).MaximumCompletionLevel()
 	} else if card.ContentType == ContentTypeNumbered {
 		return card.Contents.(*NumberedContents).MaximumCompletionLevel()
+	} else if card.ContentType == ContentTypeTable {
+		return card.Contents.(*TableContents).MaximumCompletionLevel()
+	} else if card.ContentType == ContentTypeNote {
+		return card.Contents.(*NoteContents).MaximumCompletionLevel()
 	}
 	return 0
 }
@@ -1538,6 +1803,297 @@ func (card *Card) Completable() bool {
 	return card.ContentType == ContentTypeCheckbox || card.ContentType == ContentTypeNumbered
 }
 
+// SetCompleted completes or un-completes the Card, returning if it could be set (i.e. the Card is complete-able). Completing a Number Card sets its
+// current value to its maximum, while un-completing it resets it to 0.
+func (card *Card) SetCompleted(completed bool) bool {
+
+	switch card.ContentType {
+	case ContentTypeCheckbox:
+		card.Properties.Get("checked").Set(completed)
+	case ContentTypeNumbered:
+		if completed {
+			card.Properties.Get("current").Set(card.Properties.Get("maximum").AsFloat())
+		} else {
+			card.Properties.Get("current").Set(0.0)
+		}
+	default:
+		return false
+	}
+
+	return true
+
+}
+
+// Recurrence returns the rule the Card recurs by, or nil if it doesn't recur; only complete-able Cards can recur.
+func (card *Card) Recurrence() *RecurrenceRule {
+
+	if !card.Completable() || !card.Properties.Has("recurrence") {
+		return nil
+	}
+
+	rule, err := ParseRecurrence(card.Properties.Get("recurrence").AsString())
+	if err != nil {
+		return nil
+	}
+
+	return rule
+
+}
+
+// RecurrenceHistory returns the dates a recurring Card was previously completed on, most recent first.
+func (card *Card) RecurrenceHistory() []string {
+
+	history := []string{}
+
+	if card.Properties.Has("recurrence history") {
+		for _, date := range strings.Split(card.Properties.Get("recurrence history").AsString(), ",") {
+			if date = strings.TrimSpace(date); date != "" {
+				history = append(history, date)
+			}
+		}
+	}
+
+	return history
+
+}
+
+// updateCompletion handles a Card being completed, un-completing it if it's blocked, or resetting it if it recurs. The first update only records
+// the Card's completion state, so that Cards that were loaded as completed aren't affected.
+func (card *Card) updateCompletion() {
+
+	completed := card.Completable() && card.Completed()
+
+	if completed && !card.wasCompleted && card.completionTracked {
+
+		if blockers := card.IncompleteBlockers(); len(blockers) > 0 {
+
+			if card.ContentType == ContentTypeNumbered {
+				card.Properties.Get("current").Set(math.Max(card.Properties.Get("maximum").AsFloat()-1, 0))
+			} else {
+				card.SetCompleted(false)
+			}
+
+			// Capture the undo state immediately, as the Card may not be on the current Page
+			card.HandleUndos()
+
+			globals.EventLog.Log("[%s] can't be completed until [%s] is done.", false, card.Name(), blockers[0].Name())
+
+		} else if card.Recurrence() != nil {
+			card.Recur()
+		}
+
+		completed = card.Completed()
+
+	}
+
+	card.wasCompleted = completed
+	card.completionTracked = true
+
+}
+
+// Recur records the completion of a recurring Card, resets it, and advances its deadline (and start date) to the rule's next occurrence.
+func (card *Card) Recur() {
+
+	rule := card.Recurrence()
+	if rule == nil {
+		return
+	}
+
+	today := time.Now()
+
+	history := append([]string{today.Format("2006-01-02")}, card.RecurrenceHistory()...)
+	if len(history) > RecurrenceHistoryLength {
+		history = history[:RecurrenceHistoryLength]
+	}
+	card.Properties.Get("recurrence history").Set(strings.Join(history, ","))
+
+	// Completing a Card early moves it to the occurrence after its current deadline, while completing it late moves it to the next one from today
+	from := today
+	deadline, hasDeadline := card.Deadline()
+	if hasDeadline && deadline.After(from) {
+		from = deadline
+	}
+
+	// Monthly rules remember the day of the month of the deadline, so that after a shorter month (like January 31st to February 28th), the
+	// Card goes back to the original day; if the deadline's been moved to another day since, that day's used from then on
+	if rule.Kind == RecurrenceMonthly && hasDeadline && (rule.Day == 0 || !FitsMonthlyDay(deadline, rule.Day)) {
+		rule.Day = deadline.Day()
+		card.Properties.Get("recurrence").Set(rule.String())
+	}
+
+	next := rule.Next(from)
+
+	if start, hasStart := card.StartDate(); hasStart && hasDeadline {
+		shift := int(timelineDay(next) - timelineDay(deadline))
+		card.Properties.Get("start date").Set(start.AddDate(0, 0, shift).Format("2006-01-02"))
+	}
+
+	card.SetDeadlineDate(next)
+
+	card.SetStatus(StatusNotStarted)
+
+	// Capture the undo state immediately, as the Card may not be on the current Page
+	card.HandleUndos()
+
+	globals.EventLog.Log("Recurring card [%s] completed; next due %s.", false, card.Name(), next.Format("2006-01-02"))
+
+}
+
+// BlockedBy returns the Cards that block the Card, i.e. the Cards at the end of the blocking links starting from it.
+func (card *Card) BlockedBy() []*Card {
+	blockers := []*Card{}
+	for _, link := range card.Links {
+		if link.Blocking && link.Start == card && link.End.Valid {
+			blockers = append(blockers, link.End)
+		}
+	}
+	return blockers
+}
+
+// IncompleteBlockers returns the Cards blocking the Card that haven't been completed yet.
+func (card *Card) IncompleteBlockers() []*Card {
+	blockers := []*Card{}
+	for _, blocker := range card.BlockedBy() {
+		if blocker.Completable() && !blocker.Completed() {
+			blockers = append(blockers, blocker)
+		}
+	}
+	return blockers
+}
+
+// Blocked returns if the Card can't be completed because Cards blocking it haven't been completed yet.
+func (card *Card) Blocked() bool {
+	return card.Completable() && !card.Completed() && len(card.IncompleteBlockers()) > 0
+}
+
+// DurationEstimate returns the estimated number of days the Card takes to complete.
+func (card *Card) DurationEstimate() float64 {
+	if card.Properties.Has("duration") && card.Properties.Get("duration").IsNumber() {
+		return card.Properties.Get("duration").AsFloat()
+	}
+	return DefaultDurationEstimate
+}
+
+// RemainingDuration returns the estimated number of days left until the Card is complete.
+func (card *Card) RemainingDuration() float64 {
+	if !card.Completable() || card.Completed() {
+		return 0
+	}
+	return card.DurationEstimate()
+}
+
+// Priority returns the priority level of the Card; only complete-able Cards can have a priority.
+func (card *Card) Priority() int {
+	if !card.Completable() || !card.Properties.Has("priority") {
+		return PriorityNone
+	}
+	priority := int(card.Properties.Get("priority").AsFloat())
+	if priority < PriorityNone || priority > PriorityCritical {
+		return PriorityNone
+	}
+	return priority
+}
+
+// SetPriority sets the priority level of the Card, returning if it could be set (i.e. the Card is complete-able).
+func (card *Card) SetPriority(priority int) bool {
+
+	if !card.Completable() {
+		return false
+	}
+
+	if priority == PriorityNone && !card.Properties.Has("priority") {
+		return true
+	}
+
+	card.Properties.Get("priority").Set(float64(priority))
+	return true
+
+}
+
+// Tags returns the tags assigned to the Card.
+func (card *Card) Tags() []string {
+	if !card.Properties.Has("tags") {
+		return []string{}
+	}
+	return ParseTags(card.Properties.Get("tags").AsString())
+}
+
+// HasTags returns if the Card has all of the tags given.
+func (card *Card) HasTags(tags ...string) bool {
+	cardTags := card.Tags()
+	for _, tag := range tags {
+		if !TagsContain(cardTags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// Status returns the work status of a complete-able Card. This is derived from its completion level, but a Card that hasn't been completed
+// can also explicitly be marked as in progress through its "status" property.
+func (card *Card) Status() int {
+
+	if card.Completed() {
+		return StatusDone
+	}
+
+	if card.Properties.Has("status") && card.Properties.Get("status").AsString() == statusNames[StatusInProgress] {
+		return StatusInProgress
+	}
+
+	if card.CompletionLevel() > 0 {
+		return StatusInProgress
+	}
+
+	return StatusNotStarted
+
+}
+
+// SetStatus sets the work status of the Card, completing or un-completing it as necessary, and returns if it could be set (i.e. the Card is complete-able).
+func (card *Card) SetStatus(status int) bool {
+
+	if !card.Completable() {
+		return false
+	}
+
+	explicitStatus := ""
+
+	switch status {
+	case StatusNotStarted:
+		card.SetCompleted(false)
+	case StatusInProgress:
+		if card.Completed() {
+			card.SetCompleted(false)
+		}
+		if card.CompletionLevel() == 0 {
+			explicitStatus = statusNames[StatusInProgress]
+		}
+	case StatusDone:
+		card.SetCompleted(true)
+	}
+
+	// The property is emptied rather than removed so that undoing and redoing the change works.
+	if explicitStatus != "" || card.Properties.Has("status") {
+		card.Properties.Get("status").Set(explicitStatus)
+	}
+
+	return true
+
+}
+
+// SetTags sets the tags assigned to the Card. The property is emptied rather than removed when clearing tags so that undoing and redoing the change works.
+func (card *Card) SetTags(tags ...string) {
+
+	tags = ParseTags(TagsToString(tags))
+
+	if len(tags) == 0 && !card.Properties.Has("tags") {
+		return
+	}
+
+	card.Properties.Get("tags").Set(TagsToString(tags))
+
+}
+
 func (card *Card) Serialize() string {
 
 	data := "{}"
@@ -1572,6 +2128,9 @@ func (card *Card) Serialize() string {
 					jointPos = append(jointPos, p.Position)
 				}
 				dataOut, _ = sjson.Set(dataOut, "joints", jointPos)
+				if link.Blocking {
+					dataOut, _ = sjson.Set(dataOut, "blocking", true)
+				}
 				existingLinks += dataOut + ","
 
 			}
@@ -1981,6 +2540,8 @@ func (card *Card) ReceiveMessage(message *Message) {
 		card.Page.UpdateStacks = true
 	} else if message.Type == MessageUndoRedo {
 		globals.Hierarchy.AddCard(card)
+		// Undoing or redoing shouldn't count as completing a recurring Card
+		card.wasCompleted = card.Completable() && card.Completed()
 	} else if message.Type == MessageCardMoveStack {
 		// Card resized, let's update the stack
 
@@ -2035,8 +2596,12 @@ func (card *Card) SetContents(contentType string) {
 			card.Contents = NewSubPageContents(card)
 		case ContentTypeLink:
 			card.Contents = NewLinkContents(card)
-		// case ContentTypeTable:
-		// 	card.Contents = NewTableContents(card)
+		case ContentTypeTable:
+			card.Contents = NewTableContents(card)
+		case ContentTypeFrame:
+			card.Contents = NewFrameContents(card)
+		case ContentTypeCode:
+			card.Contents = NewCodeContents(card)
 		default:
 			panic("Creation of card contents that haven't been implemented: " + contentType)
 		}
@@ -2073,8 +2638,13 @@ func (card *Card) Collapse() {
 		card.UncollapsedSize = Point{card.Rect.W, card.Rect.H}
 	}
 
+	frame, isFrame := card.Contents.(*FrameContents)
+
 	switch card.Collapsed {
 	case CollapsedNone:
+		if isFrame {
+			frame.RecordHiddenMembers()
+		}
 		card.Collapsed = CollapsedShade
 	case CollapsedShade:
 		card.Collapsed = CollapsedNone
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// checklistItem matches a line of an inline checklist (i.e. "[ ] Task", "[x] Task", or "- [ ] Task").
+var checklistItem = regexp.MustCompile(`^(\s*)(?:[-*+]\s+)?\[([ xX])\](?:\s+(.*))?$`)
+
+// checklistLinkPrefix starts the links of inline checklist items' boxes; the rest of the link is the index of the item's line in the text.
+const checklistLinkPrefix = "checklist:"
+
+// ChecklistItem is a single item of an inline checklist in a Card's text.
+type ChecklistItem struct {
+	Line    int // The index of the line the item is on
+	Checked bool
+}
+
+// ParseChecklist returns the inline checklist items in the given text. Lines in Markdown code blocks aren't checklist items.
+func ParseChecklist(text string) []ChecklistItem {
+
+	items := []ChecklistItem{}
+
+	inCodeBlock := false
+
+	for i, line := range strings.Split(text, "\n") {
+
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+
+		if match := checklistItem.FindStringSubmatch(line); match != nil && !inCodeBlock {
+			items = append(items, ChecklistItem{Line: i, Checked: match[2] != " "})
+		}
+
+	}
+
+	return items
+
+}
+
+// ChecklistProgress returns how many of the inline checklist items in the given text are checked, and how many items there are in total.
+func ChecklistProgress(text string) (int, int) {
+
+	items := ParseChecklist(text)
+
+	checked := 0
+	for _, item := range items {
+		if item.Checked {
+			checked++
+		}
+	}
+
+	return checked, len(items)
+
+}
+
+// checklistCache holds the progress of a text's inline checklist, so that the text only has to be parsed again when it changes.
+type checklistCache struct {
+	text    string
+	parsed  bool
+	checked int
+	total   int
+}
+
+// Progress returns how many of the inline checklist items in the given text are checked, and how many items there are in total.
+func (cache *checklistCache) Progress(text string) (int, int) {
+
+	if !cache.parsed || cache.text != text {
+		cache.text = text
+		cache.checked, cache.total = ChecklistProgress(text)
+		cache.parsed = true
+	}
+
+	return cache.checked, cache.total
+
+}
+
+// ToggleChecklistItem returns the given text with the inline checklist item on the given line checked or unchecked.
+func ToggleChecklistItem(text string, line int) string {
+
+	lines := strings.Split(text, "\n")
+
+	if line < 0 || line >= len(lines) {
+		return text
+	}
+
+	match := checklistItem.FindStringSubmatchIndex(lines[line])
+	if match == nil {
+		return text
+	}
+
+	mark := "x"
+	if lines[line][match[4]] != ' ' {
+		mark = " "
+	}
+
+	lines[line] = lines[line][:match[4]] + mark + lines[line][match[5]:]
+
+	return strings.Join(lines, "\n")
+
+}
+
+// checklistBoxRun returns a run displaying an inline checklist item's box as a checkbox icon. The run links to the item's line so that
+// clicking on it can check the item off.
+func checklistBoxRun(line int, checked bool) TextRun {
+
+	icon := &sdl.Rect{48, 0, 32, 32}
+	if checked {
+		icon.Y = 32
+	}
+
+	return TextRun{Text: string(TextIconRune), Icon: icon, Link: checklistLinkPrefix + strconv.Itoa(line)}
+
+}
+
+// ChecklistRuns splits plain text into runs for rendering, with the boxes of inline checklist items displayed as checkboxes. If the text
+// has no checklist items, nil is returned instead.
+func ChecklistRuns(text string) []TextRun {
+
+	items := map[int]bool{}
+	for _, item := range ParseChecklist(text) {
+		items[item.Line] = item.Checked
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	runs := markdownRuns{}
+
+	lines := strings.Split(text, "\n")
+
+	for i, line := range lines {
+
+		if checked, isItem := items[i]; isItem {
+			match := checklistItem.FindStringSubmatch(line)
+			runs.Add(match[1], 0, "")
+			runs = append(runs, checklistBoxRun(i, checked))
+			runs.Add(" "+match[3], 0, "")
+		} else {
+			runs.Add(line, 0, "")
+		}
+
+		if i < len(lines)-1 {
+			runs.Add("\n", 0, "")
+		}
+
+	}
+
+	return runs
+
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseChecklist(t *testing.T) {
+
+	text := "Groceries\n[ ] Milk\n[x] Eggs\n- [X] Bread\n  * [ ] Butter\n+ [ ]\n[] Not an item\n[ ]Not one either\n```\n[ ] Code, not an item\n```\n[y] Nope"
+
+	want := []ChecklistItem{
+		{Line: 1, Checked: false},
+		{Line: 2, Checked: true},
+		{Line: 3, Checked: true},
+		{Line: 4, Checked: false},
+		{Line: 5, Checked: false},
+	}
+
+	if items := ParseChecklist(text); !reflect.DeepEqual(items, want) {
+		t.Errorf("ParseChecklist() = %+v, want %+v", items, want)
+	}
+
+	if checked, total := ChecklistProgress(text); checked != 2 || total != 5 {
+		t.Errorf("ChecklistProgress() = %d / %d, want 2 / 5", checked, total)
+	}
+
+	if checked, total := ChecklistProgress("No items here"); checked != 0 || total != 0 {
+		t.Errorf("ChecklistProgress() without items = %d / %d, want 0 / 0", checked, total)
+	}
+
+}
+
+func TestChecklistCache(t *testing.T) {
+
+	cache := &checklistCache{}
+
+	if checked, total := cache.Progress(""); checked != 0 || total != 0 {
+		t.Errorf("Progress(\"\") = %d / %d, want 0 / 0", checked, total)
+	}
+
+	if checked, total := cache.Progress("[x] a\n[ ] b"); checked != 1 || total != 2 {
+		t.Errorf("Progress() = %d / %d, want 1 / 2", checked, total)
+	}
+
+	if checked, total := cache.Progress("[x] a\n[x] b"); checked != 2 || total != 2 {
+		t.Errorf("Progress() after the text changed = %d / %d, want 2 / 2", checked, total)
+	}
+
+}
+
+func TestToggleChecklistItem(t *testing.T) {
+
+	text := "[ ] Milk\n  - [x] Eggs [ ] not a box\nNot an item"
+
+	tests := []struct {
+		line int
+		want string
+	}{
+		{0, "[x] Milk\n  - [x] Eggs [ ] not a box\nNot an item"},
+		{1, "[ ] Milk\n  - [ ] Eggs [ ] not a box\nNot an item"},
+		{2, text},
+		{3, text},
+		{-1, text},
+	}
+
+	for _, test := range tests {
+		if got := ToggleChecklistItem(text, test.line); got != test.want {
+			t.Errorf("ToggleChecklistItem(%d) = %q, want %q", test.line, got, test.want)
+		}
+	}
+
+	if twice := ToggleChecklistItem(ToggleChecklistItem(text, 0), 0); twice != text {
+		t.Errorf("toggling an item twice = %q, want %q", twice, text)
+	}
+
+}
+
+func TestChecklistRuns(t *testing.T) {
+
+	if runs := ChecklistRuns("No items\nhere"); runs != nil {
+		t.Errorf("ChecklistRuns() without items = %+v, want nil", runs)
+	}
+
+	runs := ChecklistRuns("[ ] a\nplain\n  - [x] b")
+
+	want := []TextRun{
+		checklistBoxRun(0, false),
+		{Text: " a\nplain\n  "},
+		checklistBoxRun(2, true),
+		{Text: " b"},
+	}
+
+	if !reflect.DeepEqual(runs, want) {
+		t.Errorf("ChecklistRuns() = %+v, want %+v", runs, want)
+	}
+
+	if runs[0].Link != checklistLinkPrefix+"0" || runs[2].Link != checklistLinkPrefix+"2" {
+		t.Errorf("checklist boxes link to %q and %q, want their lines", runs[0].Link, runs[2].Link)
+	}
+
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const CodeLanguagePlainText = "Plain Text"
+
+// CodeLanguage describes how to highlight code written in a programming language.
+type CodeLanguage struct {
+	Name         string
+	Keywords     []string
+	Types        []string // Built-in types and constants
+	LineComments []string
+	BlockComment []string // The start and end of block comments, if the language has them
+	Quotes       string   // Characters that start and end strings
+	Multiline    string   // Quote characters whose strings can span multiple lines
+	Preprocessor bool     // Whether lines starting with # are preprocessor directives (rather than comments)
+
+	keywordSet map[string]bool
+	typeSet    map[string]bool
+}
+
+var cKeywords = []string{"auto", "break", "case", "const", "continue", "default", "do", "else", "enum", "extern", "for", "goto", "if", "inline", "register", "return", "sizeof", "static", "struct", "switch", "typedef", "union", "volatile", "while", "class", "namespace", "template", "typename", "public", "private", "protected", "virtual", "override", "new", "delete", "this", "using", "try", "catch", "throw", "operator", "friend", "constexpr", "nullptr", "true", "false", "NULL"}
+var cTypes = []string{"void", "char", "short", "int", "long", "float", "double", "signed", "unsigned", "bool", "size_t", "int8_t", "int16_t", "int32_t", "int64_t", "uint8_t", "uint16_t", "uint32_t", "uint64_t", "std", "string", "vector"}
+
+var shaderKeywords = []string{"break", "case", "const", "continue", "default", "discard", "do", "else", "for", "if", "in", "inout", "out", "return", "struct", "switch", "uniform", "varying", "attribute", "layout", "precision", "highp", "mediump", "lowp", "flat", "smooth", "while", "true", "false"}
+
+// CodeLanguages is the list of languages Code Cards can highlight.
+var CodeLanguages = []*CodeLanguage{
+	{Name: CodeLanguagePlainText},
+	{
+		Name:         "C / C++",
+		Keywords:     cKeywords,
+		Types:        cTypes,
+		LineComments: []string{"//"},
+		BlockComment: []string{"/*", "*/"},
+		Quotes:       `"'`,
+		Preprocessor: true,
+	},
+	{
+		Name:         "C#",
+		Keywords:     []string{"abstract", "as", "base", "break", "case", "catch", "checked", "class", "const", "continue", "default", "delegate", "do", "else", "enum", "event", "explicit", "extern", "finally", "fixed", "for", "foreach", "get", "goto", "if", "implicit", "in", "interface", "internal", "is", "lock", "namespace", "new", "null", "operator", "out", "override", "params", "private", "protected", "public", "readonly", "ref", "return", "sealed", "set", "static", "struct", "switch", "this", "throw", "try", "typeof", "using", "var", "virtual", "void", "while", "async", "await", "true", "false"},
+		Types:        []string{"bool", "byte", "char", "decimal", "double", "float", "int", "long", "object", "sbyte", "short", "string", "uint", "ulong", "ushort", "Vector2", "Vector3", "Quaternion", "GameObject", "Transform"},
+		LineComments: []string{"//"},
+		BlockComment: []string{"/*", "*/"},
+		Quotes:       `"'`,
+		Preprocessor: true,
+	},
+	{
+		Name:         "Go",
+		Keywords:     []string{"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "for", "func", "go", "goto", "if", "import", "interface", "map", "package", "range", "return", "select", "struct", "switch", "type", "var", "true", "false", "nil", "iota"},
+		Types:        []string{"bool", "byte", "complex64", "complex128", "error", "float32", "float64", "int", "int8", "int16", "int32", "int64", "rune", "string", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "any"},
+		LineComments: []string{"//"},
+		BlockComment: []string{"/*", "*/"},
+		Quotes:       "\"'`",
+		Multiline:    "`",
+	},
+	{
+		Name:         "GDScript",
+		Keywords:     []string{"and", "as", "break", "class", "class_name", "const", "continue", "elif", "else", "enum", "export", "extends", "for", "func", "if", "in", "is", "match", "not", "onready", "or", "pass", "preload", "return", "self", "signal", "static", "tool", "var", "while", "yield", "await", "true", "false", "null"},
+		Types:        []string{"bool", "int", "float", "String", "Vector2", "Vector3", "Color", "Array", "Dictionary", "Node", "Node2D", "Spatial", "Object"},
+		LineComments: []string{"#"},
+		Quotes:       `"'`,
+	},
+	{
+		Name:         "GLSL",
+		Keywords:     shaderKeywords,
+		Types:        []string{"void", "bool", "int", "uint", "float", "double", "vec2", "vec3", "vec4", "ivec2", "ivec3", "ivec4", "bvec2", "bvec3", "bvec4", "mat2", "mat3", "mat4", "sampler2D", "samplerCube", "sampler3D", "gl_Position", "gl_FragColor", "gl_FragCoord", "gl_VertexID"},
+		LineComments: []string{"//"},
+		BlockComment: []string{"/*", "*/"},
+		Quotes:       `"`,
+		Preprocessor: true,
+	},
+	{
+		Name:         "HLSL",
+		Keywords:     append([]string{"cbuffer", "register", "packoffset", "technique", "pass", "static", "extern", "shared", "groupshared", "numthreads"}, shaderKeywords...),
+		Types:        []string{"void", "bool", "int", "uint", "half", "float", "double", "float2", "float3", "float4", "half2", "half3", "half4", "int2", "int3", "int4", "float2x2", "float3x3", "float4x4", "Texture2D", "TextureCube", "SamplerState", "sampler2D"},
+		LineComments: []string{"//"},
+		BlockComment: []string{"/*", "*/"},
+		Quotes:       `"`,
+		Preprocessor: true,
+	},
+	{
+		Name:         "Java",
+		Keywords:     []string{"abstract", "assert", "break", "case", "catch", "class", "const", "continue", "default", "do", "else", "enum", "extends", "final", "finally", "for", "goto", "if", "implements", "import", "instanceof", "interface", "native", "new", "package", "private", "protected", "public", "return", "static", "super", "switch", "synchronized", "this", "throw", "throws", "try", "var", "void", "volatile", "while", "true", "false", "null"},
+		Types:        []string{"boolean", "byte", "char", "double", "float", "int", "long", "short", "String", "Object", "Integer", "List", "Map"},
+		LineComments: []string{"//"},
+		BlockComment: []string{"/*", "*/"},
+		Quotes:       `"'`,
+	},
+	{
+		Name:         "JavaScript",
+		Keywords:     []string{"async", "await", "break", "case", "catch", "class", "const", "continue", "debugger", "default", "delete", "do", "else", "export", "extends", "finally", "for", "function", "if", "import", "in", "instanceof", "let", "new", "of", "return", "static", "super", "switch", "this", "throw", "try", "typeof", "var", "void", "while", "yield", "true", "false", "null", "undefined"},
+		Types:        []string{"Array", "Boolean", "Date", "Error", "JSON", "Math", "Number", "Object", "Promise", "String", "console", "window", "document"},
+		LineComments: []string{"//"},
+		BlockComment: []string{"/*", "*/"},
+		Quotes:       "\"'`",
+		Multiline:    "`",
+	},
+	{
+		Name:         "Lua",
+		Keywords:     []string{"and", "break", "do", "else", "elseif", "end", "for", "function", "goto", "if", "in", "local", "not", "or", "repeat", "return", "then", "until", "while", "true", "false", "nil"},
+		Types:        []string{"self", "math", "string", "table", "print", "pairs", "ipairs", "require"},
+		LineComments: []string{"--"},
+		BlockComment: []string{"--[[", "]]"},
+		Quotes:       `"'`,
+	},
+	{
+		Name:         "Python",
+		Keywords:     []string{"and", "as", "assert", "async", "await", "break", "class", "continue", "def", "del", "elif", "else", "except", "finally", "for", "from", "global", "if", "import", "in", "is", "lambda", "nonlocal", "not", "or", "pass", "raise", "return", "try", "while", "with", "yield", "True", "False", "None"},
+		Types:        []string{"bool", "bytes", "dict", "float", "int", "list", "object", "set", "str", "tuple", "self", "print", "len", "range"},
+		LineComments: []string{"#"},
+		BlockComment: []string{`"""`, `"""`},
+		Quotes:       `"'`,
+	},
+	{
+		Name:         "Rust",
+		Keywords:     []string{"as", "async", "await", "break", "const", "continue", "crate", "dyn", "else", "enum", "extern", "fn", "for", "if", "impl", "in", "let", "loop", "match", "mod", "move", "mut", "pub", "ref", "return", "self", "Self", "static", "struct", "super", "trait", "type", "unsafe", "use", "where", "while", "true", "false"},
+		Types:        []string{"bool", "char", "f32", "f64", "i8", "i16", "i32", "i64", "i128", "isize", "u8", "u16", "u32", "u64", "u128", "usize", "str", "String", "Vec", "Option", "Result", "Box", "Some", "None", "Ok", "Err"},
+		LineComments: []string{"//"},
+		BlockComment: []string{"/*", "*/"},
+		Quotes:       `"`,
+	},
+	{
+		Name:         "Shell",
+		Keywords:     []string{"case", "do", "done", "elif", "else", "esac", "export", "fi", "for", "function", "if", "in", "local", "return", "then", "until", "while", "echo", "cd", "source", "exit"},
+		LineComments: []string{"#"},
+		Quotes:       `"'`,
+	},
+	{
+		Name:         "TypeScript",
+		Keywords:     []string{"abstract", "as", "async", "await", "break", "case", "catch", "class", "const", "continue", "declare", "default", "delete", "do", "else", "enum", "export", "extends", "finally", "for", "from", "function", "if", "implements", "import", "in", "instanceof", "interface", "keyof", "let", "namespace", "new", "of", "private", "protected", "public", "readonly", "return", "static", "super", "switch", "this", "throw", "try", "type", "typeof", "var", "void", "while", "yield", "true", "false", "null", "undefined"},
+		Types:        []string{"any", "boolean", "never", "number", "object", "string", "symbol", "unknown", "Array", "Promise", "Record", "Partial", "console"},
+		LineComments: []string{"//"},
+		BlockComment: []string{"/*", "*/"},
+		Quotes:       "\"'`",
+		Multiline:    "`",
+	},
+}
+
+// CodeLanguageNames returns the names of all of the languages Code Cards can highlight.
+func CodeLanguageNames() []string {
+	names := []string{}
+	for _, lang := range CodeLanguages {
+		names = append(names, lang.Name)
+	}
+	return names
+}
+
+// CodeLanguageByName returns the CodeLanguage with the given name, or Plain Text if there's no such language.
+func CodeLanguageByName(name string) *CodeLanguage {
+	for _, lang := range CodeLanguages {
+		if lang.Name == name {
+			return lang
+		}
+	}
+	return CodeLanguages[0]
+}
+
+// Hues of the different kinds of highlighted code.
+const (
+	codeHueKeyword  = 280
+	codeHueType     = 195
+	codeHueFunction = 45
+	codeHueString   = 110
+	codeHueNumber   = 20
+)
+
+// codeSyntaxColor returns the color highlighted code of the given hue is drawn in; it's light or dark, depending on the theme's font color.
+func codeSyntaxColor(hue float64) Color {
+	if _, _, v := getThemeColor(GUIFontColor).HSV(); v > 0.5 {
+		return NewColorFromHSV(hue, 0.4, 1)
+	}
+	return NewColorFromHSV(hue, 0.85, 0.45)
+}
+
+// HighlightCode splits the given code into runs of text colored according to the syntax of the given language.
+func HighlightCode(code string, language *CodeLanguage) []TextRun {
+
+	runs := []TextRun{}
+
+	fontColor := getThemeColor(GUIFontColor)
+
+	if language.keywordSet == nil {
+		language.keywordSet = map[string]bool{}
+		for _, k := range language.Keywords {
+			language.keywordSet[k] = true
+		}
+		language.typeSet = map[string]bool{}
+		for _, t := range language.Types {
+			language.typeSet[t] = true
+		}
+	}
+
+	commentColor := fontColor.Clone()
+	commentColor[3] = 140
+
+	keywordColor := codeSyntaxColor(codeHueKeyword)
+	typeColor := codeSyntaxColor(codeHueType)
+	functionColor := codeSyntaxColor(codeHueFunction)
+	stringColor := codeSyntaxColor(codeHueString)
+	numberColor := codeSyntaxColor(codeHueNumber)
+
+	chars := []rune(code)
+
+	// Runs are tracked by their range in the text until they're finished, so that they aren't built up a character at a time
+	runStart, runEnd := 0, 0
+	var runColor Color
+
+	flush := func() {
+		if runEnd > runStart {
+			runs = append(runs, TextRun{Text: string(chars[runStart:runEnd]), Color: runColor})
+		}
+	}
+
+	add := func(start, end int, color Color) {
+		if end <= start {
+			return
+		}
+		if runEnd > runStart && runEnd == start && runColor.Equals(color) {
+			runEnd = end
+			return
+		}
+		flush()
+		runStart, runEnd, runColor = start, end, color
+	}
+
+	isWordChar := func(c rune) bool {
+		return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_'
+	}
+
+	// hasPrefix compares the prefix against the text in place, as this is done at nearly every position in the text
+	hasPrefix := func(index int, prefix string) bool {
+		if prefix == "" {
+			return false
+		}
+		for _, c := range prefix {
+			if index >= len(chars) || chars[index] != c {
+				return false
+			}
+			index++
+		}
+		return true
+	}
+
+	// findFrom returns the index just past the given ending, starting from the given index, or the end of the text if it's not found
+	findFrom := func(index int, ending string, stopAtNewline bool) int {
+		for i := index; i < len(chars); i++ {
+			if stopAtNewline && chars[i] == '\n' {
+				return i
+			}
+			if hasPrefix(i, ending) {
+				return i + utf8.RuneCountInString(ending)
+			}
+		}
+		return len(chars)
+	}
+
+	lineStart := true
+
+	for i := 0; i < len(chars); {
+
+		c := chars[i]
+		start := i
+
+		if c == '\n' {
+			lineStart = true
+			add(i, i+1, fontColor)
+			i++
+			continue
+		}
+
+		if language.Name == CodeLanguagePlainText {
+			add(i, i+1, fontColor)
+			i++
+			continue
+		}
+
+		if unicode.IsSpace(c) {
+			add(i, i+1, fontColor)
+			i++
+			continue
+		}
+
+		wasLineStart := lineStart
+		lineStart = false
+
+		lineComment := false
+		for _, lc := range language.LineComments {
+			if hasPrefix(i, lc) {
+				lineComment = true
+			}
+		}
+
+		switch {
+
+		case len(language.BlockComment) == 2 && hasPrefix(i, language.BlockComment[0]):
+			i = findFrom(i+utf8.RuneCountInString(language.BlockComment[0]), language.BlockComment[1], false)
+			add(start, i, commentColor)
+
+		case language.Preprocessor && wasLineStart && c == '#':
+			i = findFrom(i, "\n", true)
+			add(start, i, keywordColor)
+
+		case lineComment:
+			i = findFrom(i, "\n", true)
+			add(start, i, commentColor)
+
+		case strings.ContainsRune(language.Quotes, c):
+			multiline := strings.ContainsRune(language.Multiline, c)
+			i++
+			for i < len(chars) && chars[i] != c && (multiline || chars[i] != '\n') {
+				if chars[i] == '\\' && c != '`' {
+					i++
+				}
+				i++
+			}
+			if i < len(chars) && chars[i] == c {
+				i++
+			}
+			if i > len(chars) {
+				i = len(chars)
+			}
+			add(start, i, stringColor)
+
+		case unicode.IsDigit(c):
+			for i < len(chars) && (isWordChar(chars[i]) || chars[i] == '.') {
+				i++
+			}
+			add(start, i, numberColor)
+
+		case isWordChar(c):
+			for i < len(chars) && isWordChar(chars[i]) {
+				i++
+			}
+			word := string(chars[start:i])
+			if language.keywordSet[word] {
+				add(start, i, keywordColor)
+			} else if language.typeSet[word] {
+				add(start, i, typeColor)
+			} else if i < len(chars) && chars[i] == '(' {
+				add(start, i, functionColor)
+			} else {
+				add(start, i, fontColor)
+			}
+
+		default:
+			add(i, i+1, fontColor)
+			i++
+
+		}
+
+	}
+
+	flush()
+
+	return runs
+
+}
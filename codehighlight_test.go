@@ -0,0 +1,185 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// codeTestRun is a highlighted run of text, with its color named by the kind of code it highlights.
+type codeTestRun struct {
+	Text string
+	Kind string
+}
+
+// highlightTestCode highlights the code in the given language, using a theme with a dark font color.
+func highlightTestCode(t *testing.T, code string, languageName string) []codeTestRun {
+
+	settings, colors := globals.Settings, guiColors
+	defer func() { globals.Settings, guiColors = settings, colors }()
+
+	globals.Settings = NewProperties()
+	globals.Settings.Get(SettingsTheme).Set("Test")
+	guiColors = map[string]map[string]Color{"Test": {GUIFontColor: NewColor(20, 20, 20, 255)}}
+
+	language := CodeLanguageByName(languageName)
+	if language.Name != languageName {
+		t.Fatalf("there's no %s language", languageName)
+	}
+
+	kinds := map[string]Color{
+		"text":     getThemeColor(GUIFontColor),
+		"comment":  NewColor(20, 20, 20, 140),
+		"keyword":  codeSyntaxColor(codeHueKeyword),
+		"type":     codeSyntaxColor(codeHueType),
+		"function": codeSyntaxColor(codeHueFunction),
+		"string":   codeSyntaxColor(codeHueString),
+		"number":   codeSyntaxColor(codeHueNumber),
+	}
+
+	runs := []codeTestRun{}
+	highlighted := strings.Builder{}
+
+	for _, run := range HighlightCode(code, language) {
+
+		kind := "unknown"
+		for name, color := range kinds {
+			if run.Color.Equals(color) {
+				kind = name
+			}
+		}
+
+		runs = append(runs, codeTestRun{run.Text, kind})
+		highlighted.WriteString(run.Text)
+
+	}
+
+	if highlighted.String() != code {
+		t.Errorf("highlighting %q in %s produced %q", code, languageName, highlighted.String())
+	}
+
+	return runs
+
+}
+
+func TestHighlightCode(t *testing.T) {
+
+	tests := []struct {
+		language string
+		code     string
+		want     []codeTestRun
+	}{
+		{
+			CodeLanguagePlainText,
+			"func main() {\n\t// not code\n}",
+			[]codeTestRun{{"func main() {\n\t// not code\n}", "text"}},
+		},
+		{
+			"Go",
+			"func main() {\n\tx := 3.5 // half\n}",
+			[]codeTestRun{
+				{"func", "keyword"}, {" ", "text"}, {"main", "function"}, {"() {\n\tx := ", "text"}, {"3.5", "number"}, {" ", "text"},
+				{"// half", "comment"}, {"\n}", "text"},
+			},
+		},
+		{
+			"Go",
+			"var s string = \"a \\\"quoted\\\" word\" + `raw\nstring`",
+			[]codeTestRun{
+				{"var", "keyword"}, {" s ", "text"}, {"string", "type"}, {" = ", "text"}, {"\"a \\\"quoted\\\" word\"", "string"}, {" + ", "text"},
+				{"`raw\nstring`", "string"},
+			},
+		},
+		{
+			"Go",
+			"/* block\ncomment */ nil",
+			[]codeTestRun{{"/* block\ncomment */", "comment"}, {" ", "text"}, {"nil", "keyword"}},
+		},
+
+		// Strings that aren't closed end at the end of the line, unless they can span lines
+		{
+			"Go",
+			"\"open\nx",
+			[]codeTestRun{{"\"open", "string"}, {"\nx", "text"}},
+		},
+		{
+			"Go",
+			"`open\nx",
+			[]codeTestRun{{"`open\nx", "string"}},
+		},
+		{
+			"Go",
+			"\"trailing\\",
+			[]codeTestRun{{"\"trailing\\", "string"}},
+		},
+
+		// Preprocessor directives only count at the start of a line
+		{
+			"C / C++",
+			"#include <stdio.h>\n  #define X 1\nint a # b;",
+			[]codeTestRun{
+				{"#include <stdio.h>", "keyword"}, {"\n  ", "text"}, {"#define X 1", "keyword"}, {"\n", "text"}, {"int", "type"},
+				{" a # b;", "text"},
+			},
+		},
+		{
+			"Python",
+			"def f(): # comment\n    \"\"\"doc\nstring\"\"\"",
+			[]codeTestRun{
+				{"def", "keyword"}, {" ", "text"}, {"f", "function"}, {"(): ", "text"}, {"# comment", "comment"}, {"\n    ", "text"},
+				{"\"\"\"doc\nstring\"\"\"", "comment"},
+			},
+		},
+		{
+			"Lua",
+			"--[[ block ]] -- line\nlocal",
+			[]codeTestRun{{"--[[ block ]]", "comment"}, {" ", "text"}, {"-- line", "comment"}, {"\n", "text"}, {"local", "keyword"}},
+		},
+
+		// Text isn't only ASCII
+		{
+			"Go",
+			"héllo := \"wörld\" // ☃",
+			[]codeTestRun{{"héllo := ", "text"}, {"\"wörld\"", "string"}, {" ", "text"}, {"// ☃", "comment"}},
+		},
+		{
+			"Go",
+			"",
+			[]codeTestRun{},
+		},
+	}
+
+	for _, test := range tests {
+		if runs := highlightTestCode(t, test.code, test.language); !reflect.DeepEqual(runs, test.want) {
+			t.Errorf("highlighting %q in %s:\n got  %q\n want %q", test.code, test.language, runs, test.want)
+		}
+	}
+
+}
+
+func TestHighlightCodeLargeInput(t *testing.T) {
+
+	// Highlighting used to take time proportional to the square of the code's length; this should be quick
+	code := strings.Repeat("for i := 0; i < 10; i++ { fmt.Println(\"line\", i) } // comment\n", 5000)
+
+	runs := highlightTestCode(t, code, "Go")
+
+	if len(runs) == 0 {
+		t.Errorf("no runs were highlighted")
+	}
+
+}
+
+func TestCodeLanguageByName(t *testing.T) {
+
+	for _, name := range CodeLanguageNames() {
+		if language := CodeLanguageByName(name); language.Name != name {
+			t.Errorf("CodeLanguageByName(%q) = %q", name, language.Name)
+		}
+	}
+
+	if language := CodeLanguageByName("Not a Language"); language.Name != CodeLanguagePlainText {
+		t.Errorf("CodeLanguageByName() of an unknown language = %q, want %q", language.Name, CodeLanguagePlainText)
+	}
+
+}
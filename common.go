@@ -1,10 +1,12 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -296,6 +298,77 @@ func formatTime(t time.Duration, showMilliseconds bool) string {
 
 }
 
+// ParseTimerDuration parses a duration given as seconds ("90"), minutes and seconds ("01:30"), or hours, minutes, and seconds ("1:00:00"),
+// optionally preceded by a number of days ("2d", "1d 12:00:00").
+func ParseTimerDuration(text string) (time.Duration, error) {
+
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	duration := time.Duration(0)
+
+	if i := strings.Index(text, "d"); i >= 0 {
+
+		days, err := strconv.Atoi(strings.TrimSpace(text[:i]))
+		if err != nil || days < 0 {
+			return 0, errors.New("invalid number of days: " + text[:i])
+		}
+
+		duration += time.Duration(days) * time.Hour * 24
+		text = strings.TrimSpace(text[i+1:])
+
+	}
+
+	if text == "" {
+		return duration, nil
+	}
+
+	units := strings.Split(text, ":")
+	if len(units) > 3 {
+		return 0, errors.New("durations should be in hh:mm:ss format: " + text)
+	}
+
+	multiplier := time.Second
+
+	for i := len(units) - 1; i >= 0; i-- {
+
+		// Empty units (e.g. the minutes in ":30") are taken as 0
+		if unit := strings.TrimSpace(units[i]); unit != "" {
+
+			value, err := strconv.Atoi(unit)
+			if err != nil || value < 0 {
+				return 0, errors.New("invalid duration: " + text)
+			}
+
+			duration += time.Duration(value) * multiplier
+
+		}
+
+		multiplier *= 60
+
+	}
+
+	return duration, nil
+
+}
+
+// formatTimerDuration formats a duration as minutes and seconds ("05:00"), adding hours ("1:05:00") and days ("2d 01:05:00") as
+// necessary.
+func formatTimerDuration(t time.Duration) string {
+
+	seconds := int64(t / time.Second)
+	days, hours, minutes := seconds/86400, (seconds/3600)%24, (seconds/60)%60
+	seconds %= 60
+
+	if days > 0 {
+		return fmt.Sprintf("%dd %02d:%02d:%02d", days, hours, minutes, seconds)
+	} else if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+
+	return fmt.Sprintf("%02d:%02d", minutes, seconds)
+
+}
+
 func WriteImageToTemp(clipboardImg []byte) (string, error) {
 
 	var file *os.File
@@ -323,6 +396,103 @@ func WriteImageToTemp(clipboardImg []byte) (string, error) {
 
 }
 
+// DefaultFallbackFonts returns the fonts used by default for characters the main font doesn't have, one path per line: the bundled symbol font,
+// followed by whichever CJK and emoji fonts the OS ships with.
+func DefaultFallbackFonts() string {
+
+	fonts := []string{"assets/DejaVuSans-Bold.ttf"}
+
+	systemFonts := []string{}
+
+	switch runtime.GOOS {
+	case "windows":
+		fontDir := filepath.Join(os.Getenv("WINDIR"), "Fonts")
+		for _, font := range []string{"YuGothB.ttc", "msyhbd.ttc", "malgunbd.ttf", "seguiemj.ttf", "seguisym.ttf"} {
+			systemFonts = append(systemFonts, filepath.Join(fontDir, font))
+		}
+	case "darwin":
+		systemFonts = []string{
+			"/System/Library/Fonts/Hiragino Sans GB.ttc",
+			"/System/Library/Fonts/AppleSDGothicNeo.ttc",
+			"/System/Library/Fonts/Apple Color Emoji.ttc",
+			"/System/Library/Fonts/Apple Symbols.ttf",
+		}
+	default:
+		systemFonts = []string{
+			"/usr/share/fonts/opentype/noto/NotoSansCJK-Bold.ttc",
+			"/usr/share/fonts/noto-cjk/NotoSansCJK-Bold.ttc",
+			"/usr/share/fonts/google-noto-cjk/NotoSansCJK-Bold.ttc",
+			"/usr/share/fonts/truetype/noto/NotoColorEmoji.ttf",
+			"/usr/share/fonts/noto/NotoColorEmoji.ttf",
+			"/usr/share/fonts/google-noto-emoji/NotoColorEmoji.ttf",
+		}
+	}
+
+	for _, font := range systemFonts {
+		if FileExists(font) {
+			fonts = append(fonts, font)
+		}
+	}
+
+	return strings.Join(fonts, "\n")
+
+}
+
+// FallbackFontPaths returns the paths of the fallback fonts set in the settings, in the order they're consulted.
+func FallbackFontPaths() []string {
+
+	paths := []string{}
+
+	for _, path := range strings.Split(globals.Settings.Get(SettingsFallbackFonts).AsString(), "\n") {
+
+		path = strings.TrimSpace(path)
+
+		if path == "" {
+			continue
+		}
+
+		if !filepath.IsAbs(path) {
+			path = LocalRelativePath(path)
+		}
+
+		paths = append(paths, path)
+
+	}
+
+	return paths
+
+}
+
+// loadFallbackFonts (re)loads the fallback fonts, logging the ones that can't be loaded.
+func loadFallbackFonts(paths []string) {
+
+	for _, font := range globals.FallbackFonts {
+		font.Close()
+	}
+
+	globals.FallbackFonts = []*ttf.Font{}
+
+	for _, path := range paths {
+
+		if !FileExists(path) {
+			globals.EventLog.Log(`ERROR: Fallback font "%s" doesn't exist. Please check path.`, false, path)
+			continue
+		}
+
+		font, err := ttf.OpenFont(path, 48)
+		if err != nil {
+			globals.EventLog.Log(`ERROR: Fallback font "%s" could not be loaded: %s`, false, path, err.Error())
+			continue
+		}
+
+		font.SetHinting(ttf.HINTING_NORMAL)
+
+		globals.FallbackFonts = append(globals.FallbackFonts, font)
+
+	}
+
+}
+
 func HandleFontReload() {
 
 	if globals.TriggerReloadFonts {
@@ -338,7 +508,12 @@ func HandleFontReload() {
 			}
 		}
 
-		if globals.LoadedFontPath != fontPath {
+		fallbackPaths := FallbackFontPaths()
+
+		fontChanged := globals.LoadedFontPath != fontPath
+		fallbacksChanged := globals.LoadedFallbacks != strings.Join(fallbackPaths, "\n") || globals.FallbackFonts == nil
+
+		if fontChanged {
 
 			if globals.LoadedFontPath != "" {
 				if customFontPath != "" {
@@ -368,6 +543,26 @@ func HandleFontReload() {
 
 			globals.LoadedFontPath = fontPath
 
+			// The monospace font (used for code) isn't customizable, so it only needs to be loaded once
+			if globals.MonospaceFont == nil {
+				monospaceFont, err := ttf.OpenFont(LocalRelativePath("assets/GoMono-Bold.ttf"), 48)
+				if err != nil {
+					globals.EventLog.Log("ERROR: Monospace font could not be loaded; the default font will be used for code instead: %s", false, err.Error())
+				} else {
+					monospaceFont.SetHinting(ttf.HINTING_NORMAL)
+					globals.MonospaceFont = monospaceFont
+				}
+			}
+
+		}
+
+		if fallbacksChanged {
+			loadFallbackFonts(fallbackPaths)
+			globals.LoadedFallbacks = strings.Join(fallbackPaths, "\n")
+		}
+
+		if fontChanged || fallbacksChanged {
+
 			globals.TextRenderer.DestroyGlyphs()
 
 			// We have to refresh the font RenderTextures
@@ -675,6 +870,22 @@ func ThickLine(start, end Point, thickness int32, color Color) {
 	gfx.ThickLineRGBA(globals.Renderer, int32(start.X), int32(start.Y), int32(end.X), int32(end.Y), thickness, color[0], color[1], color[2], color[3])
 }
 
+// DrawLockIcon draws a small padlock with its top-left corner at the position specified (in screen coordinates).
+func DrawLockIcon(pos Point, color Color) {
+
+	outlineColor := getThemeColor(GUIFontColor)
+
+	// Shackle
+	ThickRect(int32(pos.X+6), int32(pos.Y+2), 12, 12, 6, outlineColor)
+	ThickRect(int32(pos.X+6), int32(pos.Y+2), 12, 12, 2, color)
+
+	// Body
+	FillRect(pos.X+1, pos.Y+9, 22, 16, outlineColor)
+	FillRect(pos.X+3, pos.Y+11, 18, 12, color)
+	FillRect(pos.X+11, pos.Y+14, 2, 6, outlineColor)
+
+}
+
 // DrawLabel draws a small paper-like label of the specified text at the X and Y position specified.
 func DrawLabel(pos Point, text string) {
 
@@ -872,4 +1083,5 @@ const RegexNoNewlines = `[^\n]`
 const RegexOnlyDigits = `[\d]`
 const RegexNoDigits = `[^\d]`
 const RegexOnlyDigitsAndColon = `[\d:]`
+const RegexDuration = `[\d:dD ]`
 const RegexHex = `[#a-fA-F\d]`
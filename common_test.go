@@ -0,0 +1,127 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTimerDuration(t *testing.T) {
+
+	tests := []struct {
+		text string
+		want time.Duration
+	}{
+		{"", 0},
+		{"0", 0},
+		{"90", time.Second * 90},
+		{"01:30", time.Minute + time.Second*30},
+		{"1:30", time.Minute + time.Second*30},
+		{":30", time.Second * 30},
+		{"5:", time.Minute * 5},
+		{"1:00:00", time.Hour},
+		{"100:00:00", time.Hour * 100},
+		{" 1:05:09 ", time.Hour + time.Minute*5 + time.Second*9},
+		{"2d", time.Hour * 48},
+		{"2D", time.Hour * 48},
+		{"1d 12:00:00", time.Hour * 36},
+		{"1d12:00:00", time.Hour * 36},
+		{"1d 30", time.Hour*24 + time.Second*30},
+	}
+
+	for _, test := range tests {
+
+		duration, err := ParseTimerDuration(test.text)
+
+		if err != nil {
+			t.Errorf("ParseTimerDuration(%q) returned error: %s", test.text, err)
+		} else if duration != test.want {
+			t.Errorf("ParseTimerDuration(%q) = %s, want %s", test.text, duration, test.want)
+		}
+
+	}
+
+	for _, text := range []string{"abc", "1:2:3:4", "-5", "1:-5", "1.5", "xd", "-1d", "1d 2d", "1h"} {
+		if _, err := ParseTimerDuration(text); err == nil {
+			t.Errorf("ParseTimerDuration(%q) should have returned an error", text)
+		}
+	}
+
+}
+
+func TestFormatTimerDuration(t *testing.T) {
+
+	tests := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{0, "00:00"},
+		{time.Second * 90, "01:30"},
+		{time.Second*90 + time.Millisecond*999, "01:30"},
+		{time.Hour, "1:00:00"},
+		{time.Hour*23 + time.Minute*59 + time.Second*59, "23:59:59"},
+		{time.Hour * 24, "1d 00:00:00"},
+		{time.Hour*36 + time.Minute*5, "1d 12:05:00"},
+	}
+
+	for _, test := range tests {
+
+		text := formatTimerDuration(test.duration)
+
+		if text != test.want {
+			t.Errorf("formatTimerDuration(%s) = %q, want %q", test.duration, text, test.want)
+		}
+
+		// Whatever's shown can be typed back in
+		if parsed, err := ParseTimerDuration(text); err != nil || parsed != test.duration.Truncate(time.Second) {
+			t.Errorf("ParseTimerDuration(%q) = %s, %v, want %s", text, parsed, err, test.duration.Truncate(time.Second))
+		}
+
+	}
+
+}
+
+func TestFallbackFontPaths(t *testing.T) {
+
+	settings := globals.Settings
+	defer func() { globals.Settings = settings }()
+
+	globals.Settings = NewProperties()
+
+	absolute, _ := filepath.Abs(filepath.Join("fonts", "NotoColorEmoji.ttf"))
+
+	// Blank lines are skipped, and relative paths are relative to MasterPlan's directory
+	globals.Settings.Get(SettingsFallbackFonts).Set("assets/DejaVuSans-Bold.ttf\n\n  " + absolute + "  \r\n")
+
+	want := []string{LocalRelativePath("assets/DejaVuSans-Bold.ttf"), absolute}
+
+	if paths := FallbackFontPaths(); !reflect.DeepEqual(paths, want) {
+		t.Errorf("FallbackFontPaths() = %q, want %q", paths, want)
+	}
+
+	globals.Settings.Get(SettingsFallbackFonts).Set("")
+
+	if paths := FallbackFontPaths(); len(paths) != 0 {
+		t.Errorf("FallbackFontPaths() without fallback fonts = %q, want none", paths)
+	}
+
+}
+
+func TestDefaultFallbackFonts(t *testing.T) {
+
+	fonts := strings.Split(DefaultFallbackFonts(), "\n")
+
+	// The bundled font always comes first; the OS's fonts are only listed if they're there
+	if fonts[0] != "assets/DejaVuSans-Bold.ttf" {
+		t.Errorf("DefaultFallbackFonts() starts with %q, want the bundled font", fonts[0])
+	}
+
+	for _, font := range fonts[1:] {
+		if !FileExists(font) {
+			t.Errorf("DefaultFallbackFonts() lists %q, which doesn't exist", font)
+		}
+	}
+
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"os/exec"
 	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
 	"github.com/veandco/go-sdl2/sdl"
+	"golang.design/x/clipboard"
 )
 
 const (
@@ -28,6 +30,8 @@ const (
 	ContentTypeSubpage  = "Sub-Page"
 	ContentTypeLink     = "Link"
 	ContentTypeTable    = "Table"
+	ContentTypeFrame    = "Frame"
+	ContentTypeCode     = "Code"
 )
 const (
 	TriggerTypeSet = iota
@@ -46,6 +50,8 @@ var icons map[string]*sdl.Rect = map[string]*sdl.Rect{
 	ContentTypeSubpage:  {48, 256, 32, 32},
 	ContentTypeLink:     {112, 256, 32, 32},
 	ContentTypeTable:    {176, 224, 32, 32},
+	ContentTypeFrame:    {176, 288, 32, 32},
+	ContentTypeCode:     {144, 288, 32, 32},
 }
 
 var contentOrder = map[string]int{
@@ -59,6 +65,8 @@ var contentOrder = map[string]int{
 	ContentTypeSubpage:  7,
 	ContentTypeLink:     8,
 	ContentTypeTable:    9,
+	ContentTypeFrame:    10,
+	ContentTypeCode:     11,
 }
 
 type Contents interface {
@@ -113,6 +121,7 @@ type CheckboxContents struct {
 	ParentOf                     []*Card
 	Linked                       []*Card
 	PercentageOfChildrenComplete float32
+	checklist                    checklistCache
 	// URLButtons                   *URLButtons
 }
 
@@ -158,6 +167,8 @@ func NewCheckboxContents(card *Card) *CheckboxContents {
 
 	cc.Label = NewLabel("New Checkbox", nil, true, AlignLeft)
 	cc.Label.Editable = true
+	cc.Label.Checklist = true
+	cc.Label.SpellCheck = true
 	cc.Label.Property = card.Properties.Get("description")
 
 	cc.Label.OnChange = func() {
@@ -201,7 +212,9 @@ func (cc *CheckboxContents) Update() {
 		if kb.Pressed(KBCheckboxToggleCompletion) {
 			kb.Shortcuts[KBCheckboxToggleCompletion].ConsumeKeys()
 			prop := cc.Card.Properties.Get("checked")
-			prop.Set(!prop.AsBool())
+			if !cc.Card.Blocked() {
+				prop.Set(!prop.AsBool())
+			}
 		} else if kb.Pressed(KBCheckboxEditText) {
 			kb.Shortcuts[KBCheckboxEditText].ConsumeKeys()
 			cc.Label.BeginEditing()
@@ -216,9 +229,11 @@ func (cc *CheckboxContents) Draw() {
 	maximum := float32(0)
 
 	dependentCards := cc.DependentCards()
-	cc.Checkbox.MultiCheckbox = len(dependentCards) > 0
+	checkedItems, items := cc.ChecklistProgress()
+	hasSubtasks := len(dependentCards) > 0 || items > 0
+	cc.Checkbox.MultiCheckbox = hasSubtasks
 
-	if len(dependentCards) > 0 {
+	if hasSubtasks {
 
 		for _, c := range dependentCards {
 			if c.Numberable() {
@@ -229,7 +244,18 @@ func (cc *CheckboxContents) Draw() {
 			}
 		}
 
-		cc.Card.Properties.Get("checked").Set(completed >= maximum)
+		// Inline checklist items count as sub-tasks as well
+		completed += float32(checkedItems)
+		maximum += float32(items)
+
+		// A blocked Checkbox isn't checked off with its sub-tasks until its blockers are done; otherwise, it'd be checked here and
+		// unchecked again in Card.updateCompletion() every frame
+		checked := completed >= maximum
+		if checked && !cc.Card.Properties.Get("checked").AsBool() && len(cc.Card.IncompleteBlockers()) > 0 {
+			checked = false
+		}
+
+		cc.Card.Properties.Get("checked").Set(checked)
 
 		if maximum > 0 {
 			p := completed / maximum
@@ -257,9 +283,9 @@ func (cc *CheckboxContents) Draw() {
 
 	cc.DefaultContents.Draw()
 
-	cc.Checkbox.Clickable = len(dependentCards) == 0
+	cc.Checkbox.Clickable = !hasSubtasks && !cc.Card.Blocked()
 
-	if len(dependentCards) > 0 {
+	if hasSubtasks {
 		dstPoint := Point{cc.Card.DisplayRect.X + cc.Card.DisplayRect.W - 32, cc.Card.DisplayRect.Y}
 		DrawLabel(cc.Card.Page.Project.Camera.TranslatePoint(dstPoint), fmt.Sprintf("%d/%d", int(completed), int(maximum)))
 	}
@@ -304,7 +330,7 @@ func (cc *CheckboxContents) Color() Color {
 		completedColor = NewColorFromHSV(h+30, s-0.2, v+0.2)
 	}
 
-	if len(cc.DependentCards()) > 0 {
+	if _, items := cc.ChecklistProgress(); len(cc.DependentCards()) > 0 || items > 0 {
 
 		if cc.PercentageOfChildrenComplete >= 0.99 {
 			color = completedColor
@@ -338,8 +364,8 @@ func (cc *CheckboxContents) Trigger(triggerType int) {
 
 func (cc *CheckboxContents) CompletionLevel() float32 {
 
-	if len(cc.DependentCards()) > 0 {
-		comp := float32(0)
+	if checkedItems, items := cc.ChecklistProgress(); len(cc.DependentCards()) > 0 || items > 0 {
+		comp := float32(checkedItems)
 		for _, c := range cc.DependentCards() {
 			comp += c.CompletionLevel()
 		}
@@ -356,8 +382,8 @@ func (cc *CheckboxContents) CompletionLevel() float32 {
 
 func (cc *CheckboxContents) MaximumCompletionLevel() float32 {
 
-	if len(cc.DependentCards()) > 0 {
-		comp := float32(0)
+	if _, items := cc.ChecklistProgress(); len(cc.DependentCards()) > 0 || items > 0 {
+		comp := float32(items)
 		for _, c := range cc.DependentCards() {
 			comp += c.MaximumCompletionLevel()
 		}
@@ -426,10 +452,24 @@ func (cc *CheckboxContents) ReceiveMessage(msg *Message) {
 	}
 }
 
+// ChecklistProgress returns how many of the inline checklist items in the Checkbox's description are checked, and how many there are.
+func (cc *CheckboxContents) ChecklistProgress() (int, int) {
+	return cc.checklist.Progress(cc.Card.Properties.Get("description").AsString())
+}
+
 func (cc *CheckboxContents) DependentCards() []*Card {
 	cards := append([]*Card{}, cc.ParentOf...)
+	blockers := cc.Card.BlockedBy()
 	for _, card := range cc.Linked {
-		if !cc.Card.Stack.Contains(card) {
+		// Cards linked to with blocking links have to be completed first, rather than counting towards this Card's completion
+		blocking := false
+		for _, blocker := range blockers {
+			if blocker == card {
+				blocking = true
+				break
+			}
+		}
+		if !cc.Card.Stack.Contains(card) && !blocking {
 			cards = append(cards, card)
 		}
 	}
@@ -455,6 +495,7 @@ func NewNumberedContents(card *Card) *NumberedContents {
 	}
 	numbered.Label.Property = card.Properties.Get("description")
 	numbered.Label.Editable = true
+	numbered.Label.SpellCheck = true
 	numbered.Label.OnChange = func() {
 		commonTextEditingResizing(numbered.Label, card)
 	}
@@ -626,6 +667,18 @@ func (nc *NumberedContents) Trigger(triggerType int) {
 
 }
 
+// Step adds the given amount (which may be negative) to the Card's current value, keeping it between 0 and the maximum.
+func (nc *NumberedContents) Step(amount float64) {
+
+	value := math.Max(nc.Card.Properties.Get("current").AsFloat()+amount, 0)
+	if max := nc.Card.Properties.Get("maximum").AsFloat(); value > max {
+		value = max
+	}
+
+	nc.Card.Properties.Get("current").Set(value)
+
+}
+
 func (nc *NumberedContents) DefaultSize() Point {
 	gs := globals.GridSize
 	return Point{gs * 8, gs * 2}
@@ -641,7 +694,8 @@ func (nc *NumberedContents) MaximumCompletionLevel() float32 {
 
 type NoteContents struct {
 	DefaultContents
-	Label *Label
+	Label     *Label
+	checklist checklistCache
 }
 
 func NewNoteContents(card *Card) *NoteContents {
@@ -652,6 +706,8 @@ func NewNoteContents(card *Card) *NoteContents {
 
 	nc.Label = NewLabel("New Note", nil, true, AlignLeft)
 	nc.Label.Editable = true
+	nc.Label.Checklist = true
+	nc.Label.SpellCheck = true
 	nc.Label.Property = card.Properties.Get("description")
 
 	nc.Label.OnChange = func() {
@@ -680,6 +736,11 @@ func (nc *NoteContents) Update() {
 
 	nc.Label.SetMaxSize(nc.container.Rect.W-32, nc.container.Rect.H)
 
+	if markdown := globals.Settings.Get(SettingsRenderMarkdown).AsBool(); nc.Label.Markdown != markdown {
+		nc.Label.Markdown = markdown
+		nc.Label.TextureDirty = true
+	}
+
 	kb := globals.Keybindings
 
 	if nc.Card.IsSelected() && globals.State == StateNeutral && kb.Pressed(KBNoteEditText) {
@@ -689,11 +750,50 @@ func (nc *NoteContents) Update() {
 
 }
 
+func (nc *NoteContents) Draw() {
+
+	nc.DefaultContents.Draw()
+
+	if checked, items := nc.ChecklistProgress(); items > 0 {
+		dstPoint := Point{nc.Card.DisplayRect.X + nc.Card.DisplayRect.W - 32, nc.Card.DisplayRect.Y}
+		DrawLabel(nc.Card.Page.Project.Camera.TranslatePoint(dstPoint), fmt.Sprintf("%d/%d", checked, items))
+	}
+
+}
+
 func (nc *NoteContents) Color() Color {
+
+	color := getThemeColor(GUINoteColor)
+	completedColor := getThemeColor(GUICompletedColor)
+
 	if nc.Card.CustomColor != nil {
-		return nc.Card.CustomColor
+		color = nc.Card.CustomColor
+		h, s, v := nc.Card.CustomColor.HSV()
+		completedColor = NewColorFromHSV(h+30, s-0.2, v+0.2)
+	}
+
+	// A Note with an inline checklist shows that it's done once everything on the list is checked off
+	if checked, items := nc.ChecklistProgress(); items > 0 && checked >= items {
+		color = completedColor
 	}
-	return getThemeColor(GUINoteColor)
+
+	return color
+
+}
+
+// ChecklistProgress returns how many of the inline checklist items in the Note are checked, and how many there are.
+func (nc *NoteContents) ChecklistProgress() (int, int) {
+	return nc.checklist.Progress(nc.Card.Properties.Get("description").AsString())
+}
+
+func (nc *NoteContents) CompletionLevel() float32 {
+	checked, _ := nc.ChecklistProgress()
+	return float32(checked)
+}
+
+func (nc *NoteContents) MaximumCompletionLevel() float32 {
+	_, items := nc.ChecklistProgress()
+	return float32(items)
 }
 
 func (nc *NoteContents) DefaultSize() Point {
@@ -1434,14 +1534,50 @@ func (ic *ImageContents) ReceiveMessage(msg *Message) {
 const (
 	TimerModeStopwatch = iota
 	TimerModeCountdown
+	TimerModePomodoro
+)
+
+const (
+	TimerTriggerToggle = iota
+	TimerTriggerSet
+	TimerTriggerClear
+	TimerTriggerAdd
+	TimerTriggerSubtract
+)
+
+const (
+	PomodoroPhaseWork = iota
+	PomodoroPhaseShortBreak
+	PomodoroPhaseLongBreak
 )
 
+var pomodoroPhaseNames = []string{"Work", "Short Break", "Long Break"}
+
+// pomodoroCatchUpThreshold is how far past the end of a Pomodoro phase a Timer can be and still count the phase as having finished while
+// MasterPlan was running; phases that ended longer ago than this ended while the project was closed.
+const pomodoroCatchUpThreshold = time.Second * 5
+
+// nextPomodoroPhase returns the Pomodoro phase that follows the given one. completed is the number of pomodoros finished once the phase
+// is over; every so many (the number of cycles), the break is a long one.
+func nextPomodoroPhase(phase, completed, cycles int) int {
+
+	if phase != PomodoroPhaseWork {
+		return PomodoroPhaseWork
+	}
+
+	if cycles > 0 && completed%cycles == 0 {
+		return PomodoroPhaseLongBreak
+	}
+
+	return PomodoroPhaseShortBreak
+
+}
+
 type TimerContents struct {
 	DefaultContents
 	Name               *Label
 	ClockLabel         *Label
 	ClockMaxTime       *Label
-	Running            bool
 	TimerValue         time.Duration
 	Pie                *Pie
 	StartButton        *IconButton
@@ -1451,6 +1587,11 @@ type TimerContents struct {
 	TriggerMode        *IconButtonGroup
 	AlarmSound         *Sound
 	PercentageComplete float32
+	PhaseLabel         *Label
+	CompletedLabel     *Label
+	TriggerRow         *ContainerRow
+	LoopRow            *ContainerRow
+	PomodoroRows       []*ContainerRow
 }
 
 func NewTimerContents(card *Card) *TimerContents {
@@ -1460,64 +1601,94 @@ func NewTimerContents(card *Card) *TimerContents {
 		Name:            NewLabel("New Timer", nil, true, AlignLeft),
 		ClockLabel:      NewLabel("00:00", &sdl.FRect{0, 0, 128, 32}, true, AlignCenter),
 		ClockMaxTime:    NewLabel("00:00", &sdl.FRect{0, 0, 0, 0}, true, AlignCenter),
+		PhaseLabel:      NewLabel("Work", &sdl.FRect{0, 0, 0, 0}, true, AlignCenter),
+		CompletedLabel:  NewLabel("Done: 0", nil, true, AlignCenter),
+	}
+
+	// Pomodoro lengths are in minutes; defaults are set without triggering OnChange so that creating a Timer doesn't create an undo state
+	defaults := map[string]float64{
+		"trigger step":         1,
+		"pomodoro work":        25,
+		"pomodoro short break": 5,
+		"pomodoro long break":  15,
+		"pomodoro cycles":      4,
+	}
+
+	for name, value := range defaults {
+		if prop := card.Properties.Get(name); !prop.IsNumber() {
+			prop.SetRaw(value)
+		}
 	}
 
 	tc.Name.Property = card.Properties.Get("description")
 
 	tc.ClockMaxTime.Property = card.Properties.Get("max time")
-	tc.ClockMaxTime.RegexString = RegexOnlyDigitsAndColon
-	tc.ClockMaxTime.MaxLength = 8
+	tc.ClockMaxTime.RegexString = RegexDuration
+	tc.ClockMaxTime.MaxLength = 12
 
 	tc.ClockMaxTime.OnClickOut = func() {
 
-		text := tc.ClockMaxTime.TextAsString()
-		if !strings.Contains(text, ":") {
-			tc.ClockMaxTime.SetTextRaw([]rune("00:" + text))
+		if duration, err := ParseTimerDuration(tc.ClockMaxTime.TextAsString()); err == nil {
+			tc.SetMaxDuration(duration)
+		} else {
+			globals.EventLog.Log("Couldn't set the Timer's time: %s", true, err.Error())
+			tc.SetMaxDuration(tc.MaxTime)
 		}
-		timeUnits := strings.Split(tc.ClockMaxTime.TextAsString(), ":")
-
-		minutes, _ := strconv.Atoi(timeUnits[0])
-		seconds, _ := strconv.Atoi(timeUnits[1])
-
-		tc.SetMaxTime(minutes, seconds)
 
 	}
 
-	tc.Mode = NewIconButtonGroup(&sdl.FRect{0, 0, 64, 32}, true, func(index int) {
-		tc.Running = false
-		if index == 0 {
+	tc.Mode = NewIconButtonGroup(&sdl.FRect{0, 0, 96, 32}, true, func(index int) {
+		tc.SetRunning(false)
+		if index == TimerModeStopwatch {
 			globals.EventLog.Log("Timer Mode changed to Stopwatch.", false)
-		} else {
+		} else if index == TimerModeCountdown {
 			globals.EventLog.Log("Timer Mode changed to Countdown.", false)
+		} else {
+			globals.EventLog.Log("Timer Mode changed to Pomodoro.", false)
+			tc.SetElapsed(0)
+			tc.Pie.FillPercent = 0
+		}
+		// Make room for the mode's settings
+		if height := globals.GridSize * float32(timerRowCount(index)); card.Rect.H < height {
+			card.Recreate(card.Rect.W, height)
 		}
 	}, card.Properties.Get("mode group"),
 		&sdl.Rect{48, 192, 32, 32},
 		&sdl.Rect{80, 192, 32, 32},
+		&sdl.Rect{176, 32, 32, 32},
 	)
 
-	tc.TriggerMode = NewIconButtonGroup(&sdl.FRect{0, 0, 96, 32}, true, func(index int) {
-		if index == 0 {
+	tc.TriggerMode = NewIconButtonGroup(&sdl.FRect{0, 0, 160, 32}, true, func(index int) {
+		switch index {
+		case TimerTriggerToggle:
 			globals.EventLog.Log("Timer Trigger Mode changed to Toggle.", false)
-		} else if index == 1 {
+		case TimerTriggerSet:
 			globals.EventLog.Log("Timer Trigger Mode changed to Set.", false)
-		} else {
+		case TimerTriggerClear:
 			globals.EventLog.Log("Timer Trigger Mode changed to Clear.", false)
+		case TimerTriggerAdd:
+			globals.EventLog.Log("Timer Trigger Mode changed to Add.", false)
+		case TimerTriggerSubtract:
+			globals.EventLog.Log("Timer Trigger Mode changed to Subtract.", false)
 		}
 	}, card.Properties.Get("trigger mode"),
 		&sdl.Rect{112, 192, 32, 32},
 		&sdl.Rect{48, 160, 32, 32},
 		&sdl.Rect{144, 192, 32, 32},
+		&sdl.Rect{48, 96, 32, 32},
+		&sdl.Rect{80, 96, 32, 32},
 	)
 
 	tc.Name.OnChange = func() {
 		commonTextEditingResizing(tc.Name, card)
 	}
 
-	tc.StartButton = NewIconButton(0, 0, &sdl.Rect{112, 32, 32, 32}, globals.GUITexture, true, func() { tc.Running = !tc.Running })
-	tc.RestartButton = NewIconButton(0, 0, &sdl.Rect{176, 32, 32, 32}, globals.GUITexture, true, func() { tc.TimerValue = 0; tc.Pie.FillPercent = 0 })
+	tc.StartButton = NewIconButton(0, 0, &sdl.Rect{112, 32, 32, 32}, globals.GUITexture, true, func() { tc.SetRunning(!tc.IsRunning()) })
+	tc.RestartButton = NewIconButton(0, 0, &sdl.Rect{176, 32, 32, 32}, globals.GUITexture, true, func() { tc.SetElapsed(0); tc.Pie.FillPercent = 0 })
 	tc.Pie = NewPie(&sdl.FRect{0, 0, 64, 64}, tc.Color().Sub(80), tc.Color().Add(40), true)
 
 	tc.Name.Editable = true
+	tc.Name.SpellCheck = true
 	// tc.Name.AutoExpand = true
 	// tc.ClockLabel.AutoExpand = true
 
@@ -1528,6 +1699,7 @@ func NewTimerContents(card *Card) *TimerContents {
 	row = tc.container.AddRow(AlignCenter)
 	row.Add("clock", tc.ClockLabel)
 	row.Add("max", tc.ClockMaxTime)
+	row.Add("phase", tc.PhaseLabel)
 
 	row = tc.container.AddRow(AlignCenter)
 	row.Add("pie", tc.Pie)
@@ -1538,34 +1710,163 @@ func NewTimerContents(card *Card) *TimerContents {
 	row.Add("", NewLabel("Mode:  ", nil, true, AlignRight))
 	row.Add("mode", tc.Mode)
 
+	tc.TriggerRow = tc.container.AddRow(AlignCenter)
+	tc.TriggerRow.Add("", NewLabel("Trigger:  ", nil, true, AlignRight))
+	tc.TriggerRow.Add("trigger", tc.TriggerMode)
+
+	limitedSpinner := func(property string) *NumberSpinner {
+		spinner := NewNumberSpinner(nil, true, card.Properties.Get(property))
+		spinner.SetLimits(1, math.MaxFloat64)
+		return spinner
+	}
+
+	step := limitedSpinner("trigger step")
+	tc.LoopRow = tc.container.AddRow(AlignCenter)
+	tc.LoopRow.Add("loop", NewCheckbox(0, 0, true, card.Properties.Get("loop")))
+	tc.LoopRow.Add("", NewLabel("Loop  ", nil, true, AlignLeft))
+	tc.LoopRow.Add("", NewLabel("Step:  ", nil, true, AlignRight))
+	tc.LoopRow.Add("step", step)
+	tc.LoopRow.ExpandSelectedElements = []MenuElement{step}
+
+	work := limitedSpinner("pomodoro work")
+	row = tc.container.AddRow(AlignCenter)
+	row.Add("", NewLabel("Work:  ", nil, true, AlignRight))
+	row.Add("work", work)
+	row.ExpandSelectedElements = []MenuElement{work}
+	tc.PomodoroRows = append(tc.PomodoroRows, row)
+
+	shortBreak := limitedSpinner("pomodoro short break")
+	longBreak := limitedSpinner("pomodoro long break")
+	row = tc.container.AddRow(AlignCenter)
+	row.Add("", NewLabel("Breaks:  ", nil, true, AlignRight))
+	row.Add("short break", shortBreak)
+	row.Add("long break", longBreak)
+	row.ExpandSelectedElements = []MenuElement{shortBreak, longBreak}
+	tc.PomodoroRows = append(tc.PomodoroRows, row)
+
+	cycles := limitedSpinner("pomodoro cycles")
 	row = tc.container.AddRow(AlignCenter)
-	row.Add("", NewLabel("Trigger:  ", nil, true, AlignRight))
-	row.Add("trigger", tc.TriggerMode)
+	row.Add("", NewLabel("Cycles:  ", nil, true, AlignRight))
+	row.Add("cycles", cycles)
+	row.Add("completed", tc.CompletedLabel)
+	row.ExpandSelectedElements = []MenuElement{cycles}
+	tc.PomodoroRows = append(tc.PomodoroRows, row)
+
+	row = tc.container.AddRow(AlignCenter)
+	row.Add("log time", NewCheckbox(0, 0, true, card.Properties.Get("pomodoro log time")))
+	row.Add("", NewLabel("Log Work to Links", nil, true, AlignLeft))
+	tc.PomodoroRows = append(tc.PomodoroRows, row)
 
 	return tc
 }
 
 func (tc *TimerContents) SetMaxTime(minutes, seconds int) string {
+	return tc.SetMaxDuration(time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second)
+}
+
+// SetMaxDuration sets how long the Timer counts down for in Countdown mode, returning the duration as it's displayed and stored.
+func (tc *TimerContents) SetMaxDuration(duration time.Duration) string {
+
+	text := formatTimerDuration(duration)
+
+	tc.MaxTime = duration
+	tc.ClockMaxTime.SetTextRaw([]rune(text))
+	tc.ClockMaxTime.Property.Set(text)
+
+	return text
+
+}
+
+// startTime returns when the Timer was last started, along with whether it's currently running.
+func (tc *TimerContents) startTime() (time.Time, bool) {
+
+	started := tc.Card.Properties.Get("timer started")
+	if !started.IsNumber() {
+		return time.Time{}, false
+	}
+
+	return time.Unix(0, int64(started.AsFloat()*float64(time.Second))), true
+
+}
+
+// IsRunning returns if the Timer is running.
+func (tc *TimerContents) IsRunning() bool {
+	_, running := tc.startTime()
+	return running
+}
+
+// Elapsed returns how long the Timer has run for. Running Timers are timed from when they were started rather than by how many frames
+// have passed, so they keep accurate time while MasterPlan is throttled in the background, and keep running while it's closed.
+func (tc *TimerContents) Elapsed() time.Duration {
+
+	elapsed := time.Duration(tc.Card.Properties.Get("timer elapsed").AsFloat() * float64(time.Second))
+
+	if started, running := tc.startTime(); running {
+		elapsed += time.Since(started)
+	}
+
+	return elapsed
+
+}
 
-	for seconds >= 60 {
-		seconds -= 60
-		minutes++
+// SetRunning starts or stops the Timer.
+func (tc *TimerContents) SetRunning(running bool) {
+
+	if running == tc.IsRunning() {
+		return
+	}
+
+	if running {
+		tc.setTimerState("timer started", float64(time.Now().UnixNano())/float64(time.Second))
+	} else {
+		tc.setTimerState("timer elapsed", tc.Elapsed().Seconds())
+		// The property is emptied rather than removed so that the Timer's saved as stopped.
+		tc.setTimerState("timer started", "")
 	}
 
-	tc.ClockMaxTime.SetTextRaw([]rune(fmt.Sprintf("%02d", minutes) + ":" + fmt.Sprintf("%02d", seconds)))
+}
+
+// SetElapsed sets how long the Timer has run for, without starting or stopping it.
+func (tc *TimerContents) SetElapsed(elapsed time.Duration) {
+
+	tc.setTimerState("timer elapsed", elapsed.Seconds())
 
-	tc.MaxTime = time.Duration((minutes * int(time.Minute)) + (seconds * int(time.Second)))
+	if tc.IsRunning() {
+		tc.setTimerState("timer started", float64(time.Now().UnixNano())/float64(time.Second))
+	}
+
+	tc.TimerValue = elapsed
 
-	return tc.ClockMaxTime.TextAsString()
+}
 
+// setTimerState sets one of the properties tracking the Timer's state. Timers start, stop, and change phases on their own, so these changes
+// mark the project as modified without creating undo states; otherwise, undoing would undo the Timer rather than the user's last edit.
+func (tc *TimerContents) setTimerState(property string, value interface{}) {
+	tc.Card.Properties.Get(property).SetRaw(value)
+	tc.Card.Page.Project.SetModifiedState()
 }
 
 func (tc *TimerContents) Update() {
 
+	modeGroup := int(tc.Card.Properties.Get("mode group").AsFloat())
+
+	// The Trigger row is swapped out for the four rows of Pomodoro settings in Pomodoro mode, while the Loop row is only for Countdowns
+	tc.TriggerRow.Visible = modeGroup != TimerModePomodoro
+	tc.LoopRow.Visible = modeGroup == TimerModeCountdown
+	for _, row := range tc.PomodoroRows {
+		row.Visible = modeGroup == TimerModePomodoro
+	}
+
+	// Timers saved before the mode had as many rows can be too short to fit them all
+	if height := tc.DefaultSize().Y; tc.Card.Collapsed == CollapsedNone && tc.Card.Rect.H < height {
+		tc.Card.Recreate(tc.Card.Rect.W, height)
+		tc.Card.UncollapsedSize = Point{tc.Card.Rect.W, tc.Card.Rect.H}
+	}
+
 	gs := globals.GridSize
 	r := tc.Name.Rectangle()
 	r.W = tc.Card.Rect.W - gs
-	r.H = tc.Card.Rect.H - (gs * 5)
+	r.H = tc.Card.Rect.H - (gs * float32(timerRowCount(modeGroup)-1))
 	if r.H < gs {
 		r.H = gs
 	}
@@ -1573,80 +1874,95 @@ func (tc *TimerContents) Update() {
 
 	tc.StartButton.IconSrc.X = 112
 
+	if maxTime, err := ParseTimerDuration(tc.ClockMaxTime.Property.AsString()); err == nil {
+		tc.MaxTime = maxTime
+	}
+
+	tc.TimerValue = tc.Elapsed()
+
 	kb := globals.Keybindings
 	if tc.Card.IsSelected() && globals.State == StateNeutral && kb.Pressed(KBTimerEditText) {
 		kb.Shortcuts[KBTimerEditText].ConsumeKeys()
 		tc.Name.BeginEditing()
 	}
 
-	if tc.Running {
+	if tc.IsRunning() {
 
 		tc.StartButton.IconSrc.X = 144
-		tc.TimerValue += time.Duration(globals.DeltaTime * float32(time.Second))
 		tc.Pie.FillPercent += globals.DeltaTime
 
-		modeGroup := int(tc.Card.Properties.Get("mode group").AsFloat())
+		if modeGroup == TimerModePomodoro && tc.TimerValue > tc.TargetTime() {
 
-		if tc.TimerValue > tc.MaxTime && modeGroup == 1 {
+			// Several phases may have passed while the project was closed, but only the latest change is announced. Phases that ended
+			// while the project was closed are skipped through without being credited, as nobody was there to work through them.
+			message := ""
+			for i := 0; i < 1000 && tc.TimerValue > tc.TargetTime(); i++ {
+				message = tc.AdvancePomodoro(tc.TimerValue-tc.TargetTime() < pomodoroCatchUpThreshold)
+			}
 
-			elapsedMessage := "Timer [" + tc.Name.TextAsString() + "] elapsed."
+			globals.EventLog.Log("%s", false, message)
+			tc.Alert(message)
 
-			tc.Running = false
-			globals.EventLog.Log(elapsedMessage, false)
-			tc.Pie.FillPercent = 0
-			tc.TimerValue = 0
+		} else if tc.TimerValue > tc.MaxTime && modeGroup == TimerModeCountdown {
 
-			triggerMode := int(tc.Card.Properties.Get("trigger mode").AsFloat())
+			elapsedMessage := "Timer [" + tc.Name.TextAsString() + "] elapsed."
 
-			tt := TriggerTypeToggle
-			if triggerMode == 1 {
-				tt = TriggerTypeSet
-			} else if triggerMode == 2 {
-				tt = TriggerTypeClear
+			// Looping Timers restart, carrying over any time past the end; if the project was closed, it may have elapsed several times over
+			times := 1
+			if tc.Card.Properties.Get("loop").AsBool() && tc.MaxTime > 0 {
+				times = int(tc.TimerValue / tc.MaxTime)
+				tc.SetElapsed(tc.TimerValue % tc.MaxTime)
+			} else {
+				tc.SetRunning(false)
+				tc.SetElapsed(0)
 			}
 
-			for _, link := range tc.Card.Links {
-
-				if link.End.Contents != nil {
-					link.End.Contents.Trigger(tt)
-				}
-			}
+			globals.EventLog.Log(elapsedMessage, false)
+			tc.Pie.FillPercent = 0
 
-			if globals.Settings.Get(SettingsFocusOnElapsedTimers).AsBool() {
-				tc.Card.Page.Project.Camera.FocusOn(false, tc.Card)
-			}
-			if globals.Settings.Get(SettingsNotifyOnElapsedTimers).AsBool() && globals.WindowFlags&sdl.WINDOW_INPUT_FOCUS == 0 {
-				beeep.Notify("MasterPlan", elapsedMessage, "")
-			}
+			tc.TriggerLinks(times)
 
-			if globals.Settings.Get(SettingsPlayAlarmSound).AsBool() {
-				if tc.AlarmSound != nil {
-					tc.AlarmSound.Destroy()
-				}
-				tc.AlarmSound, _ = globals.Resources.Get(LocalRelativePath("assets/alarm.wav")).AsNewSound()
-				tc.AlarmSound.Play()
-			}
+			tc.Alert(elapsedMessage)
 
 		}
 
 	}
 
-	modeGroup := tc.Card.Properties.Get("mode group").AsFloat()
-
-	if modeGroup == 0 {
+	if modeGroup == TimerModeCountdown {
+		tc.ClockMaxTime.SetRectangle(&sdl.FRect{0, 0, 128, 32})
+		tc.ClockMaxTime.Editable = true
+	} else {
 		tc.ClockMaxTime.SetRectangle(&sdl.FRect{0, 0, 0, 0})
 		tc.ClockMaxTime.Editable = false
+	}
+
+	if modeGroup == TimerModePomodoro {
+
+		phase := tc.PomodoroPhase()
+		phaseText := pomodoroPhaseNames[phase]
+
+		if phase == PomodoroPhaseWork {
+			completed := int(tc.Card.Properties.Get("pomodoros completed").AsFloat())
+			cycles := int(tc.Card.Properties.Get("pomodoro cycles").AsFloat())
+			if cycles > 0 {
+				phaseText += fmt.Sprintf(" %d/%d", completed%cycles+1, cycles)
+			}
+		}
+
+		tc.PhaseLabel.SetRectangle(&sdl.FRect{0, 0, 128, 32})
+		tc.PhaseLabel.SetText([]rune(phaseText))
+		tc.CompletedLabel.SetText([]rune(fmt.Sprintf("Done: %d", int(tc.Card.Properties.Get("pomodoros completed").AsFloat()))))
+
 	} else {
-		tc.ClockMaxTime.SetRectangle(&sdl.FRect{0, 0, 128, 32})
-		tc.ClockMaxTime.Editable = true
+		tc.PhaseLabel.SetRectangle(&sdl.FRect{0, 0, 0, 0})
 	}
 
-	tc.ClockLabel.SetText([]rune(formatTime(tc.TimerValue, false)))
+	tc.ClockLabel.SetText([]rune(formatTimerDuration(tc.TimerValue)))
 
 	if tc.Card.IsSelected() {
 
 		if globals.State == StateNeutral && globals.Keybindings.Pressed(KBTimerStartStop) {
-			tc.Running = !tc.Running
+			tc.SetRunning(!tc.IsRunning())
 		}
 
 		description := tc.Card.Properties.Get("description")
@@ -1667,10 +1983,10 @@ func (tc *TimerContents) Draw() {
 	p := float32(0)
 
 	// Numbered mode
-	if int(tc.Card.Properties.Get("mode group").AsFloat()) != 0 && tc.MaxTime > 0 {
+	if target := tc.TargetTime(); int(tc.Card.Properties.Get("mode group").AsFloat()) != TimerModeStopwatch && target > 0 {
 
 		if tc.TimerValue > 0 {
-			p = float32(tc.TimerValue) / float32(tc.MaxTime)
+			p = float32(tc.TimerValue) / float32(target)
 		}
 
 	}
@@ -1701,15 +2017,166 @@ func (tc *TimerContents) Draw() {
 
 }
 
+// TriggerLinks triggers the Cards the Timer links to according to its trigger mode. In the Add and Subtract trigger modes, linked
+// Numbered Cards are stepped by the Timer's step amount (multiplied by the number of times the Timer elapsed), while other Cards are
+// set or cleared instead.
+func (tc *TimerContents) TriggerLinks(times int) {
+
+	triggerMode := int(tc.Card.Properties.Get("trigger mode").AsFloat())
+	step := tc.Card.Properties.Get("trigger step").AsFloat() * float64(times)
+	if triggerMode == TimerTriggerSubtract {
+		step *= -1
+	}
+
+	for _, link := range tc.Card.Links {
+
+		target := link.End
+
+		if link.Start != tc.Card || target.Contents == nil {
+			continue
+		}
+
+		if numbered, ok := target.Contents.(*NumberedContents); ok && (triggerMode == TimerTriggerAdd || triggerMode == TimerTriggerSubtract) {
+			numbered.Step(step)
+			globals.EventLog.Log("Timer [%s] stepped [%s] by %+g.", false, tc.Name.TextAsString(), target.Name(), step)
+			continue
+		}
+
+		tt := TriggerTypeToggle
+		switch triggerMode {
+		case TimerTriggerSet, TimerTriggerAdd:
+			tt = TriggerTypeSet
+		case TimerTriggerClear, TimerTriggerSubtract:
+			tt = TriggerTypeClear
+		}
+
+		target.Contents.Trigger(tt)
+		globals.EventLog.Log("Timer [%s] triggered [%s].", false, tc.Name.TextAsString(), target.Name())
+
+	}
+
+}
+
+// timerRowCount returns how many rows tall a Timer's contents are in the given mode, as each mode shows different settings.
+func timerRowCount(mode int) int {
+
+	// The name, clock, pie (which is two rows tall), mode, and trigger rows
+	rows := 6
+
+	if mode == TimerModeCountdown {
+		rows++ // The Loop row
+	} else if mode == TimerModePomodoro {
+		rows += 3 // The Pomodoro settings rows, in place of the trigger row
+	}
+
+	return rows
+
+}
+
+// TargetTime returns how long the Timer runs for before elapsing; this is the max time in Countdown mode, and the length of the
+// current phase in Pomodoro mode.
+func (tc *TimerContents) TargetTime() time.Duration {
+
+	if int(tc.Card.Properties.Get("mode group").AsFloat()) != TimerModePomodoro {
+		return tc.MaxTime
+	}
+
+	lengths := []string{"pomodoro work", "pomodoro short break", "pomodoro long break"}
+
+	return time.Duration(tc.Card.Properties.Get(lengths[tc.PomodoroPhase()]).AsFloat() * float64(time.Minute))
+
+}
+
+// PomodoroPhase returns which phase (work, short break, or long break) the Timer is in in Pomodoro mode.
+func (tc *TimerContents) PomodoroPhase() int {
+	phase := int(tc.Card.Properties.Get("pomodoro phase").AsFloat())
+	if phase < PomodoroPhaseWork || phase > PomodoroPhaseLongBreak {
+		phase = PomodoroPhaseWork
+	}
+	return phase
+}
+
+// AdvancePomodoro finishes the current Pomodoro phase and moves on to the next one, without stopping the Timer, returning a message
+// announcing the change. If credit is true, finishing a work session counts it as a completed pomodoro and, if set, logs it as time
+// tracked on the Cards the Timer links to. Every so many pomodoros (the number of cycles), the break is a long one.
+func (tc *TimerContents) AdvancePomodoro(credit bool) string {
+
+	message := ""
+	name := tc.Name.TextAsString()
+
+	// Any time past the end of the phase carries over into the next one
+	overflow := tc.Elapsed() - tc.TargetTime()
+	if overflow < 0 {
+		overflow = 0
+	}
+
+	if tc.PomodoroPhase() == PomodoroPhaseWork {
+
+		completed := tc.Card.Properties.Get("pomodoros completed").AsFloat() + 1
+		if credit {
+			tc.setTimerState("pomodoros completed", completed)
+		}
+
+		if credit && tc.Card.Properties.Get("pomodoro log time").AsBool() {
+
+			end := time.Now().Add(-overflow)
+			start := end.Add(-tc.TargetTime())
+
+			for _, link := range tc.Card.Links {
+				if link.Start == tc.Card {
+					link.End.AddTimeSession(start, end)
+					link.End.HandleUndos()
+				}
+			}
+
+		}
+
+		next := nextPomodoroPhase(PomodoroPhaseWork, int(completed), int(tc.Card.Properties.Get("pomodoro cycles").AsFloat()))
+
+		tc.setTimerState("pomodoro phase", float64(next))
+		message = "Pomodoro [" + name + "] finished; time for a " + strings.ToLower(pomodoroPhaseNames[next]) + "."
+
+	} else {
+		tc.setTimerState("pomodoro phase", float64(PomodoroPhaseWork))
+		message = "Break for Pomodoro [" + name + "] is over; back to work."
+	}
+
+	tc.SetElapsed(overflow)
+	tc.Pie.FillPercent = 0
+
+	return message
+
+}
+
+// Alert lets the user know the Timer has elapsed (or changed phase), according to their settings.
+func (tc *TimerContents) Alert(message string) {
+
+	if globals.Settings.Get(SettingsFocusOnElapsedTimers).AsBool() {
+		tc.Card.Page.Project.Camera.FocusOn(false, tc.Card)
+	}
+	if globals.Settings.Get(SettingsNotifyOnElapsedTimers).AsBool() && globals.WindowFlags&sdl.WINDOW_INPUT_FOCUS == 0 {
+		beeep.Notify("MasterPlan", message, "")
+	}
+
+	if globals.Settings.Get(SettingsPlayAlarmSound).AsBool() {
+		if tc.AlarmSound != nil {
+			tc.AlarmSound.Destroy()
+		}
+		tc.AlarmSound, _ = globals.Resources.Get(LocalRelativePath("assets/alarm.wav")).AsNewSound()
+		tc.AlarmSound.Play()
+	}
+
+}
+
 func (tc *TimerContents) Trigger(triggerType int) {
 
 	switch triggerType {
 	case TriggerTypeSet:
-		tc.Running = true
+		tc.SetRunning(true)
 	case TriggerTypeClear:
-		tc.Running = false
+		tc.SetRunning(false)
 	case TriggerTypeToggle:
-		tc.Running = !tc.Running
+		tc.SetRunning(!tc.IsRunning())
 	}
 
 }
@@ -1736,7 +2203,7 @@ func (tc *TimerContents) Color() Color {
 }
 
 func (tc *TimerContents) DefaultSize() Point {
-	return Point{globals.GridSize * 8, globals.GridSize * 6}
+	return Point{globals.GridSize * 8, globals.GridSize * float32(timerRowCount(int(tc.Card.Properties.Get("mode group").AsFloat())))}
 }
 
 type MapData struct {
@@ -2760,6 +3227,20 @@ func (sb *SubPageContents) Update() {
 
 }
 
+func (sb *SubPageContents) Draw() {
+
+	sb.DefaultContents.Draw()
+
+	// Show when the sub-page can be finished by, if it's been planned out with dependencies or duration estimates
+	if sb.SubPage != nil && sb.SubPage.Valid() {
+		if schedule := sb.SubPage.Schedule(); schedule.HasDependencies && schedule.Finish > 0 {
+			pos := Point{sb.Card.DisplayRect.X + (globals.GridSize * 0.25), sb.Card.DisplayRect.Y + sb.Card.DisplayRect.H - 12}
+			DrawLabel(sb.Card.Page.Project.Camera.TranslatePoint(pos), "Finishes by "+schedule.FinishDate().Format(DeadlineDateFormat))
+		}
+	}
+
+}
+
 func (sb *SubPageContents) OpenSubpage() {
 	sb.SubPage.Project.SetPage(sb.SubPage)
 }
@@ -2859,6 +3340,7 @@ func NewLinkContents(card *Card) *LinkContents {
 	lc.Card.Properties.Get("target")
 
 	lc.Label.Editable = true
+	lc.Label.SpellCheck = true
 	lc.Label.Property = card.Properties.Get("description")
 	lc.Label.RegexString = RegexNoNewlines
 
@@ -3131,34 +3613,1022 @@ func (lc *LinkContents) ReceiveMessage(msg *Message) {
 
 }
 
-// type TableContents struct {
-// 	DefaultContents
-// }
+const (
+	TableMinColumnWidth     = 32
+	TableCompletionWidth    = 64 // The width of the column showing how many of each row's checkboxes are checked
+	tableColumnResizeMargin = 4
+)
 
-// func NewTableContents(card *Card) *TableContents {
-// 	tc := &TableContents{
-// 		DefaultContents: newDefaultContents(card),
-// 	}
-// 	return tc
-// }
+// TableColumn is a column in a Table Card; its cells either hold text or checkboxes.
+type TableColumn struct {
+	Name     string
+	Width    float32
+	Checkbox bool
+}
+
+// TableData is the contents of a Table Card. Each row holds a cell for each column; checkbox cells are "x" when checked and empty otherwise.
+type TableData struct {
+	Columns []*TableColumn
+	Rows    [][]string
+}
+
+func NewTableData() *TableData {
+	data := &TableData{
+		Columns: []*TableColumn{
+			{Name: "Task", Width: globals.GridSize * 5},
+			{Name: "Done", Width: globals.GridSize * 2, Checkbox: true},
+		},
+	}
+	for i := 0; i < 3; i++ {
+		data.AddRow()
+	}
+	return data
+}
 
-// func (tc *TableContents) Update() {}
+// ParseTableData parses a Table Card's data from the JSON stored in its "table" property.
+func ParseTableData(text string) *TableData {
 
-// func (tc *TableContents) Draw() {}
+	data := &TableData{}
 
-// func (tc *TableContents) Color() Color {
-// 	color := getThemeColor(GUITableColor)
-// 	return color
-// }
+	parsed := gjson.Parse(text)
 
-// func (tc *TableContents) ReceiveMessage(msg *Message) {}
+	for _, column := range parsed.Get("columns").Array() {
+		data.Columns = append(data.Columns, &TableColumn{
+			Name:     column.Get("name").String(),
+			Width:    float32(column.Get("width").Float()),
+			Checkbox: column.Get("checkbox").Bool(),
+		})
+	}
 
-// func (tc *TableContents) DefaultSize() Point {
-// 	gs := globals.GridSize
-// 	return Point{gs * 6, gs * 4}
-// }
+	if len(data.Columns) == 0 {
+		data.Columns = append(data.Columns, &TableColumn{Name: "Task"})
+	}
 
-// func (tc *TableContents) Trigger(triggerType int) {}
+	for _, column := range data.Columns {
+		if column.Width < TableMinColumnWidth {
+			column.Width = globals.GridSize * 4
+		}
+	}
+
+	for _, row := range parsed.Get("rows").Array() {
+
+		cells := make([]string, len(data.Columns))
+		for i, cell := range row.Array() {
+			if i < len(cells) {
+				cells[i] = cell.String()
+			}
+		}
+		data.Rows = append(data.Rows, cells)
+
+	}
+
+	return data
+
+}
+
+func (data *TableData) Serialize() string {
+
+	text := "{}"
+
+	for _, column := range data.Columns {
+		columnData, _ := sjson.Set("{}", "name", column.Name)
+		columnData, _ = sjson.Set(columnData, "width", column.Width)
+		columnData, _ = sjson.Set(columnData, "checkbox", column.Checkbox)
+		text, _ = sjson.SetRaw(text, "columns.-1", columnData)
+	}
+
+	text, _ = sjson.Set(text, "rows", data.Rows)
+
+	return text
+
+}
+
+func (data *TableData) AddRow() {
+	data.Rows = append(data.Rows, make([]string, len(data.Columns)))
+}
+
+func (data *TableData) RemoveRow() {
+	if len(data.Rows) > 1 {
+		data.Rows = data.Rows[:len(data.Rows)-1]
+	}
+}
+
+func (data *TableData) AddColumn(checkbox bool) {
+
+	column := &TableColumn{Name: "Column", Width: globals.GridSize * 4, Checkbox: checkbox}
+	if checkbox {
+		column.Name = "Done"
+		column.Width = globals.GridSize * 2
+	}
+
+	data.Columns = append(data.Columns, column)
+
+	for i := range data.Rows {
+		data.Rows[i] = append(data.Rows[i], "")
+	}
+
+}
+
+func (data *TableData) RemoveColumn() {
+
+	if len(data.Columns) <= 1 {
+		return
+	}
+
+	data.Columns = data.Columns[:len(data.Columns)-1]
+
+	for i := range data.Rows {
+		data.Rows[i] = data.Rows[i][:len(data.Columns)]
+	}
+
+}
+
+// ImportV07TableData converts a table from a MasterPlan v0.7 plan, which was a grid of checkboxes with named rows and columns, into a
+// TableData, with the row names in the first column.
+func ImportV07TableData(tableData gjson.Result) *TableData {
+
+	headingName := func(heading gjson.Result) string {
+		if heading.IsObject() {
+			return heading.Get("Name").String()
+		}
+		return heading.String()
+	}
+
+	rowNames := []string{}
+	for _, row := range tableData.Get("Rows").Array() {
+		rowNames = append(rowNames, headingName(row))
+	}
+
+	data := &TableData{Columns: []*TableColumn{{Name: "", Width: globals.GridSize * 5}}}
+
+	for _, column := range tableData.Get("Columns").Array() {
+		data.Columns = append(data.Columns, &TableColumn{Name: headingName(column), Width: globals.GridSize * 3, Checkbox: true})
+	}
+
+	completions := tableData.Get("Completions").Array()
+
+	// Completions should be stored by row, then column, but are read by column first if the dimensions only fit that way
+	byColumn := len(completions) != len(rowNames) && len(completions) == len(data.Columns)-1
+
+	for r, name := range rowNames {
+
+		cells := make([]string, len(data.Columns))
+		cells[0] = name
+
+		for c := 1; c < len(cells); c++ {
+
+			completion := tableData.Get(fmt.Sprintf("Completions.%d.%d", r, c-1))
+			if byColumn {
+				completion = tableData.Get(fmt.Sprintf("Completions.%d.%d", c-1, r))
+			}
+
+			// v0.7 cells could also be marked as failed (2), which is imported as unchecked
+			if completion.Int() == 1 {
+				cells[c] = "x"
+			}
+
+		}
+
+		data.Rows = append(data.Rows, cells)
+
+	}
+
+	if len(data.Rows) == 0 {
+		data.AddRow()
+	}
+
+	return data
+
+}
+
+// Checked returns if the given cell is a checked checkbox.
+func (data *TableData) Checked(row, column int) bool {
+	return data.Columns[column].Checkbox && data.Rows[row][column] != ""
+}
+
+// SetChecked checks or unchecks all checkbox cells in the table.
+func (data *TableData) SetChecked(checked bool) {
+
+	value := ""
+	if checked {
+		value = "x"
+	}
+
+	for _, cells := range data.Rows {
+		for c, column := range data.Columns {
+			if column.Checkbox {
+				cells[c] = value
+			}
+		}
+	}
+
+}
+
+// Completion returns how many of the checkbox cells in the given row and column are checked, along with how many checkbox cells there are.
+// A row or column of -1 counts all rows or columns.
+func (data *TableData) Completion(row, column int) (int, int) {
+
+	completed := 0
+	total := 0
+
+	for r := range data.Rows {
+
+		if row >= 0 && r != row {
+			continue
+		}
+
+		for c, col := range data.Columns {
+
+			if (column >= 0 && c != column) || !col.Checkbox {
+				continue
+			}
+
+			total++
+			if data.Checked(r, c) {
+				completed++
+			}
+
+		}
+
+	}
+
+	return completed, total
+
+}
+
+// Width returns the total width of the table's columns.
+func (data *TableData) Width() float32 {
+	w := float32(0)
+	for _, column := range data.Columns {
+		w += column.Width
+	}
+	return w
+}
+
+type TableContents struct {
+	DefaultContents
+	Label            *Label
+	Data             *TableData
+	columnElements   [][]MenuElement // The elements in each column, resized along with the column
+	checkboxes       map[*Checkbox][2]int
+	rowLabels        []*Label
+	columnLabels     []*Label
+	totalLabel       *Label
+	serializedData   string // The table as last loaded or saved; if the property differs (i.e. from undoing or redoing), the table is reloaded
+	rebuildNeeded    bool
+	resizingColumn   int
+	resizeStartWidth float32
+	lastFileCheck    time.Time
+}
+
+func NewTableContents(card *Card) *TableContents {
+
+	tc := &TableContents{
+		DefaultContents: newDefaultContents(card),
+		Label:           NewLabel("New Table", nil, true, AlignLeft),
+		resizingColumn:  -1,
+	}
+
+	tc.Label.Property = card.Properties.Get("description")
+	tc.Label.Editable = true
+	tc.Label.SpellCheck = true
+	tc.Label.RegexString = RegexNoNewlines
+
+	if table := card.Properties.Get("table"); !table.IsString() || table.AsString() == "" {
+		table.SetRaw(NewTableData().Serialize())
+	}
+
+	file := card.Properties.Get("table file")
+	file.Set(card.Page.Project.PathToAbsolute(file.AsString(), false))
+	card.Properties.Get("table file sync")
+
+	row := tc.container.AddRow(AlignLeft)
+	row.Add("icon", NewGUIImage(nil, icons[ContentTypeTable], globals.GUITexture.Texture, true))
+	row.Add("label", tc.Label)
+
+	tc.rebuild()
+
+	// Rebuilding again on the first update fits the Card to the table once it's fully created
+	tc.rebuildNeeded = true
+
+	return tc
+
+}
+
+// rebuild loads the table from the Card's "table" property and recreates the rows of the table's cells.
+func (tc *TableContents) rebuild() {
+
+	gs := globals.GridSize
+
+	tc.serializedData = tc.Card.Properties.Get("table").AsString()
+	tc.Data = ParseTableData(tc.serializedData)
+	tc.rebuildNeeded = false
+
+	// The first row holds the icon and name, which are kept
+	for _, row := range tc.container.Rows[1:] {
+		row.Destroy()
+	}
+	tc.container.Rows = tc.container.Rows[:1]
+
+	tc.columnElements = make([][]MenuElement, len(tc.Data.Columns))
+	tc.checkboxes = map[*Checkbox][2]int{}
+	tc.rowLabels = []*Label{}
+	tc.columnLabels = []*Label{}
+
+	row := tc.container.AddRow(AlignLeft)
+
+	for c, column := range tc.Data.Columns {
+
+		columnIndex := c
+		header := NewLabel(column.Name, &sdl.FRect{0, 0, column.Width, gs}, true, AlignCenter)
+		header.Editable = true
+		header.RegexString = RegexNoNewlines
+		header.OnChange = func() {
+			tc.Data.Columns[columnIndex].Name = header.TextAsString()
+			tc.save()
+		}
+
+		row.Add("header "+strconv.Itoa(c), header)
+		tc.columnElements[c] = append(tc.columnElements[c], header)
+
+	}
+
+	for r, cells := range tc.Data.Rows {
+
+		row = tc.container.AddRow(AlignLeft)
+
+		for c, column := range tc.Data.Columns {
+
+			rowIndex, columnIndex := r, c
+
+			var element MenuElement
+
+			if column.Checkbox {
+
+				checkbox := NewCheckbox(0, 0, true, nil)
+				checkbox.Rect.W = column.Width
+				checkbox.Rect.H = gs
+				checkbox.OnPressed = func() {
+					if tc.Data.Checked(rowIndex, columnIndex) {
+						tc.Data.Rows[rowIndex][columnIndex] = ""
+					} else {
+						tc.Data.Rows[rowIndex][columnIndex] = "x"
+					}
+					tc.save()
+				}
+				tc.checkboxes[checkbox] = [2]int{r, c}
+				element = checkbox
+
+			} else {
+
+				cell := NewLabel(cells[c], &sdl.FRect{0, 0, column.Width, gs}, true, AlignLeft)
+				cell.Editable = true
+				cell.SpellCheck = true
+				cell.RegexString = RegexNoNewlines
+				cell.OnChange = func() {
+					tc.Data.Rows[rowIndex][columnIndex] = cell.TextAsString()
+					tc.save()
+				}
+				element = cell
+
+			}
+
+			row.Add(fmt.Sprintf("cell %d %d", r, c), element)
+			tc.columnElements[c] = append(tc.columnElements[c], element)
+
+		}
+
+		rowLabel := NewLabel("", &sdl.FRect{0, 0, TableCompletionWidth, gs}, true, AlignCenter)
+		row.Add("row completion", rowLabel)
+		tc.rowLabels = append(tc.rowLabels, rowLabel)
+
+	}
+
+	row = tc.container.AddRow(AlignLeft)
+
+	for c, column := range tc.Data.Columns {
+		columnLabel := NewLabel("", &sdl.FRect{0, 0, column.Width, gs}, true, AlignCenter)
+		row.Add("column completion "+strconv.Itoa(c), columnLabel)
+		tc.columnElements[c] = append(tc.columnElements[c], columnLabel)
+		tc.columnLabels = append(tc.columnLabels, columnLabel)
+	}
+
+	tc.totalLabel = NewLabel("", &sdl.FRect{0, 0, TableCompletionWidth, gs}, true, AlignCenter)
+	row.Add("total completion", tc.totalLabel)
+
+	row = tc.container.AddRow(AlignLeft)
+
+	row.Add("", NewLabel("Rows: ", nil, true, AlignLeft))
+	row.Add("add row", NewIconButton(0, 0, &sdl.Rect{48, 96, 32, 32}, globals.GUITexture, true, func() {
+		tc.Data.AddRow()
+		tc.save()
+		tc.rebuildNeeded = true
+	}))
+	row.Add("remove row", NewIconButton(0, 0, &sdl.Rect{80, 96, 32, 32}, globals.GUITexture, true, func() {
+		tc.Data.RemoveRow()
+		tc.save()
+		tc.rebuildNeeded = true
+	}))
+
+	row.Add("", NewLabel("  Columns: ", nil, true, AlignLeft))
+	row.Add("add text column", NewIconButton(0, 0, icons[ContentTypeNote], globals.GUITexture, true, func() {
+		tc.Data.AddColumn(false)
+		tc.save()
+		tc.rebuildNeeded = true
+	}))
+	row.Add("add checkbox column", NewIconButton(0, 0, icons[ContentTypeCheckbox], globals.GUITexture, true, func() {
+		tc.Data.AddColumn(true)
+		tc.save()
+		tc.rebuildNeeded = true
+	}))
+	row.Add("remove column", NewIconButton(0, 0, &sdl.Rect{80, 96, 32, 32}, globals.GUITexture, true, func() {
+		tc.Data.RemoveColumn()
+		tc.save()
+		tc.rebuildNeeded = true
+	}))
+
+	row.Add("", NewLabel("  Import: ", nil, true, AlignLeft))
+	row.Add("import file", NewIconButton(0, 0, &sdl.Rect{400, 224, 32, 32}, globals.GUITexture, true, func() {
+		globals.Mouse.Button(sdl.BUTTON_LEFT).Consume()
+		tc.BrowseForFile()
+	}))
+
+	if file := tc.Card.Properties.Get("table file").AsString(); file != "" {
+		row = tc.container.AddRow(AlignLeft)
+		row.Add("sync", NewCheckbox(0, 0, true, tc.Card.Properties.Get("table file sync")))
+		row.Add("", NewLabel("Keep synced with "+filepath.Base(file), nil, true, AlignLeft))
+	}
+
+}
+
+// save stores the table in the Card's "table" property.
+func (tc *TableContents) save() {
+	tc.serializedData = tc.Data.Serialize()
+	tc.Card.Properties.Get("table").Set(tc.serializedData)
+}
+
+// fitCard grows the Card to fit the table, if necessary.
+func (tc *TableContents) fitCard() {
+
+	if tc.Card.Collapsed != CollapsedNone {
+		return
+	}
+
+	size := tc.DefaultSize()
+	w := float32(math.Max(float64(tc.Card.Rect.W), float64(size.X)))
+	h := float32(math.Max(float64(tc.Card.Rect.H), float64(size.Y)))
+
+	if w != tc.Card.Rect.W || h != tc.Card.Rect.H {
+		tc.Card.Recreate(w, h)
+		tc.Card.UncollapsedSize = Point{tc.Card.Rect.W, tc.Card.Rect.H}
+	}
+
+}
+
+func (tc *TableContents) setColumnWidth(column int, width float32) {
+
+	tc.Data.Columns[column].Width = width
+
+	for _, element := range tc.columnElements[column] {
+		rect := element.Rectangle()
+		rect.W = width
+		element.SetRectangle(rect)
+	}
+
+}
+
+func (tc *TableContents) Update() {
+
+	gs := globals.GridSize
+
+	tc.syncWithFile()
+
+	if tc.rebuildNeeded || tc.Card.Properties.Get("table").AsString() != tc.serializedData {
+		tc.rebuild()
+		tc.fitCard()
+	}
+
+	if tc.Card.Page.IsCurrent() {
+
+		mousePos := globals.Mouse.WorldPosition()
+		button := globals.Mouse.Button(sdl.BUTTON_LEFT)
+		rect := tc.Card.DisplayRect
+
+		if tc.resizingColumn >= 0 {
+
+			globals.Mouse.SetCursor(CursorResizeHorizontal)
+
+			left := rect.X
+			for _, column := range tc.Data.Columns[:tc.resizingColumn] {
+				left += column.Width
+			}
+
+			width := float32(math.Round(float64(mousePos.X-left)/8) * 8)
+			if width < TableMinColumnWidth {
+				width = TableMinColumnWidth
+			}
+			tc.setColumnWidth(tc.resizingColumn, width)
+
+			if !button.HeldRaw() {
+				if width != tc.resizeStartWidth {
+					tc.save()
+				}
+				tc.resizingColumn = -1
+				tc.fitCard()
+			}
+
+		} else if globals.State == StateNeutral {
+
+			// Columns are resized by dragging the right edge of their cells
+			top := rect.Y + gs
+			bottom := top + gs*float32(len(tc.Data.Rows)+2)
+
+			if mousePos.Y >= top && mousePos.Y <= bottom {
+
+				x := rect.X
+
+				for c, column := range tc.Data.Columns {
+
+					x += column.Width
+
+					if math.Abs(float64(mousePos.X-x)) <= tableColumnResizeMargin {
+
+						globals.Mouse.SetCursor(CursorResizeHorizontal)
+
+						// The cursor has to already be showing so that the Card doesn't get dragged instead
+						if button.Pressed() && globals.Mouse.CurrentCursor == CursorResizeHorizontal {
+							button.Consume()
+							tc.resizingColumn = c
+							tc.resizeStartWidth = column.Width
+						}
+
+						break
+
+					}
+
+				}
+
+			}
+
+		}
+
+	}
+
+	for checkbox, cell := range tc.checkboxes {
+		checkbox.Checked = tc.Data.Checked(cell[0], cell[1])
+	}
+
+	for r, label := range tc.rowLabels {
+		label.SetText([]rune(formatTableCompletion(tc.Data.Completion(r, -1))))
+	}
+
+	for c, label := range tc.columnLabels {
+		label.SetText([]rune(formatTableCompletion(tc.Data.Completion(-1, c))))
+	}
+
+	tc.totalLabel.SetText([]rune(formatTableCompletion(tc.Data.Completion(-1, -1))))
+
+	labelRect := tc.Label.Rectangle()
+	labelRect.W = tc.container.Rect.W - 32
+	labelRect.H = gs
+	tc.Label.SetRectangle(labelRect)
+
+	tc.DefaultContents.Update()
+
+}
+
+func formatTableCompletion(completed, total int) string {
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", completed, total)
+}
+
+func (tc *TableContents) Draw() {
+
+	completed, total := tc.Data.Completion(-1, -1)
+
+	if total > 0 {
+
+		p := float32(completed) / float32(total)
+
+		src := &sdl.Rect{0, 0, int32(tc.Card.Rect.W * p), int32(tc.Card.Rect.H)}
+		dst := &sdl.FRect{tc.Card.DisplayRect.X, tc.Card.DisplayRect.Y, float32(src.W), float32(src.H)}
+		dst = tc.Card.Page.Project.Camera.TranslateRect(dst)
+
+		completionColor := getThemeColor(GUICompletedColor)
+		if tc.Card.CustomColor != nil {
+			h, s, v := tc.Card.CustomColor.HSV()
+			completionColor = NewColorFromHSV(h+30, s-0.2, v+0.2)
+		}
+
+		tc.Card.Result.Texture.SetColorMod(completionColor.RGB())
+		globals.Renderer.CopyF(tc.Card.Result.Texture, src, dst)
+
+	}
+
+	// Column dividers
+	camera := tc.Card.Page.Project.Camera
+	lineColor := tc.Color().Sub(40)
+	top := tc.Card.DisplayRect.Y + globals.GridSize
+	bottom := top + globals.GridSize*float32(len(tc.Data.Rows)+2)
+	x := tc.Card.DisplayRect.X
+
+	for _, column := range tc.Data.Columns {
+		x += column.Width
+		start := camera.TranslatePoint(Point{x, top})
+		end := camera.TranslatePoint(Point{x, bottom})
+		ThickLine(start, end, 2, lineColor)
+	}
+
+	tc.DefaultContents.Draw()
+
+}
+
+func (tc *TableContents) Color() Color {
+
+	color := getThemeColor(GUITableColor)
+	completedColor := getThemeColor(GUICompletedColor)
+
+	if tc.Card.CustomColor != nil {
+		color = tc.Card.CustomColor
+		h, s, v := tc.Card.CustomColor.HSV()
+		completedColor = NewColorFromHSV(h+30, s-0.2, v+0.2)
+	}
+
+	if completed, total := tc.Data.Completion(-1, -1); total > 0 && completed >= total {
+		return completedColor
+	}
+
+	return color
+
+}
+
+func (tc *TableContents) ReceiveMessage(msg *Message) {}
+
+func (tc *TableContents) DefaultSize() Point {
+	gs := globals.GridSize
+	w := float32(math.Max(float64(tc.Data.Width()+TableCompletionWidth), float64(gs*14)))
+	h := gs * float32(len(tc.Data.Rows)+4)
+	if tc.Card.Properties.Get("table file").AsString() != "" {
+		h += gs
+	}
+	return Point{w, h}
+}
+
+func (tc *TableContents) Trigger(triggerType int) {
+
+	switch triggerType {
+	case TriggerTypeSet:
+		tc.Data.SetChecked(true)
+	case TriggerTypeClear:
+		tc.Data.SetChecked(false)
+	case TriggerTypeToggle:
+		completed, total := tc.Data.Completion(-1, -1)
+		tc.Data.SetChecked(completed < total)
+	}
+
+	tc.save()
+
+}
+
+// CompletionLevel returns how many of the table's checkbox cells are checked.
+func (tc *TableContents) CompletionLevel() float32 {
+	completed, _ := tc.Data.Completion(-1, -1)
+	return float32(completed)
+}
+
+// MaximumCompletionLevel returns how many checkbox cells the table has.
+func (tc *TableContents) MaximumCompletionLevel() float32 {
+	_, total := tc.Data.Completion(-1, -1)
+	return float32(total)
+}
+
+type FrameContents struct {
+	DefaultContents
+	Label        *Label
+	dragMembers  []*Card
+	dragPosition Point
+}
+
+func NewFrameContents(card *Card) *FrameContents {
+
+	fc := &FrameContents{
+		DefaultContents: newDefaultContents(card),
+		Label:           NewLabel("New Frame", nil, true, AlignLeft),
+	}
+
+	fc.Label.Property = card.Properties.Get("description")
+	fc.Label.Editable = true
+	fc.Label.SpellCheck = true
+	fc.Label.RegexString = RegexNoNewlines
+
+	row := fc.container.AddRow(AlignLeft)
+	row.Add("icon", NewGUIImage(nil, icons[ContentTypeFrame], globals.GUITexture.Texture, true))
+	row.Add("label", fc.Label)
+
+	return fc
+
+}
+
+// Bounds returns the area the Frame encloses; this is its full size, even when it's collapsed.
+func (fc *FrameContents) Bounds() *sdl.FRect {
+	rect := *fc.Card.Rect
+	if fc.Card.Collapsed != CollapsedNone && fc.Card.UncollapsedSize.Y > 0 {
+		rect.W = fc.Card.UncollapsedSize.X
+		rect.H = fc.Card.UncollapsedSize.Y
+	}
+	return &rect
+}
+
+// Members returns the Cards that lie entirely within the Frame.
+func (fc *FrameContents) Members() []*Card {
+	return fc.membersAt(fc.Card.Rect.X, fc.Card.Rect.Y)
+}
+
+// RecordHiddenMembers records the Frame's current members as the Cards to hide when it's collapsed; Cards placed in the
+// Frame's area afterwards aren't hidden.
+func (fc *FrameContents) RecordHiddenMembers() {
+	ids := []int64{}
+	for _, member := range fc.Members() {
+		ids = append(ids, member.ID)
+	}
+	fc.Card.Properties.Get("hidden cards").SetInts(ids...)
+}
+
+// HiddenMembers returns the Cards hidden by the Frame while it's collapsed. These are the Cards that were in the Frame
+// when it was collapsed, and that are still within its bounds.
+func (fc *FrameContents) HiddenMembers() []*Card {
+
+	hidden := []*Card{}
+
+	if fc.Card.Collapsed == CollapsedNone || !fc.Card.Properties.Has("hidden cards") {
+		return hidden
+	}
+
+	bounds := fc.Bounds()
+
+	for _, id := range fc.Card.Properties.Get("hidden cards").AsArrayOfInts() {
+		if card := fc.Card.Page.CardByID(id); card != nil && card != fc.Card && card.Valid && card.Rect.X >= bounds.X && card.Rect.Y >= bounds.Y && card.Rect.X+card.Rect.W <= bounds.X+bounds.W && card.Rect.Y+card.Rect.H <= bounds.Y+bounds.H {
+			hidden = append(hidden, card)
+		}
+	}
+
+	return hidden
+
+}
+
+func (fc *FrameContents) membersAt(x, y float32) []*Card {
+
+	bounds := fc.Bounds()
+	bounds.X = x
+	bounds.Y = y
+
+	members := []*Card{}
+
+	for _, card := range fc.Card.Page.Cards {
+		if card != fc.Card && card.Valid && card.Rect.X >= bounds.X && card.Rect.Y >= bounds.Y && card.Rect.X+card.Rect.W <= bounds.X+bounds.W && card.Rect.Y+card.Rect.H <= bounds.Y+bounds.H {
+			members = append(members, card)
+		}
+	}
+
+	return members
+
+}
+
+func (fc *FrameContents) Update() {
+
+	// Frames sit behind the Cards they hold
+	fc.Card.Depth = -2
+
+	position := Point{fc.Card.Rect.X, fc.Card.Rect.Y}
+
+	// The Frame's members move along with it; selected Cards are left alone, as they're already being dragged
+	if fc.Card.Dragging && fc.dragMembers == nil {
+		fc.dragMembers = []*Card{}
+		members := fc.membersAt(fc.dragPosition.X, fc.dragPosition.Y)
+		// A collapsed Frame only carries the Cards it's hiding
+		if fc.Card.Collapsed != CollapsedNone {
+			members = fc.HiddenMembers()
+		}
+		for _, member := range members {
+			if !member.selected {
+				fc.dragMembers = append(fc.dragMembers, member)
+			}
+		}
+	}
+
+	if fc.dragMembers != nil {
+
+		for _, member := range fc.dragMembers {
+			member.Rect.X += position.X - fc.dragPosition.X
+			member.Rect.Y += position.Y - fc.dragPosition.Y
+		}
+
+		if !fc.Card.Dragging {
+			for _, member := range fc.dragMembers {
+				member.LockPosition()
+				member.CreateUndoState = true
+			}
+			fc.dragMembers = nil
+		}
+
+	}
+
+	fc.dragPosition = position
+
+	rect := fc.Label.Rectangle()
+	rect.W = fc.container.Rect.W - 32
+	rect.H = globals.GridSize
+	fc.Label.SetRectangle(rect)
+
+	fc.DefaultContents.Update()
+
+}
+
+func (fc *FrameContents) Draw() {
+
+	// The title bar is solid, while the rest of the Frame is translucent
+	color := fc.Color()
+	color[3] = 255
+
+	src := &sdl.Rect{0, 0, int32(fc.Card.Rect.W), int32(globals.GridSize)}
+	dst := fc.Card.Page.Project.Camera.TranslateRect(&sdl.FRect{fc.Card.DisplayRect.X, fc.Card.DisplayRect.Y, fc.Card.DisplayRect.W, globals.GridSize})
+
+	fc.Card.Result.Texture.SetColorMod(color.RGB())
+	fc.Card.Result.Texture.SetAlphaMod(255)
+	globals.Renderer.CopyF(fc.Card.Result.Texture, src, dst)
+
+	fc.DefaultContents.Draw()
+
+	if fc.Card.Collapsed != CollapsedNone {
+		if members := fc.HiddenMembers(); len(members) > 0 {
+			text := fmt.Sprintf("%d hidden", len(members))
+			dstPoint := fc.Card.Page.Project.Camera.TranslatePoint(Point{fc.Card.DisplayRect.X + fc.Card.DisplayRect.W, fc.Card.DisplayRect.Y})
+			dstPoint.X -= globals.TextRenderer.MeasureText([]rune(text), 0.5).X + 24
+			DrawLabel(dstPoint, text)
+		}
+	}
+
+}
+
+func (fc *FrameContents) Color() Color {
+
+	color := getThemeColor(GUIFrameColor).Clone()
+	if fc.Card.CustomColor != nil {
+		color = fc.Card.CustomColor.Clone()
+	}
+
+	if fc.Card.Collapsed == CollapsedNone {
+		color[3] = 96
+	}
+
+	return color
+
+}
+
+func (fc *FrameContents) ReceiveMessage(msg *Message) {
+
+	if msg.Type == MessageContentSwitched {
+		if fc.Card.Contents != fc {
+			fc.Card.Depth = 0
+		}
+		// Frames aren't placed in the Page's Grid, so it has to be updated when switching to or from being a Frame
+		fc.Card.LockPosition()
+	} else if msg.Type == MessageProjectLoadingAllCardsCreated && fc.Card.Properties.Has("hidden cards") {
+		// Cards are given new IDs when loading, so the hidden Cards' IDs have to be updated to match
+		ids := []int64{}
+		for _, id := range fc.Card.Properties.Get("hidden cards").AsArrayOfInts() {
+			if card := fc.Card.Page.CardByLoadedID(id); card != nil {
+				ids = append(ids, card.ID)
+			}
+		}
+		fc.Card.Properties.Get("hidden cards").SetInts(ids...)
+	}
+
+}
+
+func (fc *FrameContents) DefaultSize() Point {
+	gs := globals.GridSize
+	return Point{gs * 10, gs * 6}
+}
+
+type CodeContents struct {
+	DefaultContents
+	Label            *Label
+	LineNumbers      *Label
+	LanguageDropdown *Dropdown
+	language         string // The language the code was last highlighted as
+}
+
+func NewCodeContents(card *Card) *CodeContents {
+
+	cc := &CodeContents{
+		DefaultContents: newDefaultContents(card),
+	}
+
+	language := card.Properties.Get("language")
+	if language.AsString() == "" {
+		language.SetRaw(CodeLanguagePlainText)
+	}
+
+	cc.Label = NewLabel("// New Code", nil, true, AlignLeft)
+	cc.Label.Editable = true
+	cc.Label.DrawLineUnderTitle = false
+	cc.Label.Style = TextStyleMonospace
+	cc.Label.Property = card.Properties.Get("description")
+	cc.Label.Highlight = func(text string) []TextRun {
+		return HighlightCode(text, CodeLanguageByName(language.AsString()))
+	}
+
+	cc.Label.OnChange = func() {
+		commonTextEditingResizing(cc.Label, card)
+	}
+
+	cc.LineNumbers = NewLabel("1", nil, true, AlignRight)
+	cc.LineNumbers.Style = TextStyleMonospace
+	cc.LineNumbers.Alpha = 0.5
+
+	cc.LanguageDropdown = NewDropdown(&sdl.FRect{0, 0, 160, 32}, true, nil, language, CodeLanguageNames()...)
+
+	row := cc.container.AddRow(AlignLeft)
+	row.Add("icon", NewGUIImage(nil, icons[ContentTypeCode], globals.GUITexture.Texture, true))
+	row.Add("language", cc.LanguageDropdown)
+	row.Add("copy", NewIconButton(0, 0, &sdl.Rect{80, 0, 32, 32}, globals.GUITexture, true, func() {
+		cc.CopyToClipboard()
+	}))
+
+	row = cc.container.AddRow(AlignLeft)
+	row.Add("line numbers", cc.LineNumbers)
+	row.Add("code", cc.Label)
+
+	return cc
+
+}
+
+func (cc *CodeContents) Update() {
+
+	// Undoing or redoing can change the language, so the dropdown follows the property
+	language := cc.Card.Properties.Get("language").AsString()
+
+	for i, option := range cc.LanguageDropdown.Options {
+		if option == language {
+			cc.LanguageDropdown.ChosenIndex = i
+			break
+		}
+	}
+
+	cc.DefaultContents.Update()
+
+	if language = cc.Card.Properties.Get("language").AsString(); cc.language != language {
+		cc.language = language
+		cc.Label.TextureDirty = true
+	}
+
+	numbers := []string{}
+	for _, number := range cc.Label.LineNumbers() {
+		if number > 0 {
+			numbers = append(numbers, strconv.Itoa(number))
+		} else {
+			numbers = append(numbers, "")
+		}
+	}
+	cc.LineNumbers.SetText([]rune(strings.Join(numbers, "\n")))
+
+	cc.Label.SetMaxSize(cc.container.Rect.W-cc.LineNumbers.Rect.W-32, cc.container.Rect.H-globals.GridSize)
+
+	kb := globals.Keybindings
+
+	if cc.Card.IsSelected() && globals.State == StateNeutral && kb.Pressed(KBNoteEditText) {
+		kb.Shortcuts[KBNoteEditText].ConsumeKeys()
+		cc.Label.BeginEditing()
+	}
+
+}
+
+// CopyToClipboard copies the card's code to the clipboard.
+func (cc *CodeContents) CopyToClipboard() {
+	clipboard.Write(clipboard.FmtText, []byte(cc.Label.TextAsString()))
+	globals.EventLog.Log("Copied code to clipboard.", false)
+}
+
+func (cc *CodeContents) ReceiveMessage(msg *Message) {
+	// Highlighting colors depend on the theme
+	if msg.Type == MessageThemeChange {
+		cc.Label.TextureDirty = true
+	}
+}
+
+func (cc *CodeContents) Color() Color {
+	if cc.Card.CustomColor != nil {
+		return cc.Card.CustomColor
+	}
+	return getThemeColor(GUICodeColor)
+}
+
+func (cc *CodeContents) DefaultSize() Point {
+	return Point{globals.GridSize * 12, globals.GridSize * 2}
+}
 
 // type Calendar struct {
 // 	DefaultContents
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tidwall/gjson"
+)
+
+func TestNextPomodoroPhase(t *testing.T) {
+
+	tests := []struct {
+		cycles int
+		want   []int
+	}{
+		{4, []int{
+			PomodoroPhaseWork, PomodoroPhaseShortBreak,
+			PomodoroPhaseWork, PomodoroPhaseShortBreak,
+			PomodoroPhaseWork, PomodoroPhaseShortBreak,
+			PomodoroPhaseWork, PomodoroPhaseLongBreak,
+			PomodoroPhaseWork, PomodoroPhaseShortBreak,
+		}},
+		{1, []int{PomodoroPhaseWork, PomodoroPhaseLongBreak, PomodoroPhaseWork, PomodoroPhaseLongBreak}},
+
+		// Without a number of cycles, there are no long breaks
+		{0, []int{PomodoroPhaseWork, PomodoroPhaseShortBreak, PomodoroPhaseWork, PomodoroPhaseShortBreak}},
+	}
+
+	for _, test := range tests {
+
+		phase := PomodoroPhaseWork
+		completed := 0
+
+		for i, want := range test.want {
+
+			if phase != want {
+				t.Errorf("with %d cycles, phase %d = %s, want %s", test.cycles, i, pomodoroPhaseNames[phase], pomodoroPhaseNames[want])
+				break
+			}
+
+			if phase == PomodoroPhaseWork {
+				completed++
+			}
+
+			phase = nextPomodoroPhase(phase, completed, test.cycles)
+
+		}
+
+	}
+
+	// Breaks are always followed by work
+	for _, phase := range []int{PomodoroPhaseShortBreak, PomodoroPhaseLongBreak} {
+		if next := nextPomodoroPhase(phase, 4, 4); next != PomodoroPhaseWork {
+			t.Errorf("nextPomodoroPhase(%s) = %s, want Work", pomodoroPhaseNames[phase], pomodoroPhaseNames[next])
+		}
+	}
+
+}
+
+func TestTableDataSerialize(t *testing.T) {
+
+	data := &TableData{
+		Columns: []*TableColumn{
+			{Name: "Task", Width: 160},
+			{Name: "Done, really", Width: 64, Checkbox: true},
+		},
+		Rows: [][]string{
+			{"Write \"tests\"", "x"},
+			{"", ""},
+		},
+	}
+
+	if parsed := ParseTableData(data.Serialize()); !reflect.DeepEqual(parsed, data) {
+		t.Errorf("ParseTableData(Serialize()) = %+v, want %+v", parsed, data)
+	}
+
+}
+
+func TestParseTableData(t *testing.T) {
+
+	gs := globals.GridSize
+
+	tests := []struct {
+		text string
+		want *TableData
+	}{
+		// Tables without columns get one, and columns too narrow to use are widened
+		{`{}`, &TableData{Columns: []*TableColumn{{Name: "Task", Width: gs * 4}}}},
+		{
+			`{"columns": [{"name": "A", "width": 1}, {"name": "B", "width": 64, "checkbox": true}], "rows": [["a1", "x", "extra"], ["a2"]]}`,
+			&TableData{
+				Columns: []*TableColumn{{Name: "A", Width: gs * 4}, {Name: "B", Width: 64, Checkbox: true}},
+				Rows:    [][]string{{"a1", "x"}, {"a2", ""}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		if got := ParseTableData(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseTableData(%s) = %+v, want %+v", test.text, got, test.want)
+		}
+	}
+
+}
+
+func TestTableDataCompletion(t *testing.T) {
+
+	data := &TableData{
+		Columns: []*TableColumn{{Name: "Task"}, {Name: "A", Checkbox: true}, {Name: "B", Checkbox: true}},
+		Rows: [][]string{
+			{"one", "x", ""},
+			{"two", "x", "x"},
+			{"x", "", ""},
+		},
+	}
+
+	tests := []struct {
+		row, column      int
+		completed, total int
+	}{
+		{-1, -1, 3, 6},
+		{0, -1, 1, 2},
+		{1, -1, 2, 2},
+		{2, -1, 0, 2},
+		{-1, 1, 2, 3},
+		{-1, 2, 1, 3},
+		{-1, 0, 0, 0}, // Text cells aren't checkboxes, even when they hold an "x"
+		{1, 2, 1, 1},
+	}
+
+	for _, test := range tests {
+		if completed, total := data.Completion(test.row, test.column); completed != test.completed || total != test.total {
+			t.Errorf("Completion(%d, %d) = %d / %d, want %d / %d", test.row, test.column, completed, total, test.completed, test.total)
+		}
+	}
+
+	data.SetChecked(true)
+	if completed, total := data.Completion(-1, -1); completed != total {
+		t.Errorf("after SetChecked(true), Completion() = %d / %d", completed, total)
+	}
+
+	if data.Rows[2][0] != "x" || data.Rows[0][0] != "one" {
+		t.Errorf("SetChecked() changed text cells: %q", data.Rows)
+	}
+
+	data.SetChecked(false)
+	if completed, _ := data.Completion(-1, -1); completed != 0 {
+		t.Errorf("after SetChecked(false), %d cells are checked", completed)
+	}
+
+}
+
+func TestImportV07TableData(t *testing.T) {
+
+	gs := globals.GridSize
+
+	tests := []struct {
+		name string
+		json string
+		rows [][]string
+	}{
+		{
+			"by row",
+			`{"Rows": ["A", "B"], "Columns": ["X", "Y", "Z"], "Completions": [[1, 0, 2], [0, 1, 1]]}`,
+			[][]string{{"A", "x", "", ""}, {"B", "", "x", "x"}},
+		},
+		{
+			"by column",
+			`{"Rows": ["A", "B", "C"], "Columns": ["X", "Y"], "Completions": [[1, 0, 1], [0, 1, 0]]}`,
+			[][]string{{"A", "x", ""}, {"B", "", "x"}, {"C", "x", ""}},
+		},
+		{
+			"headings as objects",
+			`{"Rows": [{"Name": "A"}], "Columns": [{"Name": "X"}], "Completions": [[1]]}`,
+			[][]string{{"A", "x"}},
+		},
+		{
+			"missing completions",
+			`{"Rows": ["A", "B"], "Columns": ["X"]}`,
+			[][]string{{"A", ""}, {"B", ""}},
+		},
+		{
+			"empty",
+			`{"Columns": ["X", "Y"]}`,
+			[][]string{{"", "", ""}},
+		},
+	}
+
+	for _, test := range tests {
+
+		data := ImportV07TableData(gjson.Parse(test.json))
+
+		if !reflect.DeepEqual(data.Rows, test.rows) {
+			t.Errorf("%s: rows = %q, want %q", test.name, data.Rows, test.rows)
+		}
+
+		if len(data.Columns) != len(test.rows[0]) {
+			t.Errorf("%s: %d columns, want %d", test.name, len(data.Columns), len(test.rows[0]))
+			continue
+		}
+
+		// The row names go in the first column, and each v0.7 column becomes a checkbox column
+		if first := data.Columns[0]; first.Checkbox || first.Width != gs*5 {
+			t.Errorf("%s: first column = %+v, want a text column for the row names", test.name, *first)
+		}
+
+		for _, column := range data.Columns[1:] {
+			if !column.Checkbox || column.Width != gs*3 {
+				t.Errorf("%s: column %+v should be a checkbox column", test.name, *column)
+			}
+		}
+
+	}
+
+	data := ImportV07TableData(gjson.Parse(`{"Rows": ["A"], "Columns": [{"Name": "X"}, "Y"]}`))
+	if data.Columns[1].Name != "X" || data.Columns[2].Name != "Y" {
+		t.Errorf("column names = %q, %q, want X, Y", data.Columns[1].Name, data.Columns[2].Name)
+	}
+
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultDurationEstimate is the number of days a complete-able Card without a duration estimate is expected to take.
+const DefaultDurationEstimate = 1.0
+
+var blockingLinkColor = NewColor(240, 80, 80, 255)
+
+// Schedule is the result of scheduling the complete-able Cards on a Page according to their blocking dependencies and duration estimates.
+type Schedule struct {
+	EarliestFinish  map[*Card]float64 // Number of days from today each Card can be finished, at the earliest
+	CriticalPath    map[*Card]bool    // The chain of Cards that determines when the Page can be finished
+	Finish          float64           // Number of days from today the Page can be finished, at the earliest
+	HasDependencies bool              // Whether any Cards on the Page have blocking dependencies or duration estimates
+}
+
+// FinishDate returns the earliest date all of the scheduled Cards can be finished by.
+func (schedule *Schedule) FinishDate() time.Time {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return today.AddDate(0, 0, int(math.Ceil(schedule.Finish)))
+}
+
+// Schedule schedules the complete-able Cards on the Page. Each Card can be finished, at the earliest, its remaining duration after the
+// latest of the Cards blocking it are finished; completed Cards have no remaining duration. Blocking Cards on other Pages are ignored, as
+// are dependencies that form a cycle.
+func (page *Page) Schedule() *Schedule {
+
+	schedule := &Schedule{
+		EarliestFinish: map[*Card]float64{},
+		CriticalPath:   map[*Card]bool{},
+	}
+
+	visiting := map[*Card]bool{}
+	criticalPredecessor := map[*Card]*Card{}
+
+	var earliestFinish func(card *Card) float64
+
+	earliestFinish = func(card *Card) float64 {
+
+		if finish, exists := schedule.EarliestFinish[card]; exists {
+			return finish
+		}
+
+		// We've looped back around to a Card we're already scheduling, so ignore the dependency
+		if visiting[card] {
+			return 0
+		}
+
+		visiting[card] = true
+
+		start := 0.0
+
+		for _, blocker := range card.BlockedBy() {
+
+			if blocker.Page != page {
+				continue
+			}
+
+			if finish := earliestFinish(blocker); criticalPredecessor[card] == nil || finish > start {
+				start = finish
+				criticalPredecessor[card] = blocker
+			}
+
+		}
+
+		delete(visiting, card)
+
+		finish := start + card.RemainingDuration()
+		schedule.EarliestFinish[card] = finish
+
+		return finish
+
+	}
+
+	var last *Card
+
+	for _, card := range page.Cards {
+
+		if !card.Valid || !card.Completable() {
+			continue
+		}
+
+		if (card.Properties.Has("duration") && card.Properties.Get("duration").IsNumber()) || len(card.BlockedBy()) > 0 {
+			schedule.HasDependencies = true
+		}
+
+		if finish := earliestFinish(card); last == nil || finish > schedule.Finish {
+			schedule.Finish = finish
+			last = card
+		}
+
+	}
+
+	// The critical path is the chain of Cards leading to the Card that finishes last
+	traced := map[*Card]bool{}
+
+	for card := last; card != nil && !traced[card]; card = criticalPredecessor[card] {
+		traced[card] = true
+		if card.RemainingDuration() > 0 {
+			schedule.CriticalPath[card] = true
+		}
+	}
+
+	return schedule
+
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"testing"
+)
+
+// newScheduleTestCard adds a bare Number Card to the Page, taking the given number of days (or the default estimate if it's 0).
+func newScheduleTestCard(page *Page, duration float64, completed bool) *Card {
+
+	card := &Card{Valid: true, Page: page, ContentType: ContentTypeNumbered, Properties: NewProperties()}
+	card.Contents = &NumberedContents{DefaultContents: DefaultContents{Card: card}}
+
+	card.Properties.Get("maximum").Set(1.0)
+
+	if completed {
+		card.Properties.Get("current").Set(1.0)
+	}
+
+	if duration > 0 {
+		card.Properties.Get("duration").Set(duration)
+	}
+
+	page.Cards = append(page.Cards, card)
+
+	return card
+
+}
+
+func blockScheduleTestCard(card, blocker *Card) {
+	card.Links = append(card.Links, &LinkEnding{Start: card, End: blocker, Blocking: true})
+}
+
+func TestScheduleChain(t *testing.T) {
+
+	page := &Page{}
+
+	a := newScheduleTestCard(page, 2, false)
+	b := newScheduleTestCard(page, 3, false)
+	c := newScheduleTestCard(page, 0, false)
+	d := newScheduleTestCard(page, 4, false)
+
+	// c can't start until b's done, which can't start until a's done; d can happen alongside them
+	blockScheduleTestCard(c, b)
+	blockScheduleTestCard(b, a)
+
+	schedule := page.Schedule()
+
+	if !schedule.HasDependencies {
+		t.Errorf("HasDependencies = false, want true")
+	}
+
+	if schedule.Finish != 6 {
+		t.Errorf("Finish = %v, want 6", schedule.Finish)
+	}
+
+	for i, test := range []struct {
+		card     *Card
+		finish   float64
+		critical bool
+	}{
+		{a, 2, true},
+		{b, 5, true},
+		{c, 6, true},
+		{d, 4, false},
+	} {
+
+		if finish := schedule.EarliestFinish[test.card]; finish != test.finish {
+			t.Errorf("card %d EarliestFinish = %v, want %v", i, finish, test.finish)
+		}
+
+		if critical := schedule.CriticalPath[test.card]; critical != test.critical {
+			t.Errorf("card %d on CriticalPath = %v, want %v", i, critical, test.critical)
+		}
+
+	}
+
+}
+
+func TestScheduleLongestBlocker(t *testing.T) {
+
+	page := &Page{}
+
+	short := newScheduleTestCard(page, 1, false)
+	long := newScheduleTestCard(page, 5, false)
+	done := newScheduleTestCard(page, 10, true)
+	last := newScheduleTestCard(page, 2, false)
+
+	blockScheduleTestCard(last, short)
+	blockScheduleTestCard(last, long)
+	blockScheduleTestCard(last, done)
+
+	schedule := page.Schedule()
+
+	if schedule.Finish != 7 {
+		t.Errorf("Finish = %v, want 7", schedule.Finish)
+	}
+
+	// Completed Cards take no more time, and aren't part of the critical path
+	if finish := schedule.EarliestFinish[done]; finish != 0 {
+		t.Errorf("completed card EarliestFinish = %v, want 0", finish)
+	}
+
+	if !schedule.CriticalPath[long] || !schedule.CriticalPath[last] || schedule.CriticalPath[short] || schedule.CriticalPath[done] {
+		t.Errorf("CriticalPath = %v, want only the longest blocker and the card it blocks", schedule.CriticalPath)
+	}
+
+}
+
+func TestScheduleIgnoredDependencies(t *testing.T) {
+
+	page := &Page{}
+	otherPage := &Page{}
+
+	// Blockers on other Pages are ignored
+	elsewhere := newScheduleTestCard(otherPage, 10, false)
+	a := newScheduleTestCard(page, 1, false)
+	blockScheduleTestCard(a, elsewhere)
+
+	// As are dependencies that loop back around
+	b := newScheduleTestCard(page, 1, false)
+	c := newScheduleTestCard(page, 1, false)
+	blockScheduleTestCard(b, c)
+	blockScheduleTestCard(c, b)
+
+	// As are Cards that can't be completed
+	note := &Card{Valid: true, Page: page, ContentType: ContentTypeNote, Properties: NewProperties()}
+	page.Cards = append(page.Cards, note)
+
+	schedule := page.Schedule()
+
+	if finish := schedule.EarliestFinish[a]; finish != 1 {
+		t.Errorf("EarliestFinish for card blocked from another page = %v, want 1", finish)
+	}
+
+	if schedule.Finish != 2 {
+		t.Errorf("Finish = %v, want 2", schedule.Finish)
+	}
+
+	if _, exists := schedule.EarliestFinish[note]; exists {
+		t.Errorf("Note card was scheduled")
+	}
+
+}
+
+func TestScheduleWithoutDependencies(t *testing.T) {
+
+	page := &Page{}
+
+	newScheduleTestCard(page, 0, false)
+	newScheduleTestCard(page, 0, true)
+
+	schedule := page.Schedule()
+
+	if schedule.HasDependencies {
+		t.Errorf("HasDependencies = true, want false")
+	}
+
+	if schedule.Finish != DefaultDurationEstimate {
+		t.Errorf("Finish = %v, want %v", schedule.Finish, DefaultDurationEstimate)
+	}
+
+	if empty := (&Page{}).Schedule(); empty.Finish != 0 || len(empty.CriticalPath) != 0 {
+		t.Errorf("empty Page's schedule = %+v, want nothing scheduled", empty)
+	}
+
+}
@@ -41,8 +41,12 @@ type Globals struct {
 
 	RendererInfo      sdl.RendererInfo
 	Font              *ttf.Font
+	MonospaceFont     *ttf.Font
+	FallbackFonts     []*ttf.Font
 	TextRenderer      *TextRenderer
+	SpellChecker      *SpellChecker
 	LoadedFontPath    string
+	LoadedFallbacks   string
 	Keyboard          Keyboard
 	Mouse             Mouse
 	InputText         []rune
@@ -189,6 +189,16 @@ func (grid *Grid) Put(card *Card) {
 
 	grid.Remove(card)
 
+	// Frames lie underneath the Cards they hold, so they're left out of the Grid to not collide or stack with them
+	if _, isFrame := card.Contents.(*FrameContents); isFrame {
+		return
+	}
+
+	// Cards hidden in collapsed Frames can't be seen, so they shouldn't take up space either
+	if card.hidden {
+		return
+	}
+
 	card.GridExtents = grid.Select(card.Rect)
 
 	if card.GridExtents.OutsideGrid() {
@@ -15,6 +15,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/pkg/browser"
 	"github.com/veandco/go-sdl2/gfx"
 	"github.com/veandco/go-sdl2/sdl"
 	"golang.design/x/clipboard"
@@ -54,6 +55,8 @@ const (
 	GUISubBoardColor   = "Sub-Page Color"
 	GUILinkColor       = "Link Color"
 	GUITableColor      = "Table Color"
+	GUIFrameColor      = "Frame Color"
+	GUICodeColor       = "Code Color"
 )
 
 const (
@@ -1319,6 +1322,23 @@ type Label struct {
 	MousedOver          bool
 
 	MultiEditing bool
+
+	// Style is the style all of the Label's text is rendered in.
+	Style TextStyle
+	// If Markdown is true, the Label's text is rendered as Markdown while it isn't being edited.
+	Markdown bool
+	// If Highlight is set, it's used to split the Label's text into styled runs while it isn't being edited.
+	Highlight func(text string) []TextRun
+	// If Checklist is true, inline checklist items (lines starting with "[ ]" or "[x]") are displayed with checkboxes that can be clicked on
+	// while the Label isn't being edited.
+	Checklist    bool
+	renderedText []rune // The text as displayed after rendering Markdown or highlighting, or nil if the Label's text is displayed as-is
+
+	// If SpellCheck is true, misspelled words are underlined while the Label is being edited; right-clicking on one offers corrections.
+	SpellCheck           bool
+	misspellings         []SpellingError
+	spellCheckedText     string
+	spellCheckGeneration int
 }
 
 // NewLabel creates a new Label object. a rect of nil means the Label will default to a rectangle of the necessary size to fully display the text given.
@@ -1401,6 +1421,10 @@ func (label *Label) Update() {
 			label.EndEditing()
 		}
 
+		if label.renderedText != nil && globals.State == StateNeutral {
+			label.updateLinks()
+		}
+
 		if label.Editable && (globals.State == StateNeutral || (globals.State == StateTextEditing && label.Editing)) {
 
 			if !label.Editing && ClickedInRect(activeRect, label.WorldSpace) && globals.Mouse.Button(sdl.BUTTON_LEFT).PressedTimes(2) {
@@ -1617,7 +1641,7 @@ func (label *Label) Update() {
 								}
 
 								cIndex++
-								pos.X += float32(globals.TextRenderer.Glyph(c).Width())
+								pos.X += float32(globals.TextRenderer.StyledGlyph(c, label.Style).Width())
 
 							}
 
@@ -1680,6 +1704,16 @@ func (label *Label) Update() {
 						label.Selection.SelectAll()
 					}
 
+					if rightButton := globals.Mouse.Button(sdl.BUTTON_RIGHT); label.SpellCheck && rightButton.Pressed() {
+						for _, misspelling := range label.misspellings {
+							if rect, ok := label.misspellingRect(misspelling); ok && mousePos.Inside(rect) {
+								rightButton.Consume()
+								globals.SpellChecker.OpenMenu(label, misspelling)
+								break
+							}
+						}
+					}
+
 				}
 
 				if globals.Keyboard.Key(sdl.K_BACKSPACE).Pressed() {
@@ -1855,6 +1889,11 @@ func (label *Label) BeginEditing() {
 	label.Selection.CaretPos = caretPos
 	globals.State = StateTextEditing
 	globals.editingLabel = label
+
+	// Editing is done on the raw text, so we need its lines to place the caret
+	if label.renderedText != nil {
+		label.RecreateTexture()
+	}
 }
 
 func (label *Label) EndEditing() {
@@ -1873,6 +1912,137 @@ func (label *Label) EndEditing() {
 		globals.editingLabel = nil
 	}
 
+	if label.Markdown || label.Highlight != nil || label.Checklist {
+		label.TextureDirty = true
+	}
+
+}
+
+// updateLinks opens a link in the Label's rendered Markdown text when it's clicked on, or checks off an inline checklist item when its box is.
+func (label *Label) updateLinks() {
+
+	mousePos := globals.Mouse.Position()
+
+	if label.WorldSpace {
+		mousePos = globals.Mouse.WorldPosition()
+	}
+
+	for _, link := range label.RendererResult.Links {
+
+		rect := &sdl.FRect{label.Rect.X + label.Offset.X + link.Rect.X, label.Rect.Y + label.Offset.Y + link.Rect.Y, link.Rect.W, link.Rect.H}
+
+		if mousePos.Inside(rect) && mousePos.Inside(label.Rect) {
+
+			globals.Mouse.SetCursor(CursorHand)
+
+			if globals.Mouse.Button(sdl.BUTTON_LEFT).Pressed() {
+				globals.Mouse.Button(sdl.BUTTON_LEFT).Consume()
+				if strings.HasPrefix(link.URL, checklistLinkPrefix) {
+					if line, err := strconv.Atoi(strings.TrimPrefix(link.URL, checklistLinkPrefix)); err == nil {
+						label.SetText([]rune(ToggleChecklistItem(label.TextAsString(), line)))
+					}
+				} else {
+					browser.OpenURL(link.URL)
+				}
+			}
+
+			break
+
+		}
+
+	}
+
+}
+
+// updateMisspellings checks the Label's text for misspelled words if the text (or the dictionary) changed since it was last checked.
+func (label *Label) updateMisspellings() {
+
+	generation := globals.SpellChecker.Generation()
+
+	if label.misspellings != nil && label.spellCheckedText == label.TextAsString() && label.spellCheckGeneration == generation {
+		return
+	}
+
+	label.spellCheckedText = label.TextAsString()
+	label.spellCheckGeneration = generation
+	label.misspellings = globals.SpellChecker.Misspellings(label.Text)
+
+}
+
+// misspellingRect returns the area a misspelled word covers, and if it could be found (i.e. the text hasn't changed since it was checked).
+func (label *Label) misspellingRect(misspelling SpellingError) (*sdl.FRect, bool) {
+
+	if misspelling.End > len(label.Text) {
+		return nil, false
+	}
+
+	start := label.IndexToWorld(misspelling.Start)
+	end := label.IndexToWorld(misspelling.End)
+
+	if start.Y != end.Y {
+		return nil, false
+	}
+
+	return &sdl.FRect{start.X, start.Y, end.X - start.X, globals.GridSize}, true
+
+}
+
+// drawMisspellings draws a wavy line under each misspelled word in the Label.
+func (label *Label) drawMisspellings() {
+
+	label.updateMisspellings()
+
+	for _, misspelling := range label.misspellings {
+
+		rect, ok := label.misspellingRect(misspelling)
+		if !ok {
+			continue
+		}
+
+		baseline := rect.Y + rect.H - 4
+		prev := Point{rect.X, baseline}
+		up := true
+
+		for x := rect.X + 4; x <= rect.X+rect.W; x += 4 {
+
+			next := Point{x, baseline}
+			if up {
+				next.Y -= 3
+			}
+			up = !up
+
+			start, end := prev, next
+			if label.WorldSpace {
+				start = globals.Project.Camera.TranslatePoint(start)
+				end = globals.Project.Camera.TranslatePoint(end)
+			}
+
+			ThickLine(start, end, 2, ColorRed)
+			prev = next
+
+		}
+
+	}
+
+}
+
+// ReplaceMisspelling replaces a misspelled word in the Label's text with the given correction.
+func (label *Label) ReplaceMisspelling(misspelling SpellingError, correction string) {
+
+	// The text could have changed since the word was found
+	if misspelling.End > len(label.Text) || string(label.Text[misspelling.Start:misspelling.End]) != misspelling.Word {
+		return
+	}
+
+	text := append([]rune{}, label.Text[:misspelling.Start]...)
+	text = append(text, []rune(correction)...)
+	text = append(text, label.Text[misspelling.End:]...)
+
+	label.SetText(text)
+
+	caretPos := misspelling.Start + len([]rune(correction))
+	label.Selection.Select(caretPos, caretPos)
+
 }
 
 func (label *Label) Draw() {
@@ -1880,6 +2050,12 @@ func (label *Label) Draw() {
 	// Recreating the texture is only necessary of the texture is dirty; this flag ensures that
 	// doing two operations on the Label (i.e. setting the Label's Rectangle size and setting its text)
 	// don't necessitate two recreations of its underlying texture
+
+	// Colored text has the font color rendered into it, so it has to be rendered again when the theme changes
+	if label.RendererResult != nil && label.RendererResult.Colored && !label.RendererResult.FontColor.Equals(getThemeColor(GUIFontColor)) {
+		label.TextureDirty = true
+	}
+
 	if label.TextureDirty {
 		label.RecreateTexture()
 		if label.OnChange != nil && label.textChanged {
@@ -1899,9 +2075,25 @@ func (label *Label) Draw() {
 
 		lineY := float32(0)
 
+		nextBreak := label.IndexOfRunes(0, "\n")
+
+		// Rendered Markdown has its own lines; headings are already set apart, so they don't get a line under them
+		if label.renderedText != nil {
+			nextBreak = -1
+			for i, c := range label.renderedText {
+				if c == '\n' {
+					nextBreak = i
+					break
+				}
+			}
+			if firstLine := strings.SplitN(label.TextAsString(), "\n", 2)[0]; label.Markdown && IsMarkdownHeading(firstLine) {
+				thickness = 0
+			}
+		}
+
 		// if nextBreak := strings.Index(label.TextAsString(), "\n"); nextBreak >= 0 {
 
-		if nextBreak := label.IndexOfRunes(0, "\n"); nextBreak >= 0 {
+		if nextBreak >= 0 {
 			lineY = label.IndexToWorld(nextBreak).Y + globals.GridSize
 		} else {
 			lineY = label.Rect.Y + label.RendererResult.TextSize.Y + thickness
@@ -1918,7 +2110,9 @@ func (label *Label) Draw() {
 			end = globals.Project.Camera.TranslatePoint(end)
 		}
 
-		ThickLine(start, end, int32(thickness), getThemeColor(GUIFontColor))
+		if thickness > 0 {
+			ThickLine(start, end, int32(thickness), getThemeColor(GUIFontColor))
+		}
 
 	}
 
@@ -1937,7 +2131,7 @@ func (label *Label) Draw() {
 			for i := start; i < end; i++ {
 
 				pos := label.IndexToWorld(i)
-				glyph := globals.TextRenderer.Glyph(label.Text[i])
+				glyph := globals.TextRenderer.StyledGlyph(label.Text[i], label.Style)
 				if glyph == nil {
 					continue
 				}
@@ -1967,6 +2161,10 @@ func (label *Label) Draw() {
 			ThickLine(pos, pos.Add(Point{0, globals.GridSize}), 4, getThemeColor(GUIFontColor))
 		}
 
+		if label.SpellCheck {
+			label.drawMisspellings()
+		}
+
 		if mousePos.Inside(label.Rect) {
 			globals.Mouse.SetCursor(CursorCaret)
 		}
@@ -2002,6 +2200,11 @@ func (label *Label) Draw() {
 
 		color := getThemeColor(GUIFontColor)
 
+		// Colored text already has its colors rendered into the texture
+		if label.RendererResult.Colored {
+			color = ColorWhite
+		}
+
 		if label.MousedOver {
 			t := (1 + math.Sin(globals.Time*math.Pi*2)) * 0.5
 			color = color.Mix(color.Invert(), t*0.75)
@@ -2141,7 +2344,30 @@ func (label *Label) RecreateTexture() {
 		size = Point{label.Rect.W, label.Rect.H}
 	}
 
-	label.RendererResult = globals.TextRenderer.RenderText(string(label.Text), size, label.HorizontalAlignment)
+	var runs []TextRun
+
+	if !label.Editing {
+		if label.Markdown {
+			runs = ParseMarkdown(string(label.Text))
+		} else if label.Highlight != nil {
+			runs = label.Highlight(string(label.Text))
+		} else if label.Checklist {
+			runs = ChecklistRuns(string(label.Text))
+		}
+	}
+
+	if runs != nil {
+		label.renderedText = []rune{}
+		for i := range runs {
+			runs[i].Style |= label.Style
+			label.renderedText = append(label.renderedText, []rune(runs[i].Text)...)
+		}
+	} else {
+		runs = []TextRun{{Text: string(label.Text), Style: label.Style}}
+		label.renderedText = nil
+	}
+
+	label.RendererResult = globals.TextRenderer.RenderStyledText(runs, size, label.HorizontalAlignment)
 
 	if label.maxSize.X > 0 {
 		label.Rect.W = label.maxSize.X
@@ -2251,8 +2477,10 @@ func (label *Label) IndexToWorld(index int) Point {
 			if char == '\n' {
 				point.X = 0
 				point.Y += globals.GridSize
-			} else {
-				point.X += float32(globals.TextRenderer.Glyph(char).Width())
+			} else if char == TextIconRune {
+				point.X += globals.GridSize
+			} else if glyph := globals.TextRenderer.StyledGlyph(char, label.Style); glyph != nil {
+				point.X += float32(glyph.Width())
 			}
 			index--
 
@@ -2302,6 +2530,46 @@ func (label *Label) LineNumber(textIndex int) int {
 	return len(label.RendererResult.TextLines) - 1
 }
 
+// LineNumbers returns the line number (starting from 1) of each line of text the Label displays, or 0 for lines that were wrapped from the
+// line before them.
+func (label *Label) LineNumbers() []int {
+
+	numbers := []int{}
+
+	if label.RendererResult == nil {
+		return numbers
+	}
+
+	text := label.Text
+	if label.renderedText != nil {
+		text = label.renderedText
+	}
+
+	lineNumber := 1
+	wrapped := false
+	end := 0
+
+	for _, line := range label.RendererResult.TextLines {
+
+		if wrapped {
+			numbers = append(numbers, 0)
+		} else {
+			numbers = append(numbers, lineNumber)
+		}
+
+		// Lines that were wrapped end where a space was in the text, rather than a newline
+		end += len(line)
+		wrapped = end > 0 && end <= len(text) && text[end-1] != '\n'
+		if !wrapped {
+			lineNumber++
+		}
+
+	}
+
+	return numbers
+
+}
+
 func (label *Label) LineCount() int {
 	if label.RendererResult != nil {
 		return len(label.RendererResult.TextLines)
@@ -94,7 +94,7 @@ func (hier *Hierarchy) AddCard(card *Card) {
 
 }
 
-func (hier *Hierarchy) Rows(sorting, filter int) []*ContainerRow {
+func (hier *Hierarchy) Rows(sorting, filter int, tags []string) []*ContainerRow {
 
 	rows := []*ContainerRow{}
 
@@ -132,6 +132,10 @@ func (hier *Hierarchy) Rows(sorting, filter int) []*ContainerRow {
 				continue
 			}
 
+			if !card.HasTags(tags...) {
+				continue
+			}
+
 			if card.Valid && category.Expanded {
 
 				pageRows = append(pageRows, listElement)
@@ -213,6 +217,24 @@ func (hier *Hierarchy) Rows(sorting, filter int) []*ContainerRow {
 
 			})
 
+		} else if sorting == 3 {
+
+			// Highest priority first; Cards of the same priority are sorted by position
+			sort.SliceStable(pageRows, func(i, j int) bool {
+
+				a := pageRows[i].Card
+				b := pageRows[j].Card
+
+				if a.Priority() != b.Priority() {
+					return a.Priority() > b.Priority()
+				}
+				if a.Rect.Y == b.Rect.Y {
+					return a.Rect.X < b.Rect.X
+				}
+				return a.Rect.Y < b.Rect.Y
+
+			})
+
 		}
 
 		for _, r := range pageRows {
@@ -0,0 +1,432 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	KanbanColumnsByStatus = iota
+	KanbanColumnsByTag
+)
+
+const (
+	kanbanHeaderHeight = 32
+	kanbanTileHeight   = 40
+	kanbanTileSpacing  = 4
+	kanbanMinimumWidth = 192
+)
+
+type kanbanTile struct {
+	Card   *Card
+	Column *kanbanColumn
+	Rect   *sdl.FRect
+}
+
+type kanbanColumn struct {
+	Name  string
+	Tag   string // The tag the column represents when columns are by tag; empty for the "Untagged" column
+	Tiles []*kanbanTile
+	Rect  *sdl.FRect
+}
+
+// Kanban is a MenuElement that shows Cards from all Pages as compact tiles sorted into columns, either by their status (not started,
+// in progress, or done), or by their tags. Dragging a tile to another column changes the Card's status or tags accordingly; clicking
+// a tile focuses on its Card. The Cards themselves aren't moved on their Pages.
+type Kanban struct {
+	Rect    *sdl.FRect
+	Columns int
+	Scroll  Point
+
+	columns     []*kanbanColumn
+	dragTile    *kanbanTile
+	dragOrigin  Point
+	dragScroll  Point
+	dragPanning bool
+	dragged     bool
+}
+
+func NewKanban(rect *sdl.FRect) *Kanban {
+	return &Kanban{
+		Rect:    rect,
+		Columns: KanbanColumnsByStatus,
+	}
+}
+
+func (kanban *Kanban) Update() {
+
+	kanban.columns = kanban.buildColumns()
+	kanban.layout()
+
+	pos := globals.Mouse.Position()
+	button := globals.Mouse.Button(sdl.BUTTON_LEFT)
+
+	if pos.Inside(kanban.Rect) {
+
+		if wheel := globals.Mouse.Wheel(); wheel != 0 {
+			kanban.Scroll.Y -= wheel * kanbanTileHeight
+			globals.Mouse.wheel = 0 // Consume the wheel movement
+		}
+
+		hovered := kanban.tileAt(pos)
+
+		if hovered != nil {
+			globals.Mouse.SetCursor(CursorHand)
+		}
+
+		if button.Pressed() {
+			button.Consume()
+			kanban.dragTile = hovered
+			kanban.dragPanning = hovered == nil
+			kanban.dragOrigin = pos
+			kanban.dragScroll = kanban.Scroll
+			kanban.dragged = false
+		}
+
+	}
+
+	if kanban.dragTile != nil || kanban.dragPanning {
+
+		delta := pos.Sub(kanban.dragOrigin)
+
+		if math.Abs(float64(delta.X)) > 4 || math.Abs(float64(delta.Y)) > 4 {
+			kanban.dragged = true
+		}
+
+		if kanban.dragPanning {
+			kanban.Scroll = kanban.dragScroll.Sub(delta)
+		}
+
+		if kanban.dragged {
+			globals.Mouse.SetCursor(CursorHandGrab)
+		}
+
+		if !button.HeldRaw() {
+
+			if kanban.dragTile != nil {
+
+				if !kanban.dragged {
+					card := kanban.dragTile.Card
+					card.Page.Project.Camera.FocusOn(false, card)
+					card.Page.Selection.Clear()
+					card.Page.Selection.Add(card)
+				} else if column := kanban.columnAt(pos); column != nil && column.Name != kanban.dragTile.Column.Name {
+					kanban.moveTile(kanban.dragTile, column)
+				}
+
+			}
+
+			kanban.dragTile = nil
+			kanban.dragPanning = false
+
+		}
+
+	}
+
+	kanban.layout()
+
+}
+
+// layout positions the columns and their tiles, clamping the scroll values as necessary.
+func (kanban *Kanban) layout() {
+
+	if len(kanban.columns) == 0 {
+		return
+	}
+
+	columnWidth := float32(math.Max(float64(kanban.Rect.W/float32(len(kanban.columns))), kanbanMinimumWidth))
+
+	tallest := 0
+	for _, column := range kanban.columns {
+		if len(column.Tiles) > tallest {
+			tallest = len(column.Tiles)
+		}
+	}
+
+	maxScrollX := float32(math.Max(0, float64(columnWidth*float32(len(kanban.columns))-kanban.Rect.W)))
+	maxScrollY := float32(math.Max(0, float64(float32(tallest*(kanbanTileHeight+kanbanTileSpacing))-(kanban.Rect.H-kanbanHeaderHeight))))
+
+	kanban.Scroll.X = float32(math.Min(math.Max(float64(kanban.Scroll.X), 0), float64(maxScrollX)))
+	kanban.Scroll.Y = float32(math.Min(math.Max(float64(kanban.Scroll.Y), 0), float64(maxScrollY)))
+
+	for c, column := range kanban.columns {
+
+		column.Rect = &sdl.FRect{kanban.Rect.X + float32(c)*columnWidth - kanban.Scroll.X, kanban.Rect.Y, columnWidth, kanban.Rect.H}
+
+		for t, tile := range column.Tiles {
+			tile.Rect = &sdl.FRect{
+				column.Rect.X + kanbanTileSpacing,
+				column.Rect.Y + kanbanHeaderHeight + kanbanTileSpacing + float32(t*(kanbanTileHeight+kanbanTileSpacing)) - kanban.Scroll.Y,
+				columnWidth - (kanbanTileSpacing * 2),
+				kanbanTileHeight,
+			}
+		}
+
+	}
+
+}
+
+func (kanban *Kanban) Draw() {
+
+	rect := &sdl.Rect{int32(kanban.Rect.X), int32(kanban.Rect.Y), int32(kanban.Rect.W), int32(kanban.Rect.H)}
+
+	// Combine our clipping rectangle with the Container's, so we don't draw outside of it
+	if len(globals.ClipRects) > 0 {
+		if clipped, ok := rect.Intersect(globals.ClipRects[len(globals.ClipRects)-1]); ok {
+			rect = &clipped
+		} else {
+			return
+		}
+	}
+
+	globals.Renderer.SetClipRect(rect)
+	globals.ClipRects = append(globals.ClipRects, rect)
+
+	fontColor := getThemeColor(GUIFontColor)
+	menuColor := getThemeColor(GUIMenuColor)
+
+	FillRect(kanban.Rect.X, kanban.Rect.Y, kanban.Rect.W, kanban.Rect.H, getThemeColor(GUIBGColor))
+
+	pos := globals.Mouse.Position()
+
+	for c, column := range kanban.columns {
+
+		if c > 0 {
+			lineColor := fontColor.Clone()
+			lineColor[3] = 64
+			FillRect(column.Rect.X, column.Rect.Y, 1, column.Rect.H, lineColor)
+		}
+
+		// Highlight the column a tile would be dropped into
+		if kanban.dragTile != nil && kanban.dragged && column.Name != kanban.dragTile.Column.Name && pos.Inside(column.Rect) {
+			highlight := menuColor.Accent()
+			highlight[3] = 64
+			FillRect(column.Rect.X, column.Rect.Y, column.Rect.W, column.Rect.H, highlight)
+		}
+
+		for _, tile := range column.Tiles {
+			// Columns are rebuilt every frame, so the dragged tile is found by its Card and column
+			if !kanban.dragged || kanban.dragTile == nil || tile.Card != kanban.dragTile.Card || column.Name != kanban.dragTile.Column.Name {
+				kanban.drawTile(tile, tile.Rect)
+			}
+		}
+
+	}
+
+	// Headers, drawn after the tiles so they scroll underneath
+
+	for _, column := range kanban.columns {
+		FillRect(column.Rect.X, column.Rect.Y, column.Rect.W, kanbanHeaderHeight, menuColor)
+		globals.TextRenderer.QuickRenderText(column.Name+" ("+strconv.Itoa(len(column.Tiles))+")", Point{column.Rect.X + column.Rect.W/2, column.Rect.Y + 4}, 0.75, fontColor, nil, AlignCenter)
+	}
+
+	if kanban.dragTile != nil && kanban.dragged {
+		dragRect := *kanban.dragTile.Rect
+		dragRect.X += pos.X - kanban.dragOrigin.X
+		dragRect.Y += pos.Y - kanban.dragOrigin.Y
+		kanban.drawTile(kanban.dragTile, &dragRect)
+	}
+
+	globals.ClipRects[len(globals.ClipRects)-1] = nil
+	globals.ClipRects = globals.ClipRects[:len(globals.ClipRects)-1]
+	if len(globals.ClipRects) > 0 {
+		globals.Renderer.SetClipRect(globals.ClipRects[len(globals.ClipRects)-1])
+	} else {
+		globals.Renderer.SetClipRect(nil)
+	}
+
+}
+
+func (kanban *Kanban) drawTile(tile *kanbanTile, rect *sdl.FRect) {
+
+	card := tile.Card
+	fontColor := getThemeColor(GUIFontColor)
+
+	color := card.Color()
+	if card.Completed() {
+		color[3] = 96
+	}
+
+	FillRect(rect.X, rect.Y, rect.W, rect.H, color)
+
+	if priority := card.Priority(); priority != PriorityNone {
+		FillRect(rect.X+rect.W-4, rect.Y, 4, rect.H, priorityColors[priority])
+	}
+
+	if card.selected {
+		ThickRect(int32(rect.X), int32(rect.Y), int32(rect.W), int32(rect.H), 3, fontColor)
+	} else {
+		globals.Renderer.SetDrawColor(fontColor.RGBA())
+		globals.Renderer.DrawRectF(rect)
+	}
+
+	textColor := ColorBlack
+	if color.IsDark() {
+		textColor = ColorWhite
+	}
+
+	globals.TextRenderer.QuickRenderText(strings.ReplaceAll(card.Name(), "\n", " - "), Point{rect.X + 4, rect.Y + 2}, 0.5, textColor, nil, AlignLeft)
+	globals.TextRenderer.QuickRenderText(card.Page.Path(), Point{rect.X + 4, rect.Y + rect.H/2 + 2}, 0.5, textColor.Mix(color, 0.4), nil, AlignLeft)
+
+}
+
+func (kanban *Kanban) Rectangle() *sdl.FRect {
+	return kanban.Rect
+}
+
+func (kanban *Kanban) SetRectangle(rect *sdl.FRect) {
+	kanban.Rect = rect
+}
+
+func (kanban *Kanban) Destroy() {}
+
+func (kanban *Kanban) tileAt(pos Point) *kanbanTile {
+
+	if pos.Y < kanban.Rect.Y+kanbanHeaderHeight {
+		return nil
+	}
+
+	for _, column := range kanban.columns {
+		for _, tile := range column.Tiles {
+			if pos.Inside(tile.Rect) {
+				return tile
+			}
+		}
+	}
+
+	return nil
+
+}
+
+func (kanban *Kanban) columnAt(pos Point) *kanbanColumn {
+
+	for _, column := range kanban.columns {
+		if pos.Inside(column.Rect) {
+			return column
+		}
+	}
+
+	return nil
+
+}
+
+// moveTile changes the tile's Card so that it belongs in the given column.
+func (kanban *Kanban) moveTile(tile *kanbanTile, column *kanbanColumn) {
+
+	card := tile.Card
+
+	if kanban.Columns == KanbanColumnsByStatus {
+
+		for status, name := range statusNames {
+			if name == column.Name {
+				card.SetStatus(status)
+			}
+		}
+
+	} else {
+
+		card.SetTags(kanbanMovedTags(card.Tags(), tile.Column.Tag, column.Tag)...)
+
+	}
+
+	// Capture the undo state immediately, as the Card may not be on the current Page
+	card.HandleUndos()
+
+	globals.EventLog.Log("Moved [%s] to %s.", false, card.Name(), column.Name)
+
+}
+
+// kanbanMovedTags returns the tags a Card should have after moving it from the column for one tag to the column for another. Moving to
+// the "Untagged" column (with an empty tag) clears the Card's tags; otherwise, the tag of the column it was in is swapped for the new one.
+func kanbanMovedTags(tags []string, fromTag, toTag string) []string {
+
+	moved := []string{}
+
+	if toTag == "" {
+		return moved
+	}
+
+	for _, tag := range tags {
+		if !strings.EqualFold(tag, fromTag) {
+			moved = append(moved, tag)
+		}
+	}
+
+	if !TagsContain(moved, toTag) {
+		moved = append(moved, toTag)
+	}
+
+	return moved
+
+}
+
+func (kanban *Kanban) buildColumns() []*kanbanColumn {
+
+	columns := []*kanbanColumn{}
+
+	if kanban.Columns == KanbanColumnsByStatus {
+		for _, name := range statusNames {
+			columns = append(columns, &kanbanColumn{Name: name})
+		}
+	} else if globals.Project != nil {
+		columns = append(columns, &kanbanColumn{Name: "Untagged"})
+		for _, tag := range globals.Project.Tags() {
+			columns = append(columns, &kanbanColumn{Name: tag, Tag: tag})
+		}
+	}
+
+	if globals.Project == nil {
+		return columns
+	}
+
+	for _, page := range globals.Project.Pages {
+
+		if !page.Valid() {
+			continue
+		}
+
+		for _, card := range page.Cards {
+
+			if kanban.Columns == KanbanColumnsByStatus {
+
+				if card.Completable() {
+					column := columns[card.Status()]
+					column.Tiles = append(column.Tiles, &kanbanTile{Card: card, Column: column})
+				}
+
+			} else {
+
+				tags := card.Tags()
+
+				if len(tags) == 0 {
+					if card.Completable() {
+						columns[0].Tiles = append(columns[0].Tiles, &kanbanTile{Card: card, Column: columns[0]})
+					}
+					continue
+				}
+
+				for _, column := range columns[1:] {
+					if TagsContain(tags, column.Tag) {
+						column.Tiles = append(column.Tiles, &kanbanTile{Card: card, Column: column})
+					}
+				}
+
+			}
+
+		}
+
+	}
+
+	for _, column := range columns {
+		sort.SliceStable(column.Tiles, func(i, j int) bool {
+			return column.Tiles[i].Card.Priority() > column.Tiles[j].Card.Priority()
+		})
+	}
+
+	return columns
+
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKanbanMovedTags(t *testing.T) {
+
+	tests := []struct {
+		tags    []string
+		fromTag string
+		toTag   string
+		want    []string
+	}{
+		{[]string{"todo"}, "todo", "doing", []string{"doing"}},
+		{[]string{"urgent", "todo"}, "todo", "doing", []string{"urgent", "doing"}},
+
+		// Column tags come from the project's tags, which may differ in case from the Card's own
+		{[]string{"ToDo", "urgent"}, "todo", "doing", []string{"urgent", "doing"}},
+		{[]string{"todo", "Doing"}, "todo", "doing", []string{"Doing"}},
+		{[]string{"todo", "doing"}, "todo", "DOING", []string{"doing"}},
+
+		// Moving from the "Untagged" column adds the tag; moving to it clears them all
+		{[]string{}, "", "todo", []string{"todo"}},
+		{[]string{"todo", "urgent"}, "todo", "", []string{}},
+	}
+
+	for _, test := range tests {
+		if got := kanbanMovedTags(test.tags, test.fromTag, test.toTag); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("kanbanMovedTags(%q, %q, %q) = %q, want %q", test.tags, test.fromTag, test.toTag, got, test.want)
+		}
+	}
+
+}
@@ -59,6 +59,7 @@ const (
 	KBNewMapCard      = "New Map Card"
 	KBNewSubpageCard  = "New Sub-Page Card"
 	KBNewLinkCard     = "New Link Card"
+	KBNewTableCard    = "New Table Card"
 
 	KBAddToSelection      = "Multi-Edit / Add to Selection Modifier"
 	KBRemoveFromSelection = "Remove From Selection Modifier"
@@ -78,9 +79,11 @@ const (
 	KBOpenContextMenu     = "Open Context Menu"
 	KBResizeMultiple      = "Resize Multiple Cards Modifier"
 
-	KBCollapseCard = "Card: Collapse"
-	KBLinkCard     = "Card: Connect Cards"
-	KBUnlinkCard   = "Card: Disconnect From All Cards"
+	KBCollapseCard       = "Card: Collapse"
+	KBLinkCard           = "Card: Connect Cards"
+	KBUnlinkCard         = "Card: Disconnect From All Cards"
+	KBCycleCardPriority  = "Card: Cycle Priority"
+	KBToggleTimeTracking = "Card: Start / Stop Time Tracking"
 
 	KBCopyText      = "Textbox: Copy Selected Text"
 	KBCutText       = "Textbox: Cut Selected Text"
@@ -475,6 +478,7 @@ func (kb *Keybindings) Default() {
 	kb.DefineKeyShortcut(KBNewMapCard, sdl.K_7, sdl.K_LSHIFT)
 	kb.DefineKeyShortcut(KBNewSubpageCard, sdl.K_8, sdl.K_LSHIFT)
 	kb.DefineKeyShortcut(KBNewLinkCard, sdl.K_9, sdl.K_LSHIFT)
+	kb.DefineKeyShortcut(KBNewTableCard, sdl.K_0, sdl.K_LSHIFT)
 
 	kb.DefineKeyShortcut(KBAddToSelection, sdl.K_LSHIFT).triggerMode = TriggerModeHold
 	kb.DefineKeyShortcut(KBRemoveFromSelection, sdl.K_LALT).triggerMode = TriggerModeHold
@@ -500,6 +504,8 @@ func (kb *Keybindings) Default() {
 	kb.DefineKeyShortcut(KBSwitchWrapMode, sdl.K_w, sdl.K_LCTRL)
 
 	kb.DefineKeyShortcut(KBCollapseCard, sdl.K_c, sdl.K_LSHIFT)
+	kb.DefineKeyShortcut(KBCycleCardPriority, sdl.K_p, sdl.K_LSHIFT)
+	kb.DefineKeyShortcut(KBToggleTimeTracking, sdl.K_t, sdl.K_LSHIFT)
 
 	kb.DefineKeyShortcut(KBUndo, sdl.K_z, sdl.K_LCTRL)
 	kb.DefineKeyShortcut(KBRedo, sdl.K_z, sdl.K_LCTRL, sdl.K_LSHIFT)
@@ -264,6 +264,7 @@ func main() {
 	globals.Dispatcher = NewDispatcher()
 
 	globals.TextRenderer = NewTextRenderer()
+	globals.SpellChecker = NewSpellChecker()
 	screenWidth, screenHeight, _ := globals.Renderer.GetOutputSize()
 	globals.ScreenSize = Point{float32(screenWidth), float32(screenHeight)}
 
@@ -1118,7 +1119,7 @@ func ConstructMenus() {
 
 	// View Menu
 
-	viewMenu := globals.MenuSystem.Add(NewMenu(&sdl.FRect{48, 48, 300, 250}, MenuCloseClickOut), "view", false)
+	viewMenu := globals.MenuSystem.Add(NewMenu(&sdl.FRect{48, 48, 300, 370}, MenuCloseClickOut), "view", false)
 	root = viewMenu.Pages["root"]
 
 	root.AddRow(AlignCenter).Add("Create Menu", NewButton("Create", nil, nil, false, func() {
@@ -1156,6 +1157,21 @@ func ConstructMenus() {
 		viewMenu.Close()
 	}))
 
+	root.AddRow(AlignCenter).Add("Smart Views", NewButton("Smart Views", nil, nil, false, func() {
+		globals.MenuSystem.Get("smart views").Open()
+		viewMenu.Close()
+	}))
+
+	root.AddRow(AlignCenter).Add("Timeline", NewButton("Timeline", nil, nil, false, func() {
+		globals.MenuSystem.Get("timeline").Open()
+		viewMenu.Close()
+	}))
+
+	root.AddRow(AlignCenter).Add("Kanban", NewButton("Kanban", nil, nil, false, func() {
+		globals.MenuSystem.Get("kanban").Open()
+		viewMenu.Close()
+	}))
+
 	loadRecent := globals.MenuSystem.Add(NewMenu(&sdl.FRect{128, 96, 512, 128}, MenuCloseClickOut), "load recent", false)
 	loadRecent.OnOpen = func() {
 
@@ -1272,12 +1288,25 @@ func ConstructMenus() {
 		globals.Project.CurrentPage.Selection.Add(card)
 	}))
 
-	// root.AddRow(AlignCenter).Add("create new table", NewButton("Table", nil, icons[ContentTypeTable], false, func() {
-	// 	card := globals.Project.CurrentPage.CreateNewCard(ContentTypeTable)
-	// 	placeCardInStack(card, true)
-	// 	globals.Project.CurrentPage.Selection.Clear()
-	// 	globals.Project.CurrentPage.Selection.Add(card)
-	// }))
+	root.AddRow(AlignCenter).Add("create new table", NewButton("Table", nil, icons[ContentTypeTable], false, func() {
+		card := globals.Project.CurrentPage.CreateNewCard(ContentTypeTable)
+		placeCardInStack(card, true)
+		globals.Project.CurrentPage.Selection.Clear()
+		globals.Project.CurrentPage.Selection.Add(card)
+	}))
+
+	root.AddRow(AlignCenter).Add("create new frame", NewButton("Frame", nil, icons[ContentTypeFrame], false, func() {
+		card := globals.Project.CurrentPage.CreateNewCard(ContentTypeFrame)
+		globals.Project.CurrentPage.Selection.Clear()
+		globals.Project.CurrentPage.Selection.Add(card)
+	}))
+
+	root.AddRow(AlignCenter).Add("create new code", NewButton("Code", nil, icons[ContentTypeCode], false, func() {
+		card := globals.Project.CurrentPage.CreateNewCard(ContentTypeCode)
+		placeCardInStack(card, true)
+		globals.Project.CurrentPage.Selection.Clear()
+		globals.Project.CurrentPage.Selection.Add(card)
+	}))
 
 	createMenu.Recreate(createMenu.Pages["root"].IdealSize().X+64, createMenu.Pages["root"].IdealSize().Y+16)
 
@@ -1300,9 +1329,24 @@ func ConstructMenus() {
 	root.AddRow(AlignCenter).Add("set deadline", NewButton("Set Deadline", nil, nil, false, func() {
 		editMenu.SetPage("set deadline")
 	}))
+	root.AddRow(AlignCenter).Add("set recurrence", NewButton("Set Recurrence", nil, nil, false, func() {
+		editMenu.SetPage("set recurrence")
+	}))
+	root.AddRow(AlignCenter).Add("dependencies", NewButton("Dependencies", nil, nil, false, func() {
+		editMenu.SetPage("dependencies")
+	}))
+	root.AddRow(AlignCenter).Add("set priority", NewButton("Set Priority", nil, nil, false, func() {
+		editMenu.SetPage("set priority")
+	}))
+	root.AddRow(AlignCenter).Add("set tags", NewButton("Set Tags", nil, nil, false, func() {
+		editMenu.SetPage("set tags")
+	}))
 	root.AddRow(AlignCenter).Add("add icons", NewButton("Add Icons", nil, nil, false, func() {
 		editMenu.SetPage("add icons")
 	}))
+	root.AddRow(AlignCenter).Add("frame selection", NewButton("Frame Selection", nil, nil, false, func() {
+		globals.Project.CurrentPage.FrameSelection()
+	}))
 
 	setColor := editMenu.AddPage("set color")
 	setColor.AddRow(AlignCenter).Add("label", NewLabel("Set Color", nil, false, AlignCenter))
@@ -1503,6 +1547,24 @@ func ConstructMenus() {
 		}
 	}))
 
+	setType.AddRow(AlignCenter).Add("set table content type", NewButton("Table", nil, icons[ContentTypeTable], false, func() {
+		for _, card := range globals.Project.CurrentPage.Selection.AsSlice() {
+			card.SetContents(ContentTypeTable)
+		}
+	}))
+
+	setType.AddRow(AlignCenter).Add("set frame content type", NewButton("Frame", nil, icons[ContentTypeFrame], false, func() {
+		for _, card := range globals.Project.CurrentPage.Selection.AsSlice() {
+			card.SetContents(ContentTypeFrame)
+		}
+	}))
+
+	setType.AddRow(AlignCenter).Add("set code content type", NewButton("Code", nil, icons[ContentTypeCode], false, func() {
+		for _, card := range globals.Project.CurrentPage.Selection.AsSlice() {
+			card.SetContents(ContentTypeCode)
+		}
+	}))
+
 	setDeadline := editMenu.AddPage("set deadline")
 	setDeadline.AddRow(AlignCenter).Add("label", NewLabel("Set Deadline", &sdl.FRect{0, 0, 192, 32}, false, AlignCenter))
 
@@ -1571,6 +1633,14 @@ func ConstructMenus() {
 
 	selectedDate := ""
 
+	// The time of day is optional; deadlines without one are due at the end of the day
+	row = setDeadline.AddRow(AlignCenter)
+	row.Add("", NewLabel("Time (HH:MM) : ", nil, false, AlignLeft))
+	deadlineTime := NewLabel("", &sdl.FRect{0, 0, 96, 32}, false, AlignLeft)
+	deadlineTime.Editable = true
+	deadlineTime.RegexString = RegexOnlyDigitsAndColon
+	row.Add("deadline time", deadlineTime)
+
 	row = setDeadline.AddRow(AlignCenter)
 	row.Add("set deadline", NewButton("Set Deadline", nil, nil, false, func() {
 
@@ -1581,15 +1651,26 @@ func ConstructMenus() {
 
 			if selectedDate != "" {
 
+				deadline := selectedDate
+
+				if timeText := strings.TrimSpace(deadlineTime.TextAsString()); timeText != "" {
+					timeOfDay, err := time.Parse(DeadlineTimeFormat, timeText)
+					if err != nil {
+						globals.EventLog.Log("Deadline cannot be set as the time \"%s\" isn't valid; it should be in 24-hour HH:MM format.", false, timeText)
+						return
+					}
+					deadline += " " + timeOfDay.Format(DeadlineTimeFormat)
+				}
+
 				for _, card := range selection {
 					if card.Completable() {
 						completableCount++
-						card.Properties.Get("deadline").Set(selectedDate)
+						card.Properties.Get("deadline").Set(deadline)
 						card.CreateUndoState = true
 					}
 				}
 
-				globals.EventLog.Log("Deadline set on %d complete-able cards to %s.", false, completableCount, selectedDate)
+				globals.EventLog.Log("Deadline set on %d complete-able cards to %s.", false, completableCount, deadline)
 
 			} else {
 				globals.EventLog.Log("Deadline cannot be set as no date is selected.", false)
@@ -1616,6 +1697,51 @@ func ConstructMenus() {
 
 	}))
 
+	row = setDeadline.AddRow(AlignCenter)
+	row.Add("set start date", NewButton("Set Start Date", nil, nil, false, func() {
+
+		selection := globals.Project.CurrentPage.Selection.AsSlice()
+		completableCount := 0
+
+		if len(selection) > 0 {
+
+			if selectedDate != "" {
+
+				for _, card := range selection {
+					if card.Completable() {
+						completableCount++
+						card.Properties.Get("start date").Set(selectedDate)
+						card.CreateUndoState = true
+					}
+				}
+
+				globals.EventLog.Log("Start date set on %d complete-able cards to %s.", false, completableCount, selectedDate)
+
+			} else {
+				globals.EventLog.Log("Start date cannot be set as no date is selected.", false)
+			}
+
+		}
+
+	}))
+
+	row.Add("clear start date", NewButton("Clear Start Date", nil, nil, false, func() {
+
+		selection := globals.Project.CurrentPage.Selection.AsSlice()
+
+		if len(selection) > 0 {
+
+			for _, card := range selection {
+				if card.Properties.Has("start date") {
+					card.Properties.Get("start date").Set("")
+				}
+			}
+
+			globals.EventLog.Log("Start date removed on %d cards.", false, len(selection))
+		}
+
+	}))
+
 	setDeadline.OnDraw = func() {
 
 		setDeadline.FindElement("month label", false).(*Label).SetText([]rune(now.Month().String()[:3]))
@@ -1637,8 +1763,7 @@ func ConstructMenus() {
 
 				if card.selected && card.Properties.Has("deadline") {
 
-					deadline := card.Properties.Get("deadline").AsString()
-					date, _ := time.ParseInLocation("2006-01-02", deadline, now.Location())
+					date, _, _ := ParseDeadline(card.Properties.Get("deadline").AsString())
 					cardToDate[card] = date
 
 				}
@@ -1692,185 +1817,555 @@ func ConstructMenus() {
 
 	setDeadline.OnDraw()
 
-	// Icons Menu
+	// Recurrence
 
-	root = editMenu.AddPage("add icons")
+	setRecurrence := editMenu.AddPage("set recurrence")
+	setRecurrence.AddRow(AlignCenter).Add("label", NewLabel("Set Recurrence", &sdl.FRect{0, 0, 192, 32}, false, AlignCenter))
 
-	root.AddRow(AlignCenter).Add("icons label", NewLabel("Icons", nil, false, AlignCenter))
+	recurrenceKind := NewButtonGroup(&sdl.FRect{0, 0, 384, 64}, false, nil, nil, "Daily", "Weekly", "Monthly", "Every N Days")
+	recurrenceKind.MaxButtonsPerRow = 2
+	setRecurrence.AddRow(AlignCenter).Add("kind", recurrenceKind)
 
-	type iconStruct struct {
-		Image    Image
-		Filepath string
+	recurrenceWeekdayRow := setRecurrence.AddRow(AlignCenter)
+	recurrenceWeekdays := []*Checkbox{}
+
+	for _, weekday := range []string{"S", "M", "T", "W", "T", "F", "S"} {
+		checkbox := NewCheckbox(0, 0, false, nil)
+		recurrenceWeekdays = append(recurrenceWeekdays, checkbox)
+		recurrenceWeekdayRow.Add("", NewLabel(weekday, nil, false, AlignCenter))
+		recurrenceWeekdayRow.Add("", checkbox)
 	}
 
-	iconImgs := []iconStruct{}
+	recurrenceIntervalRow := setRecurrence.AddRow(AlignCenter)
+	recurrenceIntervalRow.Add("", NewLabel("Every : ", nil, false, AlignLeft))
+	recurrenceInterval := NewNumberSpinner(&sdl.FRect{0, 0, 160, 32}, false, nil)
+	recurrenceInterval.MinValue = 1
+	recurrenceInterval.Value = 2
+	recurrenceIntervalRow.Add("interval", recurrenceInterval)
+	recurrenceIntervalRow.Add("", NewLabel(" days", nil, false, AlignLeft))
 
-	filepath.Walk(LocalRelativePath("assets/icons"), func(path string, info fs.FileInfo, err error) error {
+	setRecurrence.AddRow(AlignCenter).Add("set recurrence", NewButton("Set Recurrence", nil, nil, false, func() {
 
-		if filepath.Ext(path) == ".png" {
-			res := globals.Resources.Get(path)
-			res.Destructible = false
-			iconImgs = append(iconImgs, iconStruct{
-				Image:    res.AsImage(),
-				Filepath: path,
-			})
+		rule := &RecurrenceRule{Kind: recurrenceKind.ChosenIndex, Interval: int(recurrenceInterval.Value)}
+
+		if rule.Kind == RecurrenceWeekly {
+			for i, checkbox := range recurrenceWeekdays {
+				if checkbox.Checked {
+					rule.Weekdays = append(rule.Weekdays, time.Weekday(i))
+				}
+			}
 		}
 
-		return nil
-	})
+		completableCount := 0
 
-	for i := 0; i < len(iconImgs); i += 5 {
+		for _, card := range globals.Project.CurrentPage.Selection.AsSlice() {
+			if card.Completable() {
+				completableCount++
+				cardRule := *rule
+				// Monthly Cards recur on the day of the month they're due on
+				if deadline, hasDeadline := card.Deadline(); hasDeadline && rule.Kind == RecurrenceMonthly {
+					cardRule.Day = deadline.Day()
+				}
+				card.Properties.Get("recurrence").Set(cardRule.String())
+			}
+		}
 
-		newRow := root.AddRow(AlignCenter)
+		globals.EventLog.Log("Recurrence set to \"%s\" on %d complete-able card(s).", false, rule.Description(), completableCount)
 
-		for j := 0; j < 5; j++ {
+	}))
 
-			if i+j >= len(iconImgs) {
-				break
-			}
+	setRecurrence.AddRow(AlignCenter).Add("clear recurrence", NewButton("Clear Recurrence", nil, nil, false, func() {
 
-			icon := iconImgs[i+j]
+		selection := globals.Project.CurrentPage.Selection.AsSlice()
 
-			button := NewIconButton(0, 0, &sdl.Rect{0, 0, int32(icon.Image.Size.X), int32(icon.Image.Size.X)}, icon.Image, false, func() {
-				n := globals.Project.CurrentPage.CreateNewCard(ContentTypeImage)
-				n.Rect.X = globals.Project.Camera.Position.X
-				n.Rect.Y = globals.Project.Camera.Position.Y
-				ic := n.Contents.(*ImageContents)
-				ic.LoadFileFrom(icon.Filepath)
-				ic.LoadedImage = true // The size is set already
-				n.Recreate(globals.GridSize, globals.GridSize)
-				n.CreateUndoState = true
-				n.Update()
-				n.HandleUndos()
-			})
-			button.Tint = NewColor(255, 255, 255, 255)
-			button.Scale.X = 2
-			button.Scale.Y = 2
-			newRow.Add("", button)
+		if len(selection) > 0 {
+			for _, card := range selection {
+				if card.Properties.Has("recurrence") {
+					card.Properties.Get("recurrence").Set("")
+				}
+			}
+			globals.EventLog.Log("Recurrence removed on %d card(s).", false, len(selection))
 		}
 
-	}
-
-	// root.AddRow(AlignCenter)
+	}))
 
-	// Context Menu
+	setRecurrence.AddRow(AlignCenter).Add("", NewSpacer(&sdl.FRect{0, 0, 4, 8}))
 
-	contextMenu := globals.MenuSystem.Add(NewMenu(&sdl.FRect{0, 0, 256, 256}, MenuCloseClickOut), "context", false)
-	contextMenu.OnOpen = func() { globals.State = StateContextMenu }
-	contextMenu.OnClose = func() { globals.State = StateNeutral }
-	root = contextMenu.Pages["root"]
+	selectionRecurrence := NewLabel("Selected Cards' Recurrence : ", nil, false, AlignCenter)
+	setRecurrence.AddRow(AlignCenter).Add("selection recurrence", selectionRecurrence)
 
-	root.AddRow(AlignCenter).Add("create card", NewButton("Create Card", &sdl.FRect{0, 0, 192, 32}, nil, false, func() {
-		globals.Project.CurrentPage.CreateNewCard(ContentTypeCheckbox)
-		contextMenu.Close()
-	}))
+	setRecurrence.OnUpdate = func() {
 
-	root.AddRow(AlignCenter).Add("delete cards", NewButton("Delete Cards", &sdl.FRect{0, 0, 192, 32}, nil, false, func() {
-		page := globals.Project.CurrentPage
-		page.DeleteCards(page.Selection.AsSlice()...)
-		contextMenu.Close()
-	}))
+		recurrenceWeekdayRow.Visible = recurrenceKind.ChosenIndex == RecurrenceWeekly
+		recurrenceIntervalRow.Visible = recurrenceKind.ChosenIndex == RecurrenceEveryNDays
 
-	root.AddRow(AlignCenter).Add("copy cards", NewButton("Copy Cards", &sdl.FRect{0, 0, 192, 32}, nil, false, func() {
-		page := globals.Project.CurrentPage
-		page.CopySelectedCards()
-		contextMenu.Close()
-	}))
+		descriptions := []string{}
 
-	root.AddRow(AlignCenter).Add("cut cards", NewButton("Cut Cards", &sdl.FRect{0, 0, 192, 32}, nil, false, func() {
-		page := globals.Project.CurrentPage
-		globals.CopyBuffer.CutMode = true
-		page.CopySelectedCards()
-		contextMenu.Close()
-	}))
+		if globals.Project != nil {
+			for _, card := range globals.Project.CurrentPage.Selection.AsSlice() {
+				if rule := card.Recurrence(); rule != nil && !TagsContain(descriptions, rule.Description()) {
+					descriptions = append(descriptions, rule.Description())
+				}
+			}
+		}
 
-	root.AddRow(AlignCenter).Add("paste cards", NewButton("Paste Cards", &sdl.FRect{0, 0, 192, 32}, nil, false, func() {
-		menuPos := Point{globals.MenuSystem.Get("context").Rect.X, globals.MenuSystem.Get("context").Rect.Y}
-		offset := globals.Mouse.Position().Sub(menuPos)
-		globals.Project.CurrentPage.PasteCards(offset, true)
-		contextMenu.Close()
-	}))
+		if len(descriptions) == 0 {
+			selectionRecurrence.SetText([]rune("Selected Cards' Recurrence : None"))
+		} else {
+			selectionRecurrence.SetText([]rune("Selected Cards' Recurrence : " + strings.Join(descriptions, "; ")))
+		}
 
-	commonMenu := globals.MenuSystem.Add(NewMenu(&sdl.FRect{globals.ScreenSize.X / 4, globals.ScreenSize.Y/2 - 32, globals.ScreenSize.X / 2, 192}, MenuCloseButton), "common", false)
-	commonMenu.Draggable = true
-	commonMenu.Resizeable = true
+	}
 
-	// urlMenu := globals.MenuSystem.Add(NewMenu(&sdl.FRect{0, 0, globals.ScreenSize.X / 4, globals.ScreenSize.Y / 8}, MenuCloseNone), "url menu", false)
-	// urlMenu.Draggable = true
-	// urlMenu.Resizeable = true
-	// urlMenu.Center()
+	// Dependencies
 
-	// root = urlMenu.Pages["root"]
+	dependencies := editMenu.AddPage("dependencies")
+	dependencies.AddRow(AlignCenter).Add("label", NewLabel("Dependencies", &sdl.FRect{0, 0, 192, 32}, false, AlignCenter))
 
-	// row = root.AddRow(AlignLeft)
-	// row.Add("favicon", NewGUIImage(&sdl.FRect{0, 0, 32, 32}, &sdl.Rect{0, 0, 32, 32}, nil, false))
-	// tl := NewLabel("---", nil, false, AlignLeft)
-	// // tl.AutoExpand = true
-	// row.Add("title", tl)
-	// row = root.AddRow(AlignLeft)
-	// dl := NewLabel("---", nil, false, AlignLeft)
-	// // dl.ExpandMode = true
-	// row.Add("description", dl)
+	// Linking from a Card to another with a blocking link makes the first Card blocked by the second
+	dependencies.AddRow(AlignCenter).Add("", NewLabel("Links From Selected Cards :", nil, false, AlignCenter))
 
-	// root.OnUpdate = func() {
-	// 	tl.SetMaxSize(urlMenu.Rect.W-48, float32(len(tl.RendererResult.TextLines))*globals.GridSize)
-	// 	dl.SetMaxSize(urlMenu.Rect.W-48, float32(len(dl.RendererResult.TextLines))*globals.GridSize)
-	// }
+	setBlocking := func(blocking bool) {
 
-	// Confirmation Menus
+		linkCount := 0
 
-	confirmQuit := globals.MenuSystem.Add(NewMenu(&sdl.FRect{0, 0, 32, 32}, MenuCloseButton), "confirm quit", true)
-	confirmQuit.Draggable = true
-	root = confirmQuit.Pages["root"]
-	root.AddRow(AlignCenter).Add("label", NewLabel("Are you sure you wish to quit?", nil, false, AlignCenter))
-	root.AddRow(AlignCenter).Add("label-2", NewLabel("Any unsaved changes will be lost.", nil, false, AlignCenter))
-	row = root.AddRow(AlignCenter)
-	row.Add("yes", NewButton("Yes, Quit", &sdl.FRect{0, 0, 128, 32}, nil, false, func() { quit = true }))
-	row.Add("no", NewButton("No", &sdl.FRect{0, 0, 128, 32}, nil, false, func() { confirmQuit.Close() }))
-	confirmQuit.Recreate(root.IdealSize().X+48, root.IdealSize().Y+32)
+		for _, card := range globals.Project.CurrentPage.Selection.AsSlice() {
+			for _, link := range card.Links {
+				if link.Start == card && link.Blocking != blocking {
+					link.Blocking = blocking
+					card.CreateUndoState = true
+					linkCount++
+				}
+			}
+		}
 
-	confirmNewProject := globals.MenuSystem.Add(NewMenu(&sdl.FRect{0, 0, 32, 32}, MenuCloseButton), "confirm new project", true)
-	confirmNewProject.Draggable = true
-	root = confirmNewProject.Pages["root"]
-	root.AddRow(AlignCenter).Add("label", NewLabel("Create a new project?", nil, false, AlignCenter))
-	root.AddRow(AlignCenter).Add("label-2", NewLabel("Any unsaved changes will be lost.", nil, false, AlignCenter))
-	row = root.AddRow(AlignCenter)
-	row.Add("yes", NewButton("Yes", &sdl.FRect{0, 0, 128, 32}, nil, false, func() {
-		globals.NextProject = NewProject()
-		globals.EventLog.Log("New project created.", false)
-		confirmNewProject.Close()
-	}))
-	row.Add("no", NewButton("No", &sdl.FRect{0, 0, 128, 32}, nil, false, func() { confirmNewProject.Close() }))
-	confirmNewProject.Recreate(root.IdealSize().X+48, root.IdealSize().Y+32)
+		if blocking {
+			globals.EventLog.Log("%d link(s) from selected cards are now blocking; linking cards can't be completed before linked cards.", false, linkCount)
+		} else {
+			globals.EventLog.Log("%d link(s) from selected cards are no longer blocking.", false, linkCount)
+		}
 
-	confirmLoad := globals.MenuSystem.Add(NewMenu(&sdl.FRect{0, 0, 32, 32}, MenuCloseButton), "confirm load", true)
-	confirmLoad.Draggable = true
-	root = confirmLoad.Pages["root"]
-	root.AddRow(AlignCenter).Add("label", NewLabel("Load the following project?", nil, false, AlignCenter))
-	confirmLoadFilepath := NewLabel("Project Filepath: ", &sdl.FRect{0, 0, 800, 32}, false, AlignCenter)
-	root.AddRow(AlignCenter).Add("label2", confirmLoadFilepath)
-	root.OnOpen = func() {
-		confirmLoadFilepath.SetText([]rune(SimplifyPathString(globals.Project.LoadConfirmationTo, 50)))
 	}
-	root.AddRow(AlignCenter).Add("label3", NewLabel("Any unsaved changes will be lost.", nil, false, AlignCenter))
-	row = root.AddRow(AlignCenter)
-	row.Add("yes", NewButton("Yes", &sdl.FRect{0, 0, 128, 32}, nil, false, func() {
-		OpenProjectFrom(globals.Project.LoadConfirmationTo)
-		confirmLoad.Close()
-	}))
-	row.Add("no", NewButton("No", &sdl.FRect{0, 0, 128, 32}, nil, false, func() { confirmLoad.Close() }))
-	confirmLoad.Recreate(root.IdealSize().X+48, root.IdealSize().Y+16)
 
-	// // Confirm Load Menu - do this after Project.Modified works again.
+	row = dependencies.AddRow(AlignCenter)
+	row.ExpandAllElements = true
+	row.Add("make blocking", NewButton("Make Blocking", nil, nil, false, func() { setBlocking(true) }))
+	row.Add("make non-blocking", NewButton("Make Non-Blocking", nil, nil, false, func() { setBlocking(false) }))
 
-	// confirmQuit := globals.MenuSystem.Add(NewMenu(&sdl.FRect{0, 0, 32, 32}, true), "confirm quit", true)
-	// confirmQuit.Draggable = true
+	dependencies.AddRow(AlignCenter).Add("", NewSpacer(&sdl.FRect{0, 0, 4, 8}))
 
-	// root = confirmQuit.Pages["root"]
-	// root.AddRow(AlignCenter).Add("label", NewLabel("Are you sure you\nwish to quit?", nil, false, AlignCenter))
-	// root.AddRow(AlignCenter).Add("label", NewLabel("Any unsaved changes will be lost.", nil, false, AlignCenter))
-	// row = root.AddRow(AlignCenter)
-	// row.Add("yes", NewButton("Yes, Quit", &sdl.FRect{0, 0, 128, 32}, nil, false, func() { quit = true }))
+	row = dependencies.AddRow(AlignCenter)
+	row.Add("", NewLabel("Duration (Days) : ", nil, false, AlignLeft))
+	durationEstimate := NewNumberSpinner(&sdl.FRect{0, 0, 160, 32}, false, nil)
+	durationEstimate.MinValue = 0
+	durationEstimate.Value = DefaultDurationEstimate
+	row.Add("duration", durationEstimate)
+
+	row = dependencies.AddRow(AlignCenter)
+	row.ExpandAllElements = true
+	row.Add("set duration", NewButton("Set Duration", nil, nil, false, func() {
+
+		completableCount := 0
+
+		for _, card := range globals.Project.CurrentPage.Selection.AsSlice() {
+			if card.Completable() {
+				completableCount++
+				card.Properties.Get("duration").Set(durationEstimate.Value)
+			}
+		}
+
+		globals.EventLog.Log("Duration estimate set to %d day(s) on %d complete-able card(s).", false, int(durationEstimate.Value), completableCount)
+
+	}))
+
+	row.Add("clear duration", NewButton("Clear Duration", nil, nil, false, func() {
+
+		selection := globals.Project.CurrentPage.Selection.AsSlice()
+
+		for _, card := range selection {
+			// The property is emptied rather than removed so that undoing and redoing the change works.
+			if card.Properties.Has("duration") {
+				card.Properties.Get("duration").Set("")
+			}
+		}
+
+		globals.EventLog.Log("Duration estimate cleared on %d card(s).", false, len(selection))
+
+	}))
+
+	dependencies.AddRow(AlignCenter).Add("", NewSpacer(&sdl.FRect{0, 0, 4, 8}))
+
+	row = dependencies.AddRow(AlignCenter)
+	row.Add("", NewLabel("Highlight Critical Path :", nil, false, AlignLeft))
+	highlightCriticalPath := NewCheckbox(0, 0, false, nil)
+	highlightCriticalPath.OnPressed = func() {
+		globals.Project.HighlightCriticalPath = !globals.Project.HighlightCriticalPath
+	}
+	row.Add("highlight critical path", highlightCriticalPath)
+
+	pageFinish := NewLabel("Page Finishes By : ", nil, false, AlignCenter)
+	dependencies.AddRow(AlignCenter).Add("page finish", pageFinish)
+
+	dependencies.OnUpdate = func() {
+
+		if globals.Project == nil {
+			return
+		}
+
+		highlightCriticalPath.Checked = globals.Project.HighlightCriticalPath
+
+		schedule := globals.Project.CurrentPage.Schedule()
+
+		if schedule.Finish > 0 {
+			pageFinish.SetText([]rune(fmt.Sprintf("Page Finishes By : %s (%s days)", schedule.FinishDate().Format(DeadlineDateFormat), strconv.FormatFloat(math.Ceil(schedule.Finish), 'f', 0, 64))))
+		} else {
+			pageFinish.SetText([]rune("Page Finishes By : Today"))
+		}
+
+	}
+
+	// Priority
+
+	setPriority := editMenu.AddPage("set priority")
+	setPriority.AddRow(AlignCenter).Add("label", NewLabel("Set Priority", &sdl.FRect{0, 0, 192, 32}, false, AlignCenter))
+
+	for i := PriorityCritical; i >= PriorityNone; i-- {
+
+		priority := i
+
+		button := NewButton(priorityNames[priority], &sdl.FRect{0, 0, 192, 32}, nil, false, func() {
+
+			completableCount := 0
+
+			for _, card := range globals.Project.CurrentPage.Selection.AsSlice() {
+				if card.SetPriority(priority) {
+					completableCount++
+				}
+			}
+
+			globals.EventLog.Log("Priority set to %s on %d complete-able card(s).", false, priorityNames[priority], completableCount)
+
+		})
+
+		if priority != PriorityNone {
+			button.BackgroundColor = priorityColors[priority].Clone()
+			button.BackgroundColor[3] = 64
+		}
+
+		setPriority.AddRow(AlignCenter).Add("", button)
+
+	}
+
+	// Tags
+
+	setTags := editMenu.AddPage("set tags")
+	setTags.AddRow(AlignCenter).Add("label", NewLabel("Set Tags", &sdl.FRect{0, 0, 192, 32}, false, AlignCenter))
+
+	row = setTags.AddRow(AlignCenter)
+	row.Add("", NewLabel("Tags : ", nil, false, AlignLeft))
+	tagInput := NewLabel("", &sdl.FRect{0, 0, 256, 32}, false, AlignLeft)
+	tagInput.Editable = true
+	tagInput.RegexString = RegexNoNewlines
+	row.Add("tag input", tagInput)
+
+	tagInputSuggestions := NewTagSuggestions(setTags, tagInput)
+
+	row = setTags.AddRow(AlignCenter)
+	row.ExpandAllElements = true
+	row.Add("add tags", NewButton("Add", nil, nil, false, func() {
+
+		tags := ParseTags(tagInput.TextAsString())
+		selection := globals.Project.CurrentPage.Selection.AsSlice()
+
+		if len(tags) > 0 && len(selection) > 0 {
+			for _, card := range selection {
+				card.SetTags(append(card.Tags(), tags...)...)
+			}
+			globals.EventLog.Log("Tags [%s] added to %d card(s).", false, TagsToString(tags), len(selection))
+		}
+
+	}))
+
+	row.Add("remove tags", NewButton("Remove", nil, nil, false, func() {
+
+		tags := ParseTags(tagInput.TextAsString())
+		selection := globals.Project.CurrentPage.Selection.AsSlice()
+
+		if len(tags) > 0 && len(selection) > 0 {
+
+			for _, card := range selection {
+
+				remaining := []string{}
+				for _, tag := range card.Tags() {
+					if !TagsContain(tags, tag) {
+						remaining = append(remaining, tag)
+					}
+				}
+				card.SetTags(remaining...)
+
+			}
+
+			globals.EventLog.Log("Tags [%s] removed from %d card(s).", false, TagsToString(tags), len(selection))
+
+		}
+
+	}))
+
+	setTags.AddRow(AlignCenter).Add("clear tags", NewButton("Clear Tags", nil, nil, false, func() {
+
+		selection := globals.Project.CurrentPage.Selection.AsSlice()
+
+		if len(selection) > 0 {
+			for _, card := range selection {
+				card.SetTags()
+			}
+			globals.EventLog.Log("Tags cleared on %d card(s).", false, len(selection))
+		}
+
+	}))
+
+	setTags.AddRow(AlignCenter).Add("", NewSpacer(&sdl.FRect{0, 0, 4, 8}))
+
+	row = setTags.AddRow(AlignCenter)
+	selectionTags := NewLabel("Selected Cards' Tags : ", nil, false, AlignCenter)
+	row.Add("selection tags", selectionTags)
+
+	setTags.OnUpdate = func() {
+
+		tagInputSuggestions.Update()
+
+		tags := []string{}
+		if globals.Project != nil {
+			for _, card := range globals.Project.CurrentPage.Selection.AsSlice() {
+				tags = append(tags, card.Tags()...)
+			}
+		}
+
+		tags = ParseTags(TagsToString(tags))
+
+		if len(tags) == 0 {
+			selectionTags.SetText([]rune("Selected Cards' Tags : None"))
+		} else {
+			selectionTags.SetText([]rune("Selected Cards' Tags : " + TagsToString(tags)))
+		}
+
+	}
+
+	// Icons Menu
+
+	root = editMenu.AddPage("add icons")
+
+	root.AddRow(AlignCenter).Add("icons label", NewLabel("Icons", nil, false, AlignCenter))
+
+	type iconStruct struct {
+		Image    Image
+		Filepath string
+	}
+
+	iconImgs := []iconStruct{}
+
+	filepath.Walk(LocalRelativePath("assets/icons"), func(path string, info fs.FileInfo, err error) error {
+
+		if filepath.Ext(path) == ".png" {
+			res := globals.Resources.Get(path)
+			res.Destructible = false
+			iconImgs = append(iconImgs, iconStruct{
+				Image:    res.AsImage(),
+				Filepath: path,
+			})
+		}
+
+		return nil
+	})
+
+	for i := 0; i < len(iconImgs); i += 5 {
+
+		newRow := root.AddRow(AlignCenter)
+
+		for j := 0; j < 5; j++ {
+
+			if i+j >= len(iconImgs) {
+				break
+			}
+
+			icon := iconImgs[i+j]
+
+			button := NewIconButton(0, 0, &sdl.Rect{0, 0, int32(icon.Image.Size.X), int32(icon.Image.Size.X)}, icon.Image, false, func() {
+				n := globals.Project.CurrentPage.CreateNewCard(ContentTypeImage)
+				n.Rect.X = globals.Project.Camera.Position.X
+				n.Rect.Y = globals.Project.Camera.Position.Y
+				ic := n.Contents.(*ImageContents)
+				ic.LoadFileFrom(icon.Filepath)
+				ic.LoadedImage = true // The size is set already
+				n.Recreate(globals.GridSize, globals.GridSize)
+				n.CreateUndoState = true
+				n.Update()
+				n.HandleUndos()
+			})
+			button.Tint = NewColor(255, 255, 255, 255)
+			button.Scale.X = 2
+			button.Scale.Y = 2
+			newRow.Add("", button)
+		}
+
+	}
+
+	// root.AddRow(AlignCenter)
+
+	// Context Menu
+
+	contextMenu := globals.MenuSystem.Add(NewMenu(&sdl.FRect{0, 0, 256, 256}, MenuCloseClickOut), "context", false)
+	contextMenu.OnOpen = func() { globals.State = StateContextMenu }
+	contextMenu.OnClose = func() { globals.State = StateNeutral }
+	root = contextMenu.Pages["root"]
+
+	root.AddRow(AlignCenter).Add("create card", NewButton("Create Card", &sdl.FRect{0, 0, 192, 32}, nil, false, func() {
+		globals.Project.CurrentPage.CreateNewCard(ContentTypeCheckbox)
+		contextMenu.Close()
+	}))
+
+	root.AddRow(AlignCenter).Add("delete cards", NewButton("Delete Cards", &sdl.FRect{0, 0, 192, 32}, nil, false, func() {
+		page := globals.Project.CurrentPage
+		page.DeleteCards(page.Selection.AsSlice()...)
+		contextMenu.Close()
+	}))
+
+	root.AddRow(AlignCenter).Add("copy cards", NewButton("Copy Cards", &sdl.FRect{0, 0, 192, 32}, nil, false, func() {
+		page := globals.Project.CurrentPage
+		page.CopySelectedCards()
+		contextMenu.Close()
+	}))
+
+	root.AddRow(AlignCenter).Add("cut cards", NewButton("Cut Cards", &sdl.FRect{0, 0, 192, 32}, nil, false, func() {
+		page := globals.Project.CurrentPage
+		globals.CopyBuffer.CutMode = true
+		page.CopySelectedCards()
+		contextMenu.Close()
+	}))
+
+	root.AddRow(AlignCenter).Add("paste cards", NewButton("Paste Cards", &sdl.FRect{0, 0, 192, 32}, nil, false, func() {
+		menuPos := Point{globals.MenuSystem.Get("context").Rect.X, globals.MenuSystem.Get("context").Rect.Y}
+		offset := globals.Mouse.Position().Sub(menuPos)
+		globals.Project.CurrentPage.PasteCards(offset, true)
+		contextMenu.Close()
+	}))
+
+	// Spelling Menu
+
+	spellingMenu := globals.MenuSystem.Add(NewMenu(&sdl.FRect{0, 0, 384, 256}, MenuCloseClickOut), "spelling", false)
+	spellingMenu.OnOpen = func() {
+
+		root := spellingMenu.Pages["root"]
+		root.Destroy()
+
+		label := globals.SpellChecker.CorrectingLabel
+		misspelling := globals.SpellChecker.Correcting
+
+		suggestions := globals.SpellChecker.Suggest(misspelling.Word)
+
+		if len(suggestions) == 0 {
+			root.AddRow(AlignCenter).Add("no suggestions", NewLabel("No Suggestions", nil, false, AlignCenter))
+		}
+
+		for _, s := range suggestions {
+			suggestion := s
+			root.AddRow(AlignCenter).Add("", NewButton(suggestion, &sdl.FRect{0, 0, 320, 32}, nil, false, func() {
+				label.ReplaceMisspelling(misspelling, suggestion)
+				spellingMenu.Close()
+			}))
+		}
+
+		root.AddRow(AlignCenter).Add("add to dictionary", NewButton("Add to Project Dictionary", &sdl.FRect{0, 0, 320, 32}, nil, false, func() {
+			globals.Project.AddToDictionary(misspelling.Word)
+			globals.EventLog.Log("Added [%s] to the project's dictionary.", false, misspelling.Word)
+			spellingMenu.Close()
+		}))
+
+		idealSize := root.IdealSize()
+		spellingMenu.Recreate(spellingMenu.Rect.W, idealSize.Y+16)
+
+	}
+
+	commonMenu := globals.MenuSystem.Add(NewMenu(&sdl.FRect{globals.ScreenSize.X / 4, globals.ScreenSize.Y/2 - 32, globals.ScreenSize.X / 2, 192}, MenuCloseButton), "common", false)
+	commonMenu.Draggable = true
+	commonMenu.Resizeable = true
+
+	// urlMenu := globals.MenuSystem.Add(NewMenu(&sdl.FRect{0, 0, globals.ScreenSize.X / 4, globals.ScreenSize.Y / 8}, MenuCloseNone), "url menu", false)
+	// urlMenu.Draggable = true
+	// urlMenu.Resizeable = true
+	// urlMenu.Center()
+
+	// root = urlMenu.Pages["root"]
+
+	// row = root.AddRow(AlignLeft)
+	// row.Add("favicon", NewGUIImage(&sdl.FRect{0, 0, 32, 32}, &sdl.Rect{0, 0, 32, 32}, nil, false))
+	// tl := NewLabel("---", nil, false, AlignLeft)
+	// // tl.AutoExpand = true
+	// row.Add("title", tl)
+	// row = root.AddRow(AlignLeft)
+	// dl := NewLabel("---", nil, false, AlignLeft)
+	// // dl.ExpandMode = true
+	// row.Add("description", dl)
+
+	// root.OnUpdate = func() {
+	// 	tl.SetMaxSize(urlMenu.Rect.W-48, float32(len(tl.RendererResult.TextLines))*globals.GridSize)
+	// 	dl.SetMaxSize(urlMenu.Rect.W-48, float32(len(dl.RendererResult.TextLines))*globals.GridSize)
+	// }
+
+	// Confirmation Menus
+
+	confirmQuit := globals.MenuSystem.Add(NewMenu(&sdl.FRect{0, 0, 32, 32}, MenuCloseButton), "confirm quit", true)
+	confirmQuit.Draggable = true
+	root = confirmQuit.Pages["root"]
+	root.AddRow(AlignCenter).Add("label", NewLabel("Are you sure you wish to quit?", nil, false, AlignCenter))
+	root.AddRow(AlignCenter).Add("label-2", NewLabel("Any unsaved changes will be lost.", nil, false, AlignCenter))
+	row = root.AddRow(AlignCenter)
+	row.Add("yes", NewButton("Yes, Quit", &sdl.FRect{0, 0, 128, 32}, nil, false, func() { quit = true }))
+	row.Add("no", NewButton("No", &sdl.FRect{0, 0, 128, 32}, nil, false, func() { confirmQuit.Close() }))
+	confirmQuit.Recreate(root.IdealSize().X+48, root.IdealSize().Y+32)
+
+	confirmNewProject := globals.MenuSystem.Add(NewMenu(&sdl.FRect{0, 0, 32, 32}, MenuCloseButton), "confirm new project", true)
+	confirmNewProject.Draggable = true
+	root = confirmNewProject.Pages["root"]
+	root.AddRow(AlignCenter).Add("label", NewLabel("Create a new project?", nil, false, AlignCenter))
+	root.AddRow(AlignCenter).Add("label-2", NewLabel("Any unsaved changes will be lost.", nil, false, AlignCenter))
+	row = root.AddRow(AlignCenter)
+	row.Add("yes", NewButton("Yes", &sdl.FRect{0, 0, 128, 32}, nil, false, func() {
+		globals.NextProject = NewProject()
+		globals.EventLog.Log("New project created.", false)
+		confirmNewProject.Close()
+	}))
+	row.Add("no", NewButton("No", &sdl.FRect{0, 0, 128, 32}, nil, false, func() { confirmNewProject.Close() }))
+	confirmNewProject.Recreate(root.IdealSize().X+48, root.IdealSize().Y+32)
+
+	confirmLoad := globals.MenuSystem.Add(NewMenu(&sdl.FRect{0, 0, 32, 32}, MenuCloseButton), "confirm load", true)
+	confirmLoad.Draggable = true
+	root = confirmLoad.Pages["root"]
+	root.AddRow(AlignCenter).Add("label", NewLabel("Load the following project?", nil, false, AlignCenter))
+	confirmLoadFilepath := NewLabel("Project Filepath: ", &sdl.FRect{0, 0, 800, 32}, false, AlignCenter)
+	root.AddRow(AlignCenter).Add("label2", confirmLoadFilepath)
+	root.OnOpen = func() {
+		confirmLoadFilepath.SetText([]rune(SimplifyPathString(globals.Project.LoadConfirmationTo, 50)))
+	}
+	root.AddRow(AlignCenter).Add("label3", NewLabel("Any unsaved changes will be lost.", nil, false, AlignCenter))
+	row = root.AddRow(AlignCenter)
+	row.Add("yes", NewButton("Yes", &sdl.FRect{0, 0, 128, 32}, nil, false, func() {
+		OpenProjectFrom(globals.Project.LoadConfirmationTo)
+		confirmLoad.Close()
+	}))
+	row.Add("no", NewButton("No", &sdl.FRect{0, 0, 128, 32}, nil, false, func() { confirmLoad.Close() }))
+	confirmLoad.Recreate(root.IdealSize().X+48, root.IdealSize().Y+16)
+
+	// // Confirm Load Menu - do this after Project.Modified works again.
+
+	// confirmQuit := globals.MenuSystem.Add(NewMenu(&sdl.FRect{0, 0, 32, 32}, true), "confirm quit", true)
+	// confirmQuit.Draggable = true
+
+	// root = confirmQuit.Pages["root"]
+	// root.AddRow(AlignCenter).Add("label", NewLabel("Are you sure you\nwish to quit?", nil, false, AlignCenter))
+	// root.AddRow(AlignCenter).Add("label", NewLabel("Any unsaved changes will be lost.", nil, false, AlignCenter))
+	// row = root.AddRow(AlignCenter)
+	// row.Add("yes", NewButton("Yes, Quit", &sdl.FRect{0, 0, 128, 32}, nil, false, func() { quit = true }))
 	// row.Add("no", NewButton("No", &sdl.FRect{0, 0, 128, 32}, nil, false, func() { confirmQuit.Close() }))
 
 	// confirmQuit.Recreate(root.IdealSize().X+32, root.IdealSize().Y)
@@ -2009,6 +2504,19 @@ func ConstructMenus() {
 	row.Add("", NewLabel("Notify on Elapsed Timers:", nil, false, AlignLeft))
 	row.Add("", NewCheckbox(0, 0, false, globals.Settings.Get(SettingsNotifyOnElapsedTimers)))
 
+	row = general.AddRow(AlignCenter)
+	row.Add("", NewLabel("Deadline Reminders (e.g. 1d, 1h, 30m):", nil, false, AlignLeft))
+	reminderLeadTimes := NewLabel("", &sdl.FRect{0, 0, 192, 32}, false, AlignLeft)
+	reminderLeadTimes.Editable = true
+	reminderLeadTimes.RegexString = RegexNoNewlines
+	reminderLeadTimes.Property = globals.Settings.Get(SettingsDeadlineReminders)
+	reminderLeadTimes.OnClickOut = func() {
+		if _, err := ParseReminderLeadTimes(reminderLeadTimes.TextAsString()); err != nil {
+			globals.EventLog.Log("Deadline reminders couldn't be parsed: %s", true, err.Error())
+		}
+	}
+	row.Add("", reminderLeadTimes)
+
 	row = general.AddRow(AlignCenter)
 	row.Add("", NewLabel("Show About Dialog On Start:", nil, false, AlignLeft))
 	row.Add("", NewCheckbox(0, 0, false, globals.Settings.Get(SettingsShowAboutDialogOnStart)))
@@ -2022,8 +2530,49 @@ func ConstructMenus() {
 	row.Add("", NewCheckbox(0, 0, false, globals.Settings.Get(SettingsFocusOnUndo)))
 
 	row = general.AddRow(AlignCenter)
-	row.Add("", NewLabel("Place Newly Created Cards in Selected Stack:", nil, false, AlignLeft))
-	row.Add("", NewCheckbox(0, 0, false, globals.Settings.Get(SettingsPlaceNewCardsInStack)))
+	row.Add("", NewLabel("Place Newly Created Cards in Selected Stack:", nil, false, AlignLeft))
+	row.Add("", NewCheckbox(0, 0, false, globals.Settings.Get(SettingsPlaceNewCardsInStack)))
+
+	row = general.AddRow(AlignCenter)
+	row.Add("", NewSpacer(nil))
+
+	row = general.AddRow(AlignCenter)
+	row.Add("", NewLabel("Spell Check Text:", nil, false, AlignLeft))
+	row.Add("", NewCheckbox(0, 0, false, globals.Settings.Get(SettingsSpellCheck)))
+
+	row = general.AddRow(AlignCenter)
+	row.Add("", NewLabel("Spell Check Language:", nil, false, AlignLeft))
+
+	availableDictionaries := AvailableDictionaries()
+
+	// The chosen language is always listed, even if its dictionary can't be found
+	dictionaryOptions := func() []string {
+		options := append([]string{}, availableDictionaries...)
+		language := globals.Settings.Get(SettingsSpellCheckLanguage).AsString()
+		for _, name := range options {
+			if name == language {
+				return options
+			}
+		}
+		return append([]string{language}, options...)
+	}
+
+	languageDropdown := NewDropdown(&sdl.FRect{0, 0, 192, 32}, false, nil, globals.Settings.Get(SettingsSpellCheckLanguage), dictionaryOptions()...)
+	languageDropdown.OnOpen = func() {
+		availableDictionaries = AvailableDictionaries()
+		options := dictionaryOptions()
+		languageDropdown.SetOptions(options...)
+		for i, name := range options {
+			if name == globals.Settings.Get(SettingsSpellCheckLanguage).AsString() {
+				languageDropdown.ChosenIndex = i
+				break
+			}
+		}
+	}
+	row.Add("", languageDropdown)
+
+	row = general.AddRow(AlignCenter)
+	row.Add("", NewLabel("Hunspell dictionaries (.aff and .dic files) are loaded from:\n"+SpellCheckDictionaryPath(), nil, false, AlignCenter))
 
 	row = general.AddRow(AlignCenter)
 	row.Add("", NewSpacer(nil))
@@ -2161,6 +2710,10 @@ func ConstructMenus() {
 	row.Add("", NewLabel("Display Numbered Card Percentages as:", nil, false, AlignLeft))
 	row.Add("", NewButtonGroup(nil, false, nil, globals.Settings.Get(SettingsDisplayNumberedPercentagesAs), NumberedPercentagePercent, NumberedPercentageCurrentMax, NumberedPercentageOff))
 
+	row = visual.AddRow(AlignCenter)
+	row.Add("", NewLabel("Render Markdown in Notes:", nil, false, AlignLeft))
+	row.Add("", NewCheckbox(0, 0, false, globals.Settings.Get(SettingsRenderMarkdown)))
+
 	row = visual.AddRow(AlignCenter)
 	row.Add("", NewLabel("Card Shadows:", nil, false, AlignLeft))
 	row.Add("", NewCheckbox(0, 0, false, globals.Settings.Get(SettingsCardShadows)))
@@ -2252,6 +2805,43 @@ func ConstructMenus() {
 		globals.TriggerReloadFonts = true
 	}))
 
+	row = visual.AddRow(AlignCenter)
+	row.Add("", NewSpacer(nil))
+
+	row = visual.AddRow(AlignCenter)
+	row.Add("", NewLabel("Fallback Fonts:", nil, false, AlignLeft))
+
+	row = visual.AddRow(AlignCenter)
+	row.Add("", NewLabel("Used in order for characters the main font doesn't have (i.e. CJK, symbols, or emoji); one font path per line.", &sdl.FRect{0, 0, 512, 64}, false, AlignLeft))
+
+	row = visual.AddRow(AlignCenter)
+	fallbackFonts := NewLabel("Fallback fonts", nil, false, AlignLeft)
+	fallbackFonts.Editable = true
+	fallbackFonts.Property = globals.Settings.Get(SettingsFallbackFonts)
+	fallbackFonts.OnClickOut = func() {
+		globals.TriggerReloadFonts = true
+	}
+	row.Add("", fallbackFonts)
+
+	row = visual.AddRow(AlignCenter)
+	row.Add("", NewButton("Add Font", nil, nil, false, func() {
+
+		if path, err := zenity.SelectFile(zenity.Title("Select Fallback Font (.ttf, .otf, .ttc)"), zenity.FileFilter{Name: "Font Files", Patterns: []string{"*.ttf", "*.otf", "*.ttc"}}); err == nil {
+			fonts := strings.TrimSpace(globals.Settings.Get(SettingsFallbackFonts).AsString())
+			if fonts != "" {
+				fonts += "\n"
+			}
+			globals.Settings.Get(SettingsFallbackFonts).Set(fonts + path)
+			globals.TriggerReloadFonts = true
+		}
+
+	}))
+
+	row.Add("", NewButton("Reset to Defaults", nil, nil, false, func() {
+		globals.Settings.Get(SettingsFallbackFonts).Set(DefaultFallbackFonts())
+		globals.TriggerReloadFonts = true
+	}))
+
 	// row.Add("", NewCheckbox(0, 0, false, globals.Settings.Get(SettingsShowAboutDialogOnStart)))
 
 	// INPUT PAGE
@@ -2543,8 +3133,10 @@ func ConstructMenus() {
 		&sdl.Rect{48, 288, 32, 32},
 		&sdl.Rect{80, 288, 32, 32},
 		&sdl.Rect{112, 288, 32, 32},
+		&sdl.Rect{304, 160, 32, 32},
 	)
 	sortAZ.Spacing = 12
+	sortAZ.Buttons[3].Tint = ColorWhite // Priority sorting uses the (colored) alert icon
 
 	row.Add("", sortAZ)
 
@@ -2566,11 +3158,23 @@ func ConstructMenus() {
 		icons[ContentTypeMap],
 		icons[ContentTypeSubpage],
 		icons[ContentTypeLink],
+		icons[ContentTypeTable],
+		icons[ContentTypeFrame],
+		icons[ContentTypeCode],
 	)
 	iconGroup.Spacing = 3
 
 	row.Add("", iconGroup)
 
+	row = listRoot.AddRow(AlignLeft)
+	row.Add("", NewLabel("Tag Filter :", nil, false, AlignLeft))
+	hierarchyTagFilter := NewLabel("", &sdl.FRect{0, 0, 256, 32}, false, AlignLeft)
+	hierarchyTagFilter.Editable = true
+	hierarchyTagFilter.RegexString = RegexNoNewlines
+	row.Add("", hierarchyTagFilter)
+
+	hierarchyTagSuggestions := NewTagSuggestions(listRoot, hierarchyTagFilter)
+
 	row = listRoot.AddRow(AlignCenter)
 	row.Add("", NewSpacer(nil))
 
@@ -2584,8 +3188,13 @@ func ConstructMenus() {
 
 		// listPIP.Rect.W = float32(math.Max(float64(listRoot.Rect.W)-128, 250))
 		listPIP.Rect.W = float32(math.Max(float64(listRoot.Rect.W), 250))
-		listPIP.Rect.H = listRoot.Rect.H - 190
-		listPIP.Rows = globals.Hierarchy.Rows(sorting, filter)
+		hierarchyTagSuggestions.Update()
+
+		listPIP.Rect.H = listRoot.Rect.H - 230
+		if hierarchyTagSuggestions.Row.Visible {
+			listPIP.Rect.H -= 36
+		}
+		listPIP.Rows = globals.Hierarchy.Rows(sorting, filter, ParseTags(hierarchyTagFilter.TextAsString()))
 
 	}
 
@@ -2667,7 +3276,7 @@ func ConstructMenus() {
 
 	// Search Menu
 
-	find := globals.MenuSystem.Add(NewMenu(&sdl.FRect{9999, 9999, 512, 96}, MenuCloseButton), "find", false)
+	find := globals.MenuSystem.Add(NewMenu(&sdl.FRect{9999, 9999, 512, 508}, MenuCloseButton), "find", false)
 	find.AnchorMode = MenuAnchorTopRight
 	find.Draggable = true
 	find.Resizeable = true
@@ -2685,11 +3294,98 @@ func ConstructMenus() {
 
 	caseSensitive := false
 
+	regexCheckbox := NewCheckbox(0, 0, false, nil)
+
+	replaceLabel := NewLabel("", &sdl.FRect{0, 0, 256, 32}, false, AlignLeft)
+	replaceLabel.Editable = true
+	replaceLabel.RegexString = RegexNoNewlines
+
+	var currentQuery *Query
+	var replaceAllButton *Button
+
+	smartViewName := NewLabel("", &sdl.FRect{0, 0, 256, 32}, false, AlignLeft)
+	smartViewName.Editable = true
+	smartViewName.RegexString = RegexNoNewlines
+
+	tagFilterLabel := NewLabel("", &sdl.FRect{0, 0, 256, 32}, false, AlignLeft)
+	tagFilterLabel.Editable = true
+	tagFilterLabel.RegexString = RegexNoNewlines
+
+	resultsList := NewContainer(&sdl.FRect{0, 0, 512, 128}, false)
+
+	addResultRow := func(card *Card) {
+
+		resultRow := NewContainerRow(resultsList, AlignLeft)
+		resultRow.Add("icon", NewGUIImage(nil, icons[card.ContentType], globals.GUITexture.Texture, false))
+
+		text := strings.ReplaceAll(card.Name(), "\n", " - ")
+		if r := []rune(text); len(r) > 20 {
+			text = string(r[:20]) + "..."
+		}
+
+		button := NewButton(text, &sdl.FRect{0, 0, 256, 32}, nil, false, func() {
+			for i, c := range foundCards {
+				if c == card {
+					foundIndex = i
+				}
+			}
+			globals.Project.Camera.FocusOn(false, card)
+			card.Page.Selection.Clear()
+			card.Page.Selection.Add(card)
+			foundLabel.SetText([]rune(fmt.Sprintf("%d of %d", foundIndex+1, len(foundCards))))
+		})
+		button.Label.HorizontalAlignment = AlignLeft
+		button.Label.SetMaxSize(256, 32)
+		resultRow.Add("button", button)
+
+		pageLabel := NewLabel(card.Page.Path(), nil, false, AlignLeft)
+		pageLabel.SetMaxSize(192, 32)
+		resultRow.Add("page", pageLabel)
+
+		resultsList.Rows = append(resultsList.Rows, resultRow)
+
+		// Preview what the Card's description would look like after replacing the found text
+		if preview := replacePreview(card, currentQuery, replaceLabel.TextAsString()); replaceLabel.TextAsString() != "" && preview != "" {
+			previewRow := NewContainerRow(resultsList, AlignLeft)
+			previewLabel := NewLabel("-> "+preview, nil, false, AlignLeft)
+			previewLabel.SetMaxSize(448, 32)
+			previewRow.Add("preview", previewLabel)
+			resultsList.Rows = append(resultsList.Rows, previewRow)
+		}
+
+	}
+
+	currentSearch := func() *SavedSearch {
+		return &SavedSearch{
+			Name:          smartViewName.TextAsString(),
+			Query:         searchLabel.TextAsString(),
+			Tags:          tagFilterLabel.TextAsString(),
+			Regex:         regexCheckbox.Checked,
+			CaseSensitive: caseSensitive,
+		}
+	}
+
 	findFunc := func() {
 
 		foundCards = []*Card{}
+		resultsList.Rows = []*ContainerRow{}
+
+		tagFilter := ParseTags(tagFilterLabel.TextAsString())
 
-		if len(searchLabel.Text) == 0 {
+		query, err := currentSearch().Compile()
+
+		currentQuery = query
+		replaceAllButton.Label.SetText([]rune("Replace All"))
+
+		if err != nil {
+			foundLabel.SetText([]rune("Invalid query"))
+			errorRow := NewContainerRow(resultsList, AlignCenter)
+			errorRow.Add("", NewLabel(err.Error(), nil, false, AlignCenter))
+			resultsList.Rows = append(resultsList.Rows, errorRow)
+			return
+		}
+
+		if query.Empty() && len(tagFilter) == 0 {
 			foundLabel.SetText([]rune("0 of 0"))
 			return
 		}
@@ -2704,28 +3400,23 @@ func ConstructMenus() {
 
 			for _, card := range page.Cards {
 
-				for propName, prop := range card.Properties.Props {
-
-					if (propName == "description" || propName == "filepath") && prop.InUse && prop.IsString() {
-
-						propString := prop.AsString()
-						searchString := searchLabel.TextAsString()
+				if card.HasTags(tagFilter...) && query.Matches(card) {
+					foundCards = append(foundCards, card)
+					addResultRow(card)
+				}
 
-						if !caseSensitive {
-							propString = strings.ToLower(prop.AsString())
-							searchString = strings.ToLower(searchString)
-						}
+			}
 
-						if strings.Contains(propString, searchString) {
-							foundCards = append(foundCards, card)
-							continue
-						}
-					}
+		}
 
+		if replaceLabel.TextAsString() != "" {
+			replaceCount := 0
+			for _, card := range foundCards {
+				if replacePreview(card, query, replaceLabel.TextAsString()) != "" {
+					replaceCount++
 				}
-
 			}
-
+			replaceAllButton.Label.SetText([]rune(fmt.Sprintf("Replace All (%d)", replaceCount)))
 		}
 
 		if foundIndex >= len(foundCards) {
@@ -2757,8 +3448,34 @@ func ConstructMenus() {
 		findFunc()
 	}
 
+	tagFilterLabel.OnChange = func() {
+		foundIndex = 0
+		findFunc()
+	}
+
+	replaceLabel.OnChange = func() {
+		findFunc()
+	}
+
+	regexToggle := regexCheckbox.OnPressed
+	regexCheckbox.OnPressed = func() {
+		regexToggle()
+		foundIndex = 0
+		findFunc()
+	}
+
+	var findTagSuggestions *TagSuggestions
+
 	root.OnUpdate = func() {
 
+		findTagSuggestions.Update()
+
+		resultsList.Rect.W = float32(math.Max(float64(root.Rect.W), 250))
+		resultsList.Rect.H = root.Rect.H - 284
+		if findTagSuggestions.Row.Visible {
+			resultsList.Rect.H -= 36
+		}
+
 		if globals.Keybindings.Pressed(KBFindNext) {
 			foundIndex++
 			findFunc()
@@ -2799,6 +3516,106 @@ func ConstructMenus() {
 
 	row.Add("", searchLabel)
 
+	row = root.AddRow(AlignCenter)
+	row.Add("", NewLabel("Tags:", nil, false, AlignCenter))
+	row.Add("", NewIconButton(0, 0, &sdl.Rect{176, 96, 32, 32}, globals.GUITexture, false, func() {
+		tagFilterLabel.SetText([]rune(""))
+	}))
+	row.Add("", tagFilterLabel)
+
+	findTagSuggestions = NewTagSuggestions(root, tagFilterLabel)
+
+	row = root.AddRow(AlignCenter)
+	row.Add("", NewLabel("Replace:", nil, false, AlignCenter))
+	row.Add("", NewIconButton(0, 0, &sdl.Rect{176, 96, 32, 32}, globals.GUITexture, false, func() {
+		replaceLabel.SetText([]rune(""))
+	}))
+	row.Add("", replaceLabel)
+
+	row = root.AddRow(AlignCenter)
+	row.Add("", NewLabel("Regex:", nil, false, AlignCenter))
+	row.Add("", regexCheckbox)
+
+	row.Add("", NewButton("Replace", nil, nil, false, func() {
+
+		if len(foundCards) == 0 || currentQuery == nil || !currentQuery.CanReplace() {
+			return
+		}
+
+		card := foundCards[foundIndex]
+
+		if ReplaceCardText(card, currentQuery, replaceLabel.TextAsString()) {
+			globals.EventLog.Log("Replaced text in 1 Card.", false)
+		}
+
+		findFunc()
+
+		// If the Card still matches the search (or couldn't have its text replaced), move on to the next one
+		if foundIndex < len(foundCards) && foundCards[foundIndex] == card {
+			foundIndex++
+			findFunc()
+		}
+
+	}))
+
+	replaceAllButton = NewButton("Replace All", &sdl.FRect{0, 0, 192, 32}, nil, false, func() {
+
+		if currentQuery == nil || !currentQuery.CanReplace() {
+			return
+		}
+
+		// Replacing happens all at once, so the changes are all captured in a single undo frame.
+		replaced := 0
+		for _, card := range foundCards {
+			if ReplaceCardText(card, currentQuery, replaceLabel.TextAsString()) {
+				replaced++
+			}
+		}
+
+		globals.EventLog.Log("Replaced text in %d Card(s).", false, replaced)
+
+		foundIndex = 0
+		findFunc()
+
+	})
+	row.Add("", replaceAllButton)
+
+	row = root.AddRow(AlignCenter)
+	row.Add("", NewLabel("Smart View:", nil, false, AlignCenter))
+	row.Add("", smartViewName)
+	row.Add("", NewButton("Save", nil, nil, false, func() {
+
+		search := currentSearch()
+
+		if _, err := search.Compile(); err != nil || (search.Query == "" && search.Tags == "") {
+			globals.EventLog.Log("Can't save an empty or invalid search as a smart view.", true)
+			return
+		}
+
+		if search.Name == "" {
+			search.Name = search.Query
+		}
+
+		searches := globals.Project.SavedSearches()
+
+		replaced := false
+		for i, existing := range searches {
+			if existing.Name == search.Name {
+				searches[i] = search
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			searches = append(searches, search)
+		}
+
+		globals.Project.SetSavedSearches(searches)
+
+		globals.EventLog.Log("Saved smart view [%s].", false, search.Name)
+
+	}))
+
 	row = root.AddRow(AlignCenter)
 
 	prev := NewIconButton(0, 0, &sdl.Rect{112, 32, 32, 32}, globals.GUITexture, false, func() {
@@ -2815,6 +3632,9 @@ func ConstructMenus() {
 		findFunc()
 	}))
 
+	row = root.AddRow(AlignLeft)
+	row.Add("results", resultsList)
+
 	// Previous sub-page menu
 
 	prevSubPageMenu := globals.MenuSystem.Add(NewMenu(&sdl.FRect{(globals.ScreenSize.X - 512) / 2, globals.ScreenSize.Y, 512, 96}, MenuCloseNone), "prev sub page", false)
@@ -2879,6 +3699,108 @@ func ConstructMenus() {
 	completeRow := baseRows[1]
 	completeRow.Add("completed label", NewLabel("Completed Deadlines (xxxx)", nil, false, AlignCenter))
 
+	deadlineTagFilterRow := NewContainerRow(deadlineRoot, AlignCenter)
+	deadlineTagFilterRow.Add("", NewLabel("Tag Filter :", nil, false, AlignLeft))
+	deadlineTagFilter := NewLabel("", &sdl.FRect{0, 0, 256, 32}, false, AlignLeft)
+	deadlineTagFilter.Editable = true
+	deadlineTagFilter.RegexString = RegexNoNewlines
+	deadlineTagFilterRow.Add("tag filter", deadlineTagFilter)
+
+	deadlineTagSuggestions := NewTagSuggestions(deadlineRoot, deadlineTagFilter)
+
+	deadlineSortRow := NewContainerRow(deadlineRoot, AlignCenter)
+	deadlineSortRow.Add("", NewLabel("Sort by :", nil, false, AlignLeft))
+	deadlineSorting := NewButtonGroup(&sdl.FRect{0, 0, 256, 32}, false, nil, nil, "Date", "Priority")
+	deadlineSortRow.Add("sorting", deadlineSorting)
+	deadlineSortRow.Add("calendar", NewButton("Calendar View", nil, nil, false, func() {
+		deadlines.SetPage("calendar")
+	}))
+
+	deadlineCalendarPage := deadlines.AddPage("calendar")
+	deadlineCalendar := NewCalendar(&sdl.FRect{0, 0, 640, 320})
+
+	row = deadlineCalendarPage.AddRow(AlignCenter)
+
+	prevCalendarMonth := NewIconButton(0, 0, &sdl.Rect{112, 32, 32, 32}, globals.GUITexture, false, func() {
+		deadlineCalendar.MoveMonths(-1)
+	})
+	prevCalendarMonth.Flip = sdl.FLIP_HORIZONTAL
+	row.Add("prev month", prevCalendarMonth)
+
+	calendarMonthLabel := NewLabel("September 2000", &sdl.FRect{0, 0, 256, 32}, false, AlignCenter)
+	row.Add("month label", calendarMonthLabel)
+
+	row.Add("next month", NewIconButton(0, 0, &sdl.Rect{112, 32, 32, 32}, globals.GUITexture, false, func() {
+		deadlineCalendar.MoveMonths(1)
+	}))
+
+	row.Add("today", NewButton("Today", nil, nil, false, func() {
+		deadlineCalendar.GoToDate(time.Now())
+	}))
+
+	row = deadlineCalendarPage.AddRow(AlignCenter)
+	row.Add("calendar", deadlineCalendar)
+
+	deadlineCalendarPage.OnUpdate = func() {
+		calendarMonthLabel.SetText([]rune(deadlineCalendar.Month.Format("January 2006")))
+		deadlineCalendar.Rect.W = float32(math.Max(float64(deadlineCalendarPage.Rect.W-32), 250))
+		deadlineCalendar.Rect.H = float32(math.Max(float64(deadlineCalendarPage.Rect.H-96), 160))
+	}
+
+	// Reminders that have been sent are listed at the top of the Deadlines menu until they're dismissed or snoozed
+	reminderLabelRow := NewContainerRow(deadlineRoot, AlignCenter)
+	reminderLabelRow.Add("reminders label", NewLabel("Reminders (xxxx)", nil, false, AlignCenter))
+
+	reminderRows := []*ContainerRow{}
+
+	refreshReminderRows := func() {
+
+		for _, row := range reminderRows {
+			row.Destroy()
+		}
+
+		reminderRows = []*ContainerRow{}
+
+		if globals.Project == nil {
+			return
+		}
+
+		for _, r := range globals.Project.Reminders {
+
+			reminder := r
+
+			if !reminder.Card.Valid || reminder.Card.Completed() {
+				continue
+			}
+
+			reminderRow := NewContainerRow(deadlineRoot, AlignLeft)
+			reminderRow.AlternateBGColor = true
+			reminderRow.Add("left spacer", NewSpacer(&sdl.FRect{0, 0, 32, 32}))
+			button := NewButton(reminder.Message, nil, nil, false, func() {
+				card := reminder.Card
+				card.Page.Project.Camera.FocusOn(false, card)
+				card.Page.Selection.Clear()
+				card.Page.Selection.Add(card)
+			})
+			reminderRow.Add("button", button)
+			reminderRow.Add("snooze hour", NewButton("Snooze 1h", nil, nil, false, func() {
+				globals.Project.SnoozeReminder(reminder, time.Hour)
+			}))
+			reminderRow.Add("snooze day", NewButton("Snooze 1d", nil, nil, false, func() {
+				globals.Project.SnoozeReminder(reminder, time.Hour*24)
+			}))
+			reminderRow.Add("dismiss", NewButton("Dismiss", nil, nil, false, func() {
+				globals.Project.DismissReminder(reminder)
+			}))
+			reminderRow.ExpandSelectedElements = []MenuElement{button}
+			reminderRows = append(reminderRows, reminderRow)
+
+		}
+
+		reminderLabelRow.Elements["reminders label"].(*Label).SetText([]rune(fmt.Sprintf("Reminders (%d)", len(reminderRows))))
+
+	}
+
 	type deadlineButton struct {
 		Row  *ContainerRow
 		Card *Card
@@ -2888,6 +3810,8 @@ func ConstructMenus() {
 
 	refreshDeadlineButtons := func() {
 
+		refreshReminderRows()
+
 		for _, button := range deadlineButtons {
 			// Project changed, start over
 			button.Row.Visible = true
@@ -2963,55 +3887,300 @@ func ConstructMenus() {
 						buttonObj.Label.SetText([]rune(card.Name()))
 						date.SetText([]rune("Due on " + deadlineStr))
 
-					}
+					}
+
+				}
+
+			}
+
+		}
+
+		sort.SliceStable(deadlineButtons, func(i, j int) bool {
+			if deadlineSorting.ChosenIndex == 1 && deadlineButtons[i].Card.Priority() != deadlineButtons[j].Card.Priority() {
+				return deadlineButtons[i].Card.Priority() > deadlineButtons[j].Card.Priority()
+			}
+			if deadlineButtons[i].Card.Properties.Has("deadline") && deadlineButtons[j].Card.Properties.Has("deadline") {
+				deadlineA, _ := deadlineButtons[i].Card.DeadlineTime()
+				deadlineB, _ := deadlineButtons[j].Card.DeadlineTime()
+				if deadlineA.Before(deadlineB) {
+					return true
+				} else if deadlineA.After(deadlineB) {
+					return false
+				}
+			}
+			return deadlineButtons[i].Card.ID < deadlineButtons[j].Card.ID
+		})
+
+		tagFilter := ParseTags(deadlineTagFilter.TextAsString())
+
+		count := 0
+		deadlineRoot.Rows = []*ContainerRow{deadlineTagFilterRow, deadlineTagSuggestions.Row, deadlineSortRow}
+		if len(reminderRows) > 0 {
+			deadlineRoot.Rows = append(deadlineRoot.Rows, reminderLabelRow)
+			deadlineRoot.Rows = append(deadlineRoot.Rows, reminderRows...)
+		}
+		deadlineRoot.Rows = append(deadlineRoot.Rows, baseRows[0])
+		for _, b := range deadlineButtons {
+			if !b.Card.HasTags(tagFilter...) {
+				continue
+			}
+			if b.Card.Properties.Has("deadline") && b.Card.Completable() && !b.Card.Completed() {
+				count++
+				deadlineRoot.Rows = append(deadlineRoot.Rows, b.Row)
+			}
+		}
+
+		baseRows[0].Elements["due label"].(*Label).SetText([]rune(fmt.Sprintf("Due Deadlines (%d)", count)))
+
+		deadlineRoot.Rows = append(deadlineRoot.Rows, baseRows[1])
+
+		count = 0
+		for _, b := range deadlineButtons {
+			if !b.Card.HasTags(tagFilter...) {
+				continue
+			}
+			if b.Card.Properties.Has("deadline") && b.Card.Completable() && b.Card.Completed() {
+				count++
+				deadlineRoot.Rows = append(deadlineRoot.Rows, b.Row)
+			}
+		}
+
+		baseRows[1].Elements["completed label"].(*Label).SetText([]rune(fmt.Sprintf("Completed Deadlines (%d)", count)))
+
+	}
+
+	deadlineTagFilter.OnChange = refreshDeadlineButtons
+	deadlineSorting.OnChoose = func(index int) { refreshDeadlineButtons() }
+
+	globals.Dispatcher.Register(refreshDeadlineButtons)
+
+	refreshDeadlineButtons() // Call it once to initialize the static elements
+
+	var reminderProject *Project
+
+	deadlineRoot.OnUpdate = func() {
+
+		deadlineTagSuggestions.Update()
+
+		if globals.Project != nil && (globals.Project.RemindersChanged || reminderProject != globals.Project) {
+			globals.Project.RemindersChanged = false
+			reminderProject = globals.Project
+			refreshDeadlineButtons()
+		}
+
+	}
+
+	// Smart Views menu
+
+	smartViews := globals.MenuSystem.Add(NewMenu(&sdl.FRect{globals.ScreenSize.X/2 - (700 / 2), 9999, 700, 274}, MenuCloseButton), "smart views", false)
+
+	smartViews.Draggable = true
+	smartViews.Resizeable = true
+	smartViews.AnchorMode = MenuAnchorBottomLeft
+
+	smartViewRoot := smartViews.Pages["root"]
+	smartViewPage := smartViews.AddPage("view")
+
+	openedSmartView := ""
+
+	refreshSmartViews := func() {
+
+		if globals.Project == nil || !smartViews.Opened {
+			return
+		}
+
+		searches := globals.Project.SavedSearches()
+
+		smartViewRoot.Destroy()
+
+		row := smartViewRoot.AddRow(AlignCenter)
+		row.Add("", NewLabel("Smart Views", nil, false, AlignCenter))
+
+		if len(searches) == 0 {
+			row = smartViewRoot.AddRow(AlignCenter)
+			row.Add("", NewLabel("No smart views exist; save a search from the Find menu to create one.", nil, false, AlignCenter))
+		}
+
+		for i, s := range searches {
+
+			search := s
+			index := i
 
+			row = smartViewRoot.AddRow(AlignLeft)
+			row.AlternateBGColor = true
+
+			row.Add("open", NewButton(search.Name, nil, nil, false, func() {
+				openedSmartView = search.Name
+				smartViews.SetPage("view")
+			}))
+
+			queryText := search.Query
+			if search.Tags != "" {
+				queryText += " [" + search.Tags + "]"
+			}
+			queryLabel := NewLabel(queryText, nil, false, AlignLeft)
+			queryLabel.SetMaxSize(320, 32)
+			row.Add("query", queryLabel)
+
+			row.Add("delete", NewIconButton(0, 0, &sdl.Rect{176, 96, 32, 32}, globals.GUITexture, false, func() {
+				searches := globals.Project.SavedSearches()
+				if index < len(searches) {
+					globals.EventLog.Log("Deleted smart view [%s].", false, searches[index].Name)
+					globals.Project.SetSavedSearches(append(searches[:index], searches[index+1:]...))
 				}
+			}))
+
+			row.ExpandSelectedElements = []MenuElement{row.Elements["query"]}
+
+		}
 
+		smartViewPage.Destroy()
+
+		var search *SavedSearch
+		for _, s := range searches {
+			if s.Name == openedSmartView {
+				search = s
 			}
+		}
 
+		row = smartViewPage.AddRow(AlignCenter)
+
+		if search == nil {
+			row.Add("", NewLabel("This smart view no longer exists.", nil, false, AlignCenter))
+			return
 		}
 
-		sort.SliceStable(deadlineButtons, func(i, j int) bool {
-			if deadlineButtons[i].Card.Properties.Has("deadline") && deadlineButtons[j].Card.Properties.Has("deadline") {
-				deadlineA, _ := time.ParseInLocation("2006-01-02", deadlineButtons[i].Card.Properties.Get("deadline").AsString(), now.Location())
-				deadlineB, _ := time.ParseInLocation("2006-01-02", deadlineButtons[j].Card.Properties.Get("deadline").AsString(), now.Location())
-				if deadlineA.Before(deadlineB) {
-					return true
-				} else if deadlineA.After(deadlineB) {
-					return false
+		results, err := search.Results(globals.Project)
+
+		if err != nil {
+			row.Add("", NewLabel(search.Name+": "+err.Error(), nil, false, AlignCenter))
+			return
+		}
+
+		row.Add("", NewLabel(fmt.Sprintf("%s (%d)", search.Name, len(results)), nil, false, AlignCenter))
+
+		for _, c := range results {
+
+			card := c
+
+			row = smartViewPage.AddRow(AlignLeft)
+			row.AlternateBGColor = true
+
+			if card.Completable() {
+				checkbox := NewCheckbox(0, 0, false, nil)
+				checkbox.Checked = card.Completed()
+				toggle := checkbox.OnPressed
+				checkbox.OnPressed = func() {
+					toggle()
+					card.SetCompleted(checkbox.Checked)
+					// Capture the undo state immediately, as the Card may not be on the current Page
+					card.HandleUndos()
 				}
+				row.Add("complete", checkbox)
+			} else {
+				row.Add("complete", NewSpacer(&sdl.FRect{0, 0, 32, 32}))
 			}
-			return deadlineButtons[i].Card.ID < deadlineButtons[j].Card.ID
-		})
 
-		count := 0
-		deadlineRoot.Rows = append([]*ContainerRow{}, baseRows[0])
-		for _, b := range deadlineButtons {
-			if b.Card.Properties.Has("deadline") && b.Card.Completable() && !b.Card.Completed() {
-				count++
-				deadlineRoot.Rows = append(deadlineRoot.Rows, b.Row)
+			row.Add("icon", NewGUIImage(&sdl.FRect{0, 0, 32, 32}, icons[card.ContentType], globals.GUITexture.Texture, false))
+
+			name := strings.ReplaceAll(card.Name(), "\n", " - ")
+			if r := []rune(name); len(r) > 40 {
+				name = string(r[:40]) + "..."
 			}
-		}
 
-		baseRows[0].Elements["due label"].(*Label).SetText([]rune(fmt.Sprintf("Due Deadlines (%d)", count)))
+			row.Add("button", NewButton(name, nil, nil, false, func() {
+				card.Page.Project.Camera.FocusOn(false, card)
+				selection := card.Page.Selection
+				if !globals.Keybindings.Pressed(KBAddToSelection) {
+					selection.Clear()
+				}
+				selection.Add(card)
+			}))
 
-		deadlineRoot.Rows = append(deadlineRoot.Rows, baseRows[1])
+			pageLabel := NewLabel(card.Page.Path(), nil, false, AlignRight)
+			pageLabel.SetMaxSize(192, 32)
+			row.Add("page", pageLabel)
+
+			row.ExpandSelectedElements = []MenuElement{row.Elements["button"]}
 
-		count = 0
-		for _, b := range deadlineButtons {
-			if b.Card.Properties.Has("deadline") && b.Card.Completable() && b.Card.Completed() {
-				count++
-				deadlineRoot.Rows = append(deadlineRoot.Rows, b.Row)
-			}
 		}
 
-		baseRows[1].Elements["completed label"].(*Label).SetText([]rune(fmt.Sprintf("Completed Deadlines (%d)", count)))
+	}
+
+	smartViews.OnOpen = refreshSmartViews
+	smartViewPage.OnOpen = refreshSmartViews
+
+	globals.Dispatcher.Register(refreshSmartViews)
+
+	// Timeline menu
+
+	timelineMenu := globals.MenuSystem.Add(NewMenu(&sdl.FRect{globals.ScreenSize.X/2 - (800 / 2), 9999, 800, 400}, MenuCloseButton), "timeline", false)
+
+	timelineMenu.Draggable = true
+	timelineMenu.Resizeable = true
+	timelineMenu.AnchorMode = MenuAnchorBottom
+
+	root = timelineMenu.Pages["root"]
 
+	timeline := NewTimeline(&sdl.FRect{0, 0, 768, 300})
+
+	row = root.AddRow(AlignCenter)
+	row.Add("", NewLabel("Timeline", nil, false, AlignCenter))
+
+	timelineScale := NewButtonGroup(&sdl.FRect{0, 0, 256, 32}, false, func(index int) {
+		timeline.SetScale(index)
+	}, nil, "Days", "Weeks", "Months")
+	row.Add("scale", timelineScale)
+
+	prevTimeButton := NewIconButton(0, 0, &sdl.Rect{112, 32, 32, 32}, globals.GUITexture, false, func() {
+		timeline.Move(float64(-timeline.Rect.W / 2 / timeline.DayWidth()))
+	})
+	prevTimeButton.Flip = sdl.FLIP_HORIZONTAL
+	row.Add("", prevTimeButton)
+
+	row.Add("", NewButton("Today", nil, nil, false, func() {
+		timeline.GoToDate(time.Now())
+	}))
+
+	row.Add("", NewIconButton(0, 0, &sdl.Rect{112, 32, 32, 32}, globals.GUITexture, false, func() {
+		timeline.Move(float64(timeline.Rect.W / 2 / timeline.DayWidth()))
+	}))
+
+	row = root.AddRow(AlignCenter)
+	row.Add("timeline", timeline)
+
+	root.OnUpdate = func() {
+		timeline.Rect.W = float32(math.Max(float64(root.Rect.W-32), 250))
+		timeline.Rect.H = float32(math.Max(float64(root.Rect.H-96), 64))
 	}
 
-	globals.Dispatcher.Register(refreshDeadlineButtons)
+	// Kanban menu
 
-	refreshDeadlineButtons() // Call it once to initialize the static elements
+	kanbanMenu := globals.MenuSystem.Add(NewMenu(&sdl.FRect{globals.ScreenSize.X/2 - (800 / 2), 9999, 800, 400}, MenuCloseButton), "kanban", false)
+
+	kanbanMenu.Draggable = true
+	kanbanMenu.Resizeable = true
+	kanbanMenu.AnchorMode = MenuAnchorBottom
+
+	root = kanbanMenu.Pages["root"]
+
+	kanban := NewKanban(&sdl.FRect{0, 0, 768, 300})
+
+	row = root.AddRow(AlignCenter)
+	row.Add("", NewLabel("Kanban", nil, false, AlignCenter))
+	row.Add("", NewLabel("Columns :", nil, false, AlignLeft))
+	row.Add("columns", NewButtonGroup(&sdl.FRect{0, 0, 256, 32}, false, func(index int) {
+		kanban.Columns = index
+		kanban.Scroll = Point{}
+	}, nil, "Status", "Tags"))
+
+	row = root.AddRow(AlignCenter)
+	row.Add("kanban", kanban)
+
+	root.OnUpdate = func() {
+		kanban.Rect.W = float32(math.Max(float64(root.Rect.W-32), 250))
+		kanban.Rect.H = float32(math.Max(float64(root.Rect.H-96), 64))
+	}
 
 	// Stats Menu
 
@@ -3025,6 +4194,58 @@ func ConstructMenus() {
 	row = root.AddRow(AlignCenter)
 	row.Add("", NewLabel("Stats", nil, false, AlignCenter))
 
+	row = root.AddRow(AlignLeft)
+	row.Add("", NewLabel("Scope:", nil, false, AlignLeft))
+	statsScope := NewButtonGroup(&sdl.FRect{0, 0, 384, 32}, false, func(index int) {}, nil, statsScopeNames...)
+	row.Add("scope", statsScope)
+
+	// The progress history of the Pages in the chosen scope is only combined again when the scope or the project changes
+	type progressHistoryKey struct {
+		Project    *Project
+		Page       *Page
+		Scope      int
+		UndoFrame  *UndoFrame
+		UndoIndex  int
+		UndoFrames int
+		Recorded   string
+		Day        string
+	}
+
+	var progressKey progressHistoryKey
+	var progressHistory []*ProgressSnapshot
+	progressHistoryVersion := 0 // Incremented each time the history is combined again
+
+	scopedProgressHistory := func() []*ProgressSnapshot {
+
+		project := globals.Project
+
+		key := progressHistoryKey{
+			Project:    project,
+			Page:       project.CurrentPage,
+			Scope:      statsScope.ChosenIndex,
+			UndoFrame:  project.UndoHistory.CurrentFrame,
+			UndoIndex:  project.UndoHistory.Index,
+			UndoFrames: len(project.UndoHistory.Frames),
+			Day:        time.Now().Format(DeadlineDateFormat),
+		}
+
+		if recorded := project.Properties.GetIfExists(ProjectProgressHistory); recorded != nil && recorded.IsString() {
+			key.Recorded = recorded.AsString()
+		}
+
+		if progressHistory == nil || key != progressKey {
+			progressKey = key
+			progressHistory = CombinedProgressHistory(StatsPages(project, statsScope.ChosenIndex))
+			progressHistoryVersion++
+		}
+
+		return progressHistory
+
+	}
+	row.Add("breakdown", NewButton("Breakdown", nil, nil, false, func() {
+		stats.SetPage("breakdown")
+	}))
+
 	row = root.AddRow(AlignLeft)
 	maxLabel := NewLabel("so many cards existing", nil, false, AlignLeft)
 	row.Add("", maxLabel)
@@ -3034,6 +4255,25 @@ func ConstructMenus() {
 	row.Add("", completedLabel)
 	row.ExpandAllElements = true
 
+	row = root.AddRow(AlignLeft)
+	priorityLabel := NewLabel("so many cards per priority", nil, false, AlignLeft)
+	row.Add("", priorityLabel)
+	row.ExpandAllElements = true
+
+	row = root.AddRow(AlignLeft)
+	trackedLabel := NewLabel("so much time tracked", nil, false, AlignLeft)
+	row.Add("", trackedLabel)
+	row.Add("time report", NewButton("Time Report", nil, nil, false, func() {
+		stats.SetPage("time report")
+	}))
+
+	row = root.AddRow(AlignLeft)
+	projectedLabel := NewLabel("projected completion", nil, false, AlignLeft)
+	row.Add("", projectedLabel)
+	row.Add("progress charts", NewButton("Progress Charts", nil, nil, false, func() {
+		stats.SetPage("progress charts")
+	}))
+
 	row = root.AddRow(AlignLeft)
 	row.Add("", NewSpacer(&sdl.FRect{0, 0, 32, 1}))
 
@@ -3073,29 +4313,65 @@ func ConstructMenus() {
 
 	root.OnUpdate = func() {
 
-		maxLabel.SetText([]rune(fmt.Sprintf("Total Cards: %d Cards", len(globals.Project.CurrentPage.Cards))))
+		totals := &CompletionStats{}
+
+		priorityTotals := make([]int, len(priorityNames))
+		priorityCompleted := make([]int, len(priorityNames))
 
-		completionLevel := float32(0)
-		maxLevel := float32(0)
-		totalCompletable := 0
-		completedCards := 0
+		for _, page := range StatsPages(globals.Project, statsScope.ChosenIndex) {
 
-		for _, i := range globals.Project.CurrentPage.Cards {
+			for _, i := range page.Cards {
 
-			if i.Numberable() {
+				if !i.Valid {
+					continue
+				}
+
+				totals.Add(i)
 
-				maxLevel += i.MaximumCompletionLevel()
-				completionLevel += i.CompletionLevel()
+				if i.Numberable() {
+
+					priorityTotals[i.Priority()]++
+					if i.Completed() {
+						priorityCompleted[i.Priority()]++
+					}
 
-				totalCompletable++
-				if i.Completed() {
-					completedCards++
 				}
 
 			}
 
 		}
 
+		maxLabel.SetText([]rune(fmt.Sprintf("Total Cards: %d Cards", totals.Cards)))
+
+		completionLevel := totals.CompletionLevel
+		maxLevel := totals.MaxLevel
+		totalCompletable := totals.Completable
+		completedCards := totals.Completed
+
+		priorityText := ""
+		for p := PriorityCritical; p > PriorityNone; p-- {
+			if priorityTotals[p] > 0 {
+				if priorityText != "" {
+					priorityText += ", "
+				}
+				priorityText += fmt.Sprintf("%s: %d / %d", priorityNames[p], priorityCompleted[p], priorityTotals[p])
+			}
+		}
+
+		if priorityText == "" {
+			priorityText = "None"
+		}
+
+		priorityLabel.SetText([]rune("Completed by Priority: " + priorityText))
+
+		trackedLabel.SetText([]rune("Time Tracked: " + FormatTrackedTime(totals.Tracked)))
+
+		if projected, ok := ProjectedCompletion(scopedProgressHistory()); ok {
+			projectedLabel.SetText([]rune("Projected Completion: " + projected.Format("Monday, January 2, 2006")))
+		} else {
+			projectedLabel.SetText([]rune("Projected Completion: Not enough progress recorded"))
+		}
+
 		if maxLevel == 0 {
 			completedLabel.SetText([]rune("Total Cards Completed: 0 / 0 (0%)"))
 		} else {
@@ -3137,6 +4413,287 @@ func ConstructMenus() {
 
 	}
 
+	// Breakdown of the stats per page and per content type
+
+	breakdown := stats.AddPage("breakdown")
+
+	breakdownTitle := NewLabel("Breakdown", nil, false, AlignCenter)
+	breakdown.AddRow(AlignCenter).Add("", breakdownTitle)
+
+	breakdownList := NewContainer(&sdl.FRect{0, 0, 512, 128}, false)
+	breakdown.AddRow(AlignCenter).Add("breakdown", breakdownList)
+
+	lastBreakdown := time.Time{}
+
+	breakdown.OnUpdate = func() {
+
+		breakdownList.Rect.W = float32(math.Max(float64(breakdown.Rect.W-32), 250))
+		breakdownList.Rect.H = float32(math.Max(float64(breakdown.Rect.H-96), 64))
+
+		if time.Since(lastBreakdown) < time.Second {
+			return
+		}
+
+		lastBreakdown = time.Now()
+
+		breakdownTitle.SetText([]rune("Breakdown (" + statsScopeNames[statsScope.ChosenIndex] + ")"))
+
+		breakdownList.Rows = []*ContainerRow{}
+
+		addHeader := func(text string) {
+			header := NewContainerRow(breakdownList, AlignCenter)
+			header.Add("", NewLabel(text, nil, false, AlignCenter))
+			breakdownList.Rows = append(breakdownList.Rows, header)
+		}
+
+		addEntry := func(name string, entryStats *CompletionStats) {
+			entryRow := NewContainerRow(breakdownList, AlignLeft)
+			entryRow.AlternateBGColor = true
+			nameLabel := NewLabel(name, nil, false, AlignLeft)
+			nameLabel.SetMaxSize(320, 32)
+			entryRow.Add("name", nameLabel)
+			entryRow.Add("stats", NewLabel(entryStats.String(), nil, false, AlignRight))
+			entryRow.ExpandSelectedElements = []MenuElement{nameLabel}
+			breakdownList.Rows = append(breakdownList.Rows, entryRow)
+		}
+
+		typeNames := []string{}
+		typeStats := map[string]*CompletionStats{}
+
+		addHeader("By Page")
+
+		for _, page := range StatsPages(globals.Project, statsScope.ChosenIndex) {
+
+			pageStats := &CompletionStats{}
+
+			for _, card := range page.Cards {
+
+				if !card.Valid {
+					continue
+				}
+
+				pageStats.Add(card)
+
+				if _, exists := typeStats[card.ContentType]; !exists {
+					typeNames = append(typeNames, card.ContentType)
+					typeStats[card.ContentType] = &CompletionStats{}
+				}
+
+				typeStats[card.ContentType].Add(card)
+
+			}
+
+			addEntry(page.Path(), pageStats)
+
+		}
+
+		addHeader("By Type")
+
+		sort.Strings(typeNames)
+
+		for _, contentType := range typeNames {
+			addEntry(contentType, typeStats[contentType])
+		}
+
+	}
+
+	// Progress charts
+
+	progressPage := stats.AddPage("progress charts")
+
+	progressPage.AddRow(AlignCenter).Add("", NewLabel("Progress Charts", nil, false, AlignCenter))
+
+	progressChart := NewProgressChart(&sdl.FRect{0, 0, 640, 256})
+
+	row = progressPage.AddRow(AlignCenter)
+	row.Add("", NewLabel("Chart :", nil, false, AlignLeft))
+	row.Add("chart type", NewButtonGroup(&sdl.FRect{0, 0, 384, 32}, false, func(index int) {
+		progressChart.Mode = index
+	}, nil, progressChartNames...))
+
+	progressPage.AddRow(AlignCenter).Add("chart", progressChart)
+
+	progressSummary := NewLabel("Progress is recorded each time the project is saved.", nil, false, AlignCenter)
+	progressPage.AddRow(AlignCenter).Add("summary", progressSummary)
+
+	chartedVersion := -1
+
+	progressPage.OnUpdate = func() {
+
+		progressChart.Rect.W = float32(math.Max(float64(progressPage.Rect.W-32), 250))
+		progressChart.Rect.H = float32(math.Max(float64(progressPage.Rect.H-144), 96))
+
+		// The charts cover the same Pages as the rest of the Stats menu
+		history := scopedProgressHistory()
+
+		if chartedVersion == progressHistoryVersion {
+			return
+		}
+
+		chartedVersion = progressHistoryVersion
+
+		progressChart.History = history
+
+		summary := "Progress is recorded each time the project is saved."
+
+		if len(progressChart.History) > 0 {
+
+			latest := progressChart.History[len(progressChart.History)-1]
+			summary = fmt.Sprintf("%s: %d / %d Cards completed", statsScopeNames[statsScope.ChosenIndex], latest.Completed, latest.Total)
+
+			if projected, ok := ProjectedCompletion(progressChart.History); ok {
+				summary += "; projected completion: " + projected.Format("Monday, January 2, 2006")
+			} else {
+				summary += "; not enough progress recorded to project completion"
+			}
+
+		}
+
+		progressSummary.SetText([]rune(summary))
+
+	}
+
+	// Time report
+
+	timeReport := stats.AddPage("time report")
+
+	timeReport.AddRow(AlignCenter).Add("", NewLabel("Time Report", nil, false, AlignCenter))
+
+	reportFrom := NewLabel("", &sdl.FRect{0, 0, 160, 32}, false, AlignCenter)
+	reportFrom.Editable = true
+	reportFrom.RegexString = RegexNoNewlines
+
+	reportTo := NewLabel("", &sdl.FRect{0, 0, 160, 32}, false, AlignCenter)
+	reportTo.Editable = true
+	reportTo.RegexString = RegexNoNewlines
+
+	setReportRange := func(from, to time.Time) {
+		reportFrom.SetText([]rune(from.Format(DeadlineDateFormat)))
+		reportTo.SetText([]rune(to.Format(DeadlineDateFormat)))
+	}
+
+	reportToday := time.Now()
+	setReportRange(time.Date(reportToday.Year(), reportToday.Month(), 1, 0, 0, 0, 0, time.Local), reportToday)
+
+	row = timeReport.AddRow(AlignCenter)
+	row.Add("", NewLabel("From :", nil, false, AlignLeft))
+	row.Add("from", reportFrom)
+	row.Add("", NewLabel("To :", nil, false, AlignLeft))
+	row.Add("to", reportTo)
+
+	row = timeReport.AddRow(AlignCenter)
+	row.ExpandAllElements = true
+	row.Add("today", NewButton("Today", nil, nil, false, func() {
+		now := time.Now()
+		setReportRange(now, now)
+	}))
+	row.Add("this week", NewButton("This Week", nil, nil, false, func() {
+		now := time.Now()
+		setReportRange(now.AddDate(0, 0, -int(now.Weekday())), now)
+	}))
+	row.Add("this month", NewButton("This Month", nil, nil, false, func() {
+		now := time.Now()
+		setReportRange(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local), now)
+	}))
+
+	row = timeReport.AddRow(AlignCenter)
+	row.Add("", NewLabel("Group By :", nil, false, AlignLeft))
+	reportGrouping := NewButtonGroup(&sdl.FRect{0, 0, 384, 32}, false, nil, nil, timeReportGroupNames...)
+	row.Add("grouping", reportGrouping)
+
+	reportTotal := NewLabel("Total : 0:00:00", nil, false, AlignCenter)
+	timeReport.AddRow(AlignCenter).Add("total", reportTotal)
+
+	reportList := NewContainer(&sdl.FRect{0, 0, 512, 128}, false)
+	timeReport.AddRow(AlignCenter).Add("report", reportList)
+
+	reportEntries := []*TimeReportEntry{}
+	lastReport := time.Time{}
+
+	refreshTimeReport := func() {
+
+		lastReport = time.Now()
+		reportList.Rows = []*ContainerRow{}
+		reportEntries = []*TimeReportEntry{}
+
+		from, fromErr := time.ParseInLocation(DeadlineDateFormat, strings.TrimSpace(reportFrom.TextAsString()), time.Local)
+		to, toErr := time.ParseInLocation(DeadlineDateFormat, strings.TrimSpace(reportTo.TextAsString()), time.Local)
+
+		if fromErr != nil || toErr != nil {
+			reportTotal.SetText([]rune("Dates should be in YYYY-MM-DD format"))
+			return
+		}
+
+		reportEntries = BuildTimeReport(globals.Project, from, to, reportGrouping.ChosenIndex)
+
+		total := time.Duration(0)
+
+		for _, entry := range reportEntries {
+
+			// Tags overlap, so the total is only summed when grouping by page or day
+			if reportGrouping.ChosenIndex != TimeReportByTag {
+				total += entry.Duration
+			}
+
+			entryRow := NewContainerRow(reportList, AlignLeft)
+			entryRow.AlternateBGColor = true
+			name := NewLabel(entry.Name, nil, false, AlignLeft)
+			name.SetMaxSize(320, 32)
+			entryRow.Add("name", name)
+			entryRow.Add("time", NewLabel(FormatTrackedTime(entry.Duration), nil, false, AlignRight))
+			entryRow.ExpandSelectedElements = []MenuElement{name}
+			reportList.Rows = append(reportList.Rows, entryRow)
+
+		}
+
+		if len(reportEntries) == 0 {
+			reportTotal.SetText([]rune("No time tracked in this range"))
+		} else if reportGrouping.ChosenIndex == TimeReportByTag {
+			reportTotal.SetText([]rune(fmt.Sprintf("%d tag(s)", len(reportEntries))))
+		} else {
+			reportTotal.SetText([]rune("Total : " + FormatTrackedTime(total)))
+		}
+
+	}
+
+	reportFrom.OnChange = refreshTimeReport
+	reportTo.OnChange = refreshTimeReport
+	reportGrouping.OnChoose = func(index int) { refreshTimeReport() }
+	timeReport.OnOpen = refreshTimeReport
+
+	row = timeReport.AddRow(AlignCenter)
+	row.Add("export csv", NewButton("Export CSV", nil, nil, false, func() {
+
+		if filename, err := zenity.SelectFileSave(zenity.Title("Export Time Report..."), zenity.ConfirmOverwrite(), zenity.FileFilter{Name: "CSV File (*.csv)", Patterns: []string{"*.csv"}}); err == nil {
+
+			if filepath.Ext(filename) != ".csv" {
+				filename += ".csv"
+			}
+
+			if err := ExportTimeReportCSV(filename, reportEntries, reportGrouping.ChosenIndex); err != nil {
+				globals.EventLog.Log("Error exporting time report: %s", true, err.Error())
+			} else {
+				globals.EventLog.Log("Time report exported to %s.", false, filename)
+			}
+
+		} else if err != zenity.ErrCanceled {
+			globals.EventLog.Log("Error exporting time report: %s", true, err.Error())
+		}
+
+	}))
+
+	timeReport.OnUpdate = func() {
+
+		reportList.Rect.W = float32(math.Max(float64(timeReport.Rect.W-32), 250))
+		reportList.Rect.H = float32(math.Max(float64(timeReport.Rect.H-272), 64))
+
+		// Refresh periodically so that time currently being tracked is included
+		if time.Since(lastReport) > time.Second {
+			refreshTimeReport()
+		}
+
+	}
+
 	// Map palette menu
 
 	paletteMenu := globals.MenuSystem.Add(NewMenu(&sdl.FRect{0, 0, 200, 560}, MenuCloseButton), "map palette menu", false)
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var markdownHeading = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+var markdownQuote = regexp.MustCompile(`^\s*>\s?(.*)$`)
+var markdownBullet = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+var markdownNumbered = regexp.MustCompile(`^(\s*)(\d+)[.)]\s+(.*)$`)
+var markdownLink = regexp.MustCompile(`^\[([^\]]+)\]\(([^)\s]+)\)`)
+var markdownURL = regexp.MustCompile(`^https?://[^\s]+`)
+var markdownAutolink = regexp.MustCompile(`^<(https?://[^\s>]+)>`)
+
+// IsMarkdownHeading returns if the given line of text is a Markdown heading (i.e. "# Title").
+func IsMarkdownHeading(line string) bool {
+	return markdownHeading.MatchString(line)
+}
+
+// markdownRuns is a list of TextRuns; adding text in the same style as the previous run extends that run.
+type markdownRuns []TextRun
+
+func (runs *markdownRuns) Add(text string, style TextStyle, link string) {
+	if text == "" {
+		return
+	}
+	if len(*runs) > 0 {
+		last := &(*runs)[len(*runs)-1]
+		if last.Style == style && last.Link == link && last.Icon == nil {
+			last.Text += text
+			return
+		}
+	}
+	*runs = append(*runs, TextRun{Text: text, Style: style, Link: link})
+}
+
+// ParseMarkdown parses Markdown text into runs of styled text for rendering. Headings, bold, italic and strikethrough text, inline code, code
+// blocks, bulleted and numbered lists, inline checklists, blockquotes, and links are supported; the Markdown syntax itself is removed from the
+// rendered text.
+func ParseMarkdown(text string) []TextRun {
+
+	runs := markdownRuns{}
+
+	bullet := "-"
+	if globals.TextRenderer.Glyph('•') != nil {
+		bullet = "•"
+	}
+
+	inCodeBlock := false
+
+	lines := strings.Split(text, "\n")
+
+	for i, line := range lines {
+
+		lineStyle := TextStyle(0)
+
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			// Code fences aren't displayed
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+
+		if inCodeBlock {
+			lineStyle = TextStyleCodeBlock | TextStyleMonospace
+			runs.Add(line, lineStyle, "")
+		} else if match := checklistItem.FindStringSubmatch(line); match != nil {
+			runs.Add(match[1], 0, "")
+			runs = append(runs, checklistBoxRun(i, match[2] != " "))
+			runs.Add(" ", 0, "")
+			parseMarkdownInline(&runs, match[3], 0)
+		} else if match := markdownHeading.FindStringSubmatch(line); match != nil {
+			lineStyle = TextStyleBold
+			if len(match[1]) == 1 {
+				lineStyle |= TextStyleUnderline
+			}
+			parseMarkdownInline(&runs, match[2], lineStyle)
+		} else if match := markdownQuote.FindStringSubmatch(line); match != nil {
+			lineStyle = TextStyleQuote
+			runs.Add("  ", lineStyle, "")
+			parseMarkdownInline(&runs, match[1], lineStyle)
+		} else if match := markdownBullet.FindStringSubmatch(line); match != nil {
+			runs.Add(match[1]+bullet+" ", 0, "")
+			parseMarkdownInline(&runs, match[2], 0)
+		} else if match := markdownNumbered.FindStringSubmatch(line); match != nil {
+			runs.Add(match[1]+match[2]+". ", 0, "")
+			parseMarkdownInline(&runs, match[3], 0)
+		} else {
+			parseMarkdownInline(&runs, line, 0)
+		}
+
+		if i < len(lines)-1 {
+			runs.Add("\n", lineStyle, "")
+		}
+
+	}
+
+	// Drop a trailing newline left over from skipping a closing code fence
+	if len(runs) > 0 {
+		last := &runs[len(runs)-1]
+		last.Text = strings.TrimSuffix(last.Text, "\n")
+	}
+
+	return runs
+
+}
+
+// parseMarkdownInline parses the inline styling (emphasis, code, and links) of a single line of Markdown text.
+func parseMarkdownInline(runs *markdownRuns, line string, baseStyle TextStyle) {
+
+	style := baseStyle
+	chars := []rune(line)
+	plain := []rune{}
+
+	flush := func() {
+		runs.Add(string(plain), style, "")
+		plain = []rune{}
+	}
+
+	isWordChar := func(index int) bool {
+		return index >= 0 && index < len(chars) && (unicode.IsLetter(chars[index]) || unicode.IsDigit(chars[index]))
+	}
+
+	// toggle switches the given style if the marker at index opens an emphasis that's closed later on, or closes one that's open.
+	toggle := func(index int, marker string, markerStyle TextStyle) bool {
+
+		end := index + len([]rune(marker))
+
+		if baseStyle&markerStyle > 0 {
+			return true // The whole line is already in this style (i.e. bold text in a heading), so the marker is just dropped
+		}
+
+		if style&markerStyle > 0 {
+			if (index > 0 && unicode.IsSpace(chars[index-1])) || (marker[0] == '_' && isWordChar(end)) {
+				return false
+			}
+		} else {
+			if end >= len(chars) || unicode.IsSpace(chars[end]) || (marker[0] == '_' && isWordChar(index-1)) {
+				return false
+			}
+			if !strings.Contains(string(chars[end+1:]), marker) {
+				return false
+			}
+		}
+
+		flush()
+		style ^= markerStyle
+		return true
+
+	}
+
+	for i := 0; i < len(chars); i++ {
+
+		c := chars[i]
+		rest := string(chars[i:])
+
+		switch {
+
+		case c == '\\' && i+1 < len(chars) && strings.ContainsRune("\\`*_~[]()#>-+.!", chars[i+1]):
+			plain = append(plain, chars[i+1])
+			i++
+
+		case c == '`':
+			end := -1
+			for j := i + 1; j < len(chars); j++ {
+				if chars[j] == '`' {
+					end = j
+					break
+				}
+			}
+			if end >= 0 {
+				flush()
+				runs.Add(string(chars[i+1:end]), style|TextStyleCode|TextStyleMonospace, "")
+				i = end
+			} else {
+				plain = append(plain, c)
+			}
+
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if toggle(i, rest[:2], TextStyleBold) {
+				i++
+			} else {
+				plain = append(plain, c, chars[i+1])
+				i++
+			}
+
+		case strings.HasPrefix(rest, "~~"):
+			if toggle(i, "~~", TextStyleStrikethrough) {
+				i++
+			} else {
+				plain = append(plain, c, chars[i+1])
+				i++
+			}
+
+		case c == '*' || c == '_':
+			if !toggle(i, string(c), TextStyleItalic) {
+				plain = append(plain, c)
+			}
+
+		case c == '[' && markdownLink.MatchString(rest):
+			match := markdownLink.FindStringSubmatch(rest)
+			flush()
+			runs.Add(match[1], style|TextStyleUnderline, match[2])
+			i += len([]rune(match[0])) - 1
+
+		case c == '<' && markdownAutolink.MatchString(rest):
+			match := markdownAutolink.FindStringSubmatch(rest)
+			flush()
+			runs.Add(match[1], style|TextStyleUnderline, match[1])
+			i += len([]rune(match[0])) - 1
+
+		case c == 'h' && !isWordChar(i-1) && markdownURL.MatchString(rest):
+			url := strings.TrimRight(markdownURL.FindString(rest), ".,;:!?)")
+			flush()
+			runs.Add(url, style|TextStyleUnderline, url)
+			i += len([]rune(url)) - 1
+
+		default:
+			plain = append(plain, c)
+
+		}
+
+	}
+
+	flush()
+
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsMarkdownHeading(t *testing.T) {
+
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"# Title", true},
+		{"###### Small title", true},
+		{"####### Too many", false},
+		{"#hashtag", false},
+		{" # Indented", false},
+		{"Not # a heading", false},
+	}
+
+	for _, test := range tests {
+		if got := IsMarkdownHeading(test.line); got != test.want {
+			t.Errorf("IsMarkdownHeading(%q) = %v, want %v", test.line, got, test.want)
+		}
+	}
+
+}
+
+func TestParseMarkdownInline(t *testing.T) {
+
+	code := TextStyleCode | TextStyleMonospace
+
+	tests := []struct {
+		line      string
+		baseStyle TextStyle
+		want      []TextRun
+	}{
+		{"plain text", 0, []TextRun{{Text: "plain text"}}},
+		{"a **bold** b", 0, []TextRun{{Text: "a "}, {Text: "bold", Style: TextStyleBold}, {Text: " b"}}},
+		{"__bold__", 0, []TextRun{{Text: "bold", Style: TextStyleBold}}},
+		{"*it* and _it_", 0, []TextRun{{Text: "it", Style: TextStyleItalic}, {Text: " and "}, {Text: "it", Style: TextStyleItalic}}},
+		{"~~gone~~ here", 0, []TextRun{{Text: "gone", Style: TextStyleStrikethrough}, {Text: " here"}}},
+		{"**bold _both_**", 0, []TextRun{{Text: "bold ", Style: TextStyleBold}, {Text: "both", Style: TextStyleBold | TextStyleItalic}}},
+
+		// Markers that don't open or close emphasis are left as they are
+		{"snake_case_name", 0, []TextRun{{Text: "snake_case_name"}}},
+		{"2 * 3 * 4", 0, []TextRun{{Text: "2 * 3 * 4"}}},
+		{"**unclosed", 0, []TextRun{{Text: "**unclosed"}}},
+		{"`unclosed", 0, []TextRun{{Text: "`unclosed"}}},
+		{"\\*escaped\\* \\`too\\`", 0, []TextRun{{Text: "*escaped* `too`"}}},
+
+		{"run `go *test*` now", 0, []TextRun{{Text: "run "}, {Text: "go *test*", Style: code}, {Text: " now"}}},
+		{"**`bold code`**", 0, []TextRun{{Text: "bold code", Style: TextStyleBold | code}}},
+
+		{"see [the site](https://example.com) ok", 0, []TextRun{
+			{Text: "see "}, {Text: "the site", Style: TextStyleUnderline, Link: "https://example.com"}, {Text: " ok"},
+		}},
+		{"go to https://example.com/a_b.", 0, []TextRun{
+			{Text: "go to "}, {Text: "https://example.com/a_b", Style: TextStyleUnderline, Link: "https://example.com/a_b"}, {Text: "."},
+		}},
+		{"<https://example.com>", 0, []TextRun{{Text: "https://example.com", Style: TextStyleUnderline, Link: "https://example.com"}}},
+		{"nothttps://example.com", 0, []TextRun{{Text: "nothttps://example.com"}}},
+
+		// In a heading, the whole line's already bold, so bold markers are dropped
+		{"Big **news**", TextStyleBold, []TextRun{{Text: "Big news", Style: TextStyleBold}}},
+		{"", 0, []TextRun{}},
+	}
+
+	for _, test := range tests {
+
+		runs := markdownRuns{}
+		parseMarkdownInline(&runs, test.line, test.baseStyle)
+
+		if got := []TextRun(runs); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parseMarkdownInline(%q) = %+v, want %+v", test.line, got, test.want)
+		}
+
+	}
+
+}
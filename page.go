@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
@@ -36,6 +37,8 @@ type Page struct {
 	DeserializationLinks []string
 
 	PointingSubpageCard *Card
+
+	CriticalPath map[*Card]bool // The Cards on the Page's critical path, if it's being highlighted
 }
 
 var globalPageID = uint64(0)
@@ -65,6 +68,8 @@ func NewPage(project *Project) *Page {
 
 func (page *Page) Update() {
 
+	page.updateFrames()
+
 	reversed := append([]*Card{}, page.Cards...)
 
 	sort.SliceStable(reversed, func(i, j int) bool {
@@ -88,6 +93,12 @@ func (page *Page) Update() {
 		card.Update()
 	}
 
+	if page.IsCurrent() && page.Project.HighlightCriticalPath {
+		page.CriticalPath = page.Schedule().CriticalPath
+	} else {
+		page.CriticalPath = nil
+	}
+
 	if page.IsCurrent() {
 
 		// We only want to set the pan and zoom of a page if it's not loading the project (as it sets the page to be current to take screenshots for subpages).
@@ -118,6 +129,40 @@ func (page *Page) Update() {
 
 }
 
+// updateFrames hides the Cards within collapsed Frames.
+func (page *Page) updateFrames() {
+
+	hidden := map[*Card]bool{}
+
+	for _, card := range page.Cards {
+		if frame, ok := card.Contents.(*FrameContents); ok && card.Valid && card.Collapsed != CollapsedNone {
+			for _, member := range frame.HiddenMembers() {
+				hidden[member] = true
+			}
+		}
+	}
+
+	for _, card := range page.Cards {
+
+		if card.hidden != hidden[card] {
+			card.hidden = hidden[card]
+			// Hidden Cards are taken out of the Grid, and put back in when they're shown again
+			if card.hidden {
+				page.Grid.Remove(card)
+			} else if card.Valid {
+				page.Grid.Put(card)
+			}
+			page.UpdateStacks = true
+		}
+
+		if card.hidden && card.selected {
+			page.Selection.Remove(card)
+		}
+
+	}
+
+}
+
 func (page *Page) IsCurrent() bool {
 	return page.Project.CurrentPage == page
 }
@@ -238,6 +283,54 @@ func (page *Page) Name() string {
 	return "Root"
 }
 
+// Path returns the names of the Pages leading from the root Page to this one, separated by slashes.
+func (page *Page) Path() string {
+
+	path := page.Name()
+	p := page
+
+	// We limit the depth in case an orphaned Page ends up pointing to itself
+	for i := 0; i < len(page.Project.Pages) && p.PointingSubpageCard != nil; i++ {
+		p = p.PointingSubpageCard.Page
+		path = p.Name() + " / " + path
+	}
+
+	return path
+
+}
+
+// WithSubpages returns the Page, followed by all of the Pages nested within it through Sub-Page Cards.
+func (page *Page) WithSubpages() []*Page {
+
+	pages := []*Page{}
+	visited := map[*Page]bool{}
+
+	var add func(p *Page)
+
+	add = func(p *Page) {
+
+		// Sub-Pages can't normally contain their parents, but we guard against cycles just in case
+		if visited[p] {
+			return
+		}
+
+		visited[p] = true
+		pages = append(pages, p)
+
+		for _, card := range p.Cards {
+			if subpage, ok := card.Contents.(*SubPageContents); ok && card.Valid && subpage.SubPage != nil {
+				add(subpage.SubPage)
+			}
+		}
+
+	}
+
+	add(page)
+
+	return pages
+
+}
+
 func (page *Page) Serialize() string {
 
 	pageData := "{}"
@@ -329,6 +422,9 @@ func (page *Page) UpdateLinks() {
 			link, fresh := start.Link(end)
 			joints := gjson.Get(linkString, "joints").Array()
 			// If the link wasn't freshly created, then the joints should have been set already
+			if link != nil {
+				link.Blocking = gjson.Get(linkString, "blocking").Bool()
+			}
 			if link != nil && fresh {
 				link.Joints = []*LinkJoint{}
 				for _, joint := range joints {
@@ -503,6 +599,57 @@ func (page *Page) PasteCards(offset Point, adhereToMousePosition bool) []*Card {
 
 }
 
+// FrameSelection wraps a Frame around the selected Cards. If one of the selected Cards is a Frame, it's resized to fit the others; otherwise, a
+// new Frame is created.
+func (page *Page) FrameSelection() *Card {
+
+	gs := globals.GridSize
+
+	var frame *Card
+	cards := []*Card{}
+
+	for _, card := range page.Selection.AsSlice() {
+		if card.ContentType == ContentTypeFrame && frame == nil {
+			frame = card
+		} else {
+			cards = append(cards, card)
+		}
+	}
+
+	if len(cards) == 0 {
+		return nil
+	}
+
+	bounds := NewCorrectingRect(cards[0].Rect.X, cards[0].Rect.Y, cards[0].Rect.X+cards[0].Rect.W, cards[0].Rect.Y+cards[0].Rect.H)
+
+	for _, card := range cards[1:] {
+		bounds.X1 = float32(math.Min(float64(bounds.X1), float64(card.Rect.X)))
+		bounds.Y1 = float32(math.Min(float64(bounds.Y1), float64(card.Rect.Y)))
+		bounds.X2 = float32(math.Max(float64(bounds.X2), float64(card.Rect.X+card.Rect.W)))
+		bounds.Y2 = float32(math.Max(float64(bounds.Y2), float64(card.Rect.Y+card.Rect.H)))
+	}
+
+	if frame == nil {
+		frame = page.CreateNewCard(ContentTypeFrame)
+	} else if frame.Collapsed != CollapsedNone {
+		frame.Collapse()
+	}
+
+	// There's a margin around the Cards, with extra room at the top for the Frame's title
+	frame.Rect.X = bounds.X1 - gs
+	frame.Rect.Y = bounds.Y1 - (gs * 2)
+	frame.Recreate(bounds.X2-bounds.X1+(gs*2), bounds.Y2-bounds.Y1+(gs*3))
+	frame.UncollapsedSize = Point{frame.Rect.W, frame.Rect.H}
+	frame.LockPosition()
+	frame.CreateUndoState = true
+
+	page.Selection.Clear()
+	page.Selection.Add(frame)
+
+	return frame
+
+}
+
 func (page *Page) Raise(card *Card) {
 
 	if len(page.Cards) <= 1 {
@@ -515,6 +662,12 @@ func (page *Page) Raise(card *Card) {
 
 func (page *Page) HandleDroppedFiles(filePath string) {
 
+	if IsTableFile(filePath) {
+		card := page.CreateNewCard(ContentTypeTable)
+		card.Contents.(*TableContents).ImportFile(filePath, false)
+		return
+	}
+
 	mime, _ := mimetype.DetectFile(filePath)
 	mimeType := mime.String()
 
@@ -615,6 +768,15 @@ func (page *Page) HandleExternalPaste() {
 				return
 			}
 
+			// Cells copied from a spreadsheet come in as tab-separated rows
+			if data, ok := ParseTabSeparatedTable(text); ok {
+				card := page.CreateNewCard(ContentTypeTable)
+				card.Contents.(*TableContents).SetData(data)
+				globals.EventLog.Log("Pasted %d rows from clipboard content into a new Table.", false, len(data.Rows))
+				page.UpdateStacks = true
+				return
+			}
+
 			todoList := strings.HasPrefix(tl[0], "[")
 
 			if todoList {
@@ -0,0 +1,447 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	ProgressChartBurndown = iota
+	ProgressChartBurnup
+	ProgressChartVelocity
+)
+
+var progressChartNames = []string{"Burndown", "Burnup", "Velocity"}
+
+// ProgressSnapshot records how many of a Page's complete-able Cards existed and were completed on a given day.
+type ProgressSnapshot struct {
+	Date      time.Time
+	Total     int
+	Completed int
+}
+
+// Remaining returns how many Cards were left to complete at the time of the snapshot.
+func (snapshot *ProgressSnapshot) Remaining() int {
+	return snapshot.Total - snapshot.Completed
+}
+
+// ProgressSnapshot takes a snapshot of how many of the Page's complete-able Cards are currently completed.
+func (page *Page) ProgressSnapshot() *ProgressSnapshot {
+
+	now := time.Now()
+	snapshot := &ProgressSnapshot{Date: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)}
+
+	for _, card := range page.Cards {
+		if card.Valid && card.Numberable() {
+			snapshot.Total++
+			if card.Completed() {
+				snapshot.Completed++
+			}
+		}
+	}
+
+	return snapshot
+
+}
+
+// ProgressHistory returns the snapshots recorded for the Page each time the project was saved, oldest first.
+func (page *Page) ProgressHistory() []*ProgressSnapshot {
+
+	history := []*ProgressSnapshot{}
+
+	prop := page.Project.Properties.GetIfExists(ProjectProgressHistory)
+
+	if prop == nil || !prop.IsString() {
+		return history
+	}
+
+	for _, pageData := range gjson.Parse(prop.AsString()).Array() {
+
+		if pageData.Get("page").Uint() != page.ID {
+			continue
+		}
+
+		for _, data := range pageData.Get("history").Array() {
+			if date, err := time.ParseInLocation(DeadlineDateFormat, data.Get("date").String(), time.Local); err == nil {
+				history = append(history, &ProgressSnapshot{
+					Date:      date,
+					Total:     int(data.Get("total").Int()),
+					Completed: int(data.Get("completed").Int()),
+				})
+			}
+		}
+
+	}
+
+	return history
+
+}
+
+// LiveProgressHistory returns the Page's recorded progress history, with the last entry reflecting its current (possibly unsaved) state.
+func (page *Page) LiveProgressHistory() []*ProgressSnapshot {
+	return addProgressSnapshot(page.ProgressHistory(), page.ProgressSnapshot())
+}
+
+// CombinedProgressHistory returns the live progress histories of the given Pages added together. Pages aren't all recorded on the same days,
+// so on each day in any of the histories, each Page counts with its most recent snapshot up to then.
+func CombinedProgressHistory(pages []*Page) []*ProgressSnapshot {
+
+	histories := [][]*ProgressSnapshot{}
+	dates := []time.Time{}
+
+	for _, page := range pages {
+		history := page.LiveProgressHistory()
+		histories = append(histories, history)
+		for _, snapshot := range history {
+			dates = append(dates, snapshot.Date)
+		}
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	combined := []*ProgressSnapshot{}
+
+	for _, date := range dates {
+
+		if len(combined) > 0 && DatesAreEqual(combined[len(combined)-1].Date, date) {
+			continue
+		}
+
+		snapshot := &ProgressSnapshot{Date: date}
+
+		for _, history := range histories {
+
+			var latest *ProgressSnapshot
+			for _, s := range history {
+				if s.Date.After(date) {
+					break
+				}
+				latest = s
+			}
+
+			if latest != nil {
+				snapshot.Total += latest.Total
+				snapshot.Completed += latest.Completed
+			}
+
+		}
+
+		combined = append(combined, snapshot)
+
+	}
+
+	return combined
+
+}
+
+// addProgressSnapshot adds a snapshot to the end of a progress history; a day only has one snapshot, so a snapshot from the same day as the
+// last one replaces it.
+func addProgressSnapshot(history []*ProgressSnapshot, snapshot *ProgressSnapshot) []*ProgressSnapshot {
+
+	if len(history) > 0 && DatesAreEqual(history[len(history)-1].Date, snapshot.Date) {
+		history[len(history)-1] = snapshot
+		return history
+	}
+
+	return append(history, snapshot)
+
+}
+
+// RecordProgress records a completion snapshot for each of the Project's Pages, to be saved in the project file. This is called when saving.
+// Pages that have never had any complete-able Cards aren't recorded, and the history of deleted Pages is discarded.
+func (project *Project) RecordProgress() {
+
+	data := "[]"
+
+	for _, page := range project.Pages {
+
+		if !page.Valid() {
+			continue
+		}
+
+		history := page.ProgressHistory()
+		snapshot := page.ProgressSnapshot()
+
+		if len(history) == 0 && snapshot.Total == 0 {
+			continue
+		}
+
+		history = addProgressSnapshot(history, snapshot)
+
+		pageData, _ := sjson.Set("{}", "page", page.ID)
+
+		for _, snapshot := range history {
+			snapshotData, _ := sjson.Set("{}", "date", snapshot.Date.Format(DeadlineDateFormat))
+			snapshotData, _ = sjson.Set(snapshotData, "total", snapshot.Total)
+			snapshotData, _ = sjson.Set(snapshotData, "completed", snapshot.Completed)
+			pageData, _ = sjson.SetRaw(pageData, "history.-1", snapshotData)
+		}
+
+		data, _ = sjson.SetRaw(data, "-1", pageData)
+
+	}
+
+	project.Properties.Get(ProjectProgressHistory).Set(data)
+
+}
+
+// WeeklyVelocity returns how many Cards were completed in each week (starting on Monday) of a progress history, along with the date each
+// week starts on.
+func WeeklyVelocity(history []*ProgressSnapshot) ([]time.Time, []int) {
+
+	weeks := []time.Time{}
+	counts := []int{}
+
+	if len(history) == 0 {
+		return weeks, counts
+	}
+
+	weekStart := func(date time.Time) time.Time {
+		offset := (int(date.Weekday()) + 6) % 7 // Days since Monday
+		return time.Date(date.Year(), date.Month(), date.Day()-offset, 0, 0, 0, 0, time.Local)
+	}
+
+	previous := history[0].Completed
+	i := 0
+
+	for week := weekStart(history[0].Date); !week.After(history[len(history)-1].Date); week = week.AddDate(0, 0, 7) {
+
+		// The number completed at the end of the week is that of the last snapshot taken during or before it
+		completed := previous
+		for i < len(history) && history[i].Date.Before(week.AddDate(0, 0, 7)) {
+			completed = history[i].Completed
+			i++
+		}
+
+		weeks = append(weeks, week)
+		// Cards can be uncompleted or deleted, but that doesn't count as negative progress
+		counts = append(counts, int(math.Max(float64(completed-previous), 0)))
+
+		previous = completed
+
+	}
+
+	return weeks, counts
+
+}
+
+// ProjectedCompletion estimates when all of the complete-able Cards in a progress history will be completed, based on the average rate
+// Cards were completed at over the history. It returns false if there's not enough progress to estimate from.
+func ProjectedCompletion(history []*ProgressSnapshot) (time.Time, bool) {
+
+	if len(history) == 0 {
+		return time.Time{}, false
+	}
+
+	first, last := history[0], history[len(history)-1]
+
+	if last.Remaining() <= 0 {
+		return last.Date, true
+	}
+
+	days := last.Date.Sub(first.Date).Hours() / 24
+	progress := float64(last.Completed - first.Completed)
+
+	if days < 1 || progress <= 0 {
+		return time.Time{}, false
+	}
+
+	return last.Date.AddDate(0, 0, int(math.Ceil(float64(last.Remaining())/(progress/days)))), true
+
+}
+
+// ProgressChart is a MenuElement that plots a Page's progress history as a burndown chart (Cards remaining over time), a burnup chart
+// (Cards completed and total Cards over time), or a velocity chart (Cards completed each week). The burndown chart also shows the
+// projected completion date.
+type ProgressChart struct {
+	Rect    *sdl.FRect
+	Mode    int
+	History []*ProgressSnapshot
+}
+
+func NewProgressChart(rect *sdl.FRect) *ProgressChart {
+	return &ProgressChart{Rect: rect}
+}
+
+func (chart *ProgressChart) Update() {}
+
+func (chart *ProgressChart) Draw() {
+
+	rect := &sdl.Rect{int32(chart.Rect.X), int32(chart.Rect.Y), int32(chart.Rect.W), int32(chart.Rect.H)}
+
+	// Combine our clipping rectangle with the Container's, so we don't draw outside of it
+	if len(globals.ClipRects) > 0 {
+		if clipped, ok := rect.Intersect(globals.ClipRects[len(globals.ClipRects)-1]); ok {
+			rect = &clipped
+		} else {
+			return
+		}
+	}
+
+	globals.Renderer.SetClipRect(rect)
+	globals.ClipRects = append(globals.ClipRects, rect)
+
+	fontColor := getThemeColor(GUIFontColor)
+
+	FillRect(chart.Rect.X, chart.Rect.Y, chart.Rect.W, chart.Rect.H, getThemeColor(GUIBGColor))
+
+	if len(chart.History) == 0 {
+		globals.TextRenderer.QuickRenderText("No progress recorded yet; progress is recorded each time the project is saved.", Point{chart.Rect.X + chart.Rect.W/2, chart.Rect.Y + chart.Rect.H/2 - 8}, 0.5, fontColor, nil, AlignCenter)
+	} else {
+
+		// The plotting area, leaving room for the axis labels
+		plot := &sdl.FRect{chart.Rect.X + 40, chart.Rect.Y + 12, chart.Rect.W - 56, chart.Rect.H - 36}
+
+		lineColor := fontColor.Clone()
+		lineColor[3] = 64
+		ThickLine(Point{plot.X, plot.Y}, Point{plot.X, plot.Y + plot.H}, 1, lineColor)
+		ThickLine(Point{plot.X, plot.Y + plot.H}, Point{plot.X + plot.W, plot.Y + plot.H}, 1, lineColor)
+
+		if chart.Mode == ProgressChartVelocity {
+			chart.drawVelocity(plot)
+		} else {
+			chart.drawLines(plot)
+		}
+
+	}
+
+	globals.ClipRects[len(globals.ClipRects)-1] = nil
+	globals.ClipRects = globals.ClipRects[:len(globals.ClipRects)-1]
+	if len(globals.ClipRects) > 0 {
+		globals.Renderer.SetClipRect(globals.ClipRects[len(globals.ClipRects)-1])
+	} else {
+		globals.Renderer.SetClipRect(nil)
+	}
+
+}
+
+// drawAxisLabels draws the labels for the top and bottom values on the Y axis, and the first and last dates on the X axis.
+func (chart *ProgressChart) drawAxisLabels(plot *sdl.FRect, maxValue int, firstDate, lastDate time.Time) {
+
+	fontColor := getThemeColor(GUIFontColor)
+
+	globals.TextRenderer.QuickRenderText(strconv.Itoa(maxValue), Point{plot.X - 4, plot.Y - 8}, 0.5, fontColor, nil, AlignRight)
+	globals.TextRenderer.QuickRenderText("0", Point{plot.X - 4, plot.Y + plot.H - 8}, 0.5, fontColor, nil, AlignRight)
+
+	globals.TextRenderer.QuickRenderText(firstDate.Format("Jan 2"), Point{plot.X, plot.Y + plot.H + 2}, 0.5, fontColor, nil, AlignLeft)
+	if !DatesAreEqual(firstDate, lastDate) {
+		globals.TextRenderer.QuickRenderText(lastDate.Format("Jan 2"), Point{plot.X + plot.W, plot.Y + plot.H + 2}, 0.5, fontColor, nil, AlignRight)
+	}
+
+}
+
+func (chart *ProgressChart) drawLines(plot *sdl.FRect) {
+
+	first, last := chart.History[0], chart.History[len(chart.History)-1]
+
+	endDate := last.Date
+	projected, canProject := ProjectedCompletion(chart.History)
+
+	if chart.Mode == ProgressChartBurndown && canProject && projected.After(endDate) {
+		endDate = projected
+	}
+
+	maxValue := 1
+	for _, snapshot := range chart.History {
+		if snapshot.Total > maxValue {
+			maxValue = snapshot.Total
+		}
+	}
+
+	days := math.Max(endDate.Sub(first.Date).Hours()/24, 1)
+
+	pointAt := func(date time.Time, value int) Point {
+		return Point{
+			plot.X + plot.W*float32(date.Sub(first.Date).Hours()/24/days),
+			plot.Y + plot.H - plot.H*float32(value)/float32(maxValue),
+		}
+	}
+
+	plotLine := func(value func(snapshot *ProgressSnapshot) int, color Color) {
+		for i, snapshot := range chart.History {
+			point := pointAt(snapshot.Date, value(snapshot))
+			if i > 0 {
+				ThickLine(pointAt(chart.History[i-1].Date, value(chart.History[i-1])), point, 2, color)
+			}
+			FillRect(point.X-2, point.Y-2, 4, 4, color)
+		}
+	}
+
+	if chart.Mode == ProgressChartBurndown {
+
+		if canProject && projected.After(last.Date) {
+
+			// The projection is drawn as a dashed line from the last snapshot to the projected completion date
+			start, end := pointAt(last.Date, last.Remaining()), pointAt(projected, 0)
+			dashes := int(math.Max(float64(end.Distance(start)/8), 1))
+
+			for i := 0; i < dashes; i += 2 {
+				a := start.Add(end.Sub(start).Mult(float32(i) / float32(dashes)))
+				b := start.Add(end.Sub(start).Mult(float32(i+1) / float32(dashes)))
+				ThickLine(a, b, 2, deadlineStateColors[DeadlineStateDueToday])
+			}
+
+		}
+
+		plotLine(func(snapshot *ProgressSnapshot) int { return snapshot.Remaining() }, deadlineStateColors[DeadlineStateTimeRemains])
+
+	} else {
+
+		scopeColor := getThemeColor(GUIFontColor).Clone()
+		scopeColor[3] = 128
+
+		plotLine(func(snapshot *ProgressSnapshot) int { return snapshot.Total }, scopeColor)
+		plotLine(func(snapshot *ProgressSnapshot) int { return snapshot.Completed }, deadlineStateColors[DeadlineStateDone])
+
+	}
+
+	chart.drawAxisLabels(plot, maxValue, first.Date, endDate)
+
+}
+
+func (chart *ProgressChart) drawVelocity(plot *sdl.FRect) {
+
+	weeks, counts := WeeklyVelocity(chart.History)
+
+	maxValue := 1
+	for _, count := range counts {
+		if count > maxValue {
+			maxValue = count
+		}
+	}
+
+	fontColor := getThemeColor(GUIFontColor)
+	barWidth := plot.W / float32(len(weeks))
+
+	for i, count := range counts {
+
+		h := plot.H * float32(count) / float32(maxValue)
+		x := plot.X + barWidth*float32(i)
+
+		FillRect(x+barWidth*0.1, plot.Y+plot.H-h, barWidth*0.8, h, deadlineStateColors[DeadlineStateDone])
+
+		if count > 0 {
+			globals.TextRenderer.QuickRenderText(strconv.Itoa(count), Point{x + barWidth/2, plot.Y + plot.H - h - 16}, 0.5, fontColor, nil, AlignCenter)
+		}
+
+	}
+
+	chart.drawAxisLabels(plot, maxValue, weeks[0], weeks[len(weeks)-1])
+
+}
+
+func (chart *ProgressChart) Rectangle() *sdl.FRect {
+	return chart.Rect
+}
+
+func (chart *ProgressChart) SetRectangle(rect *sdl.FRect) {
+	chart.Rect = rect
+}
+
+func (chart *ProgressChart) Destroy() {}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func progressTestDate(month time.Month, day int) time.Time {
+	return time.Date(2020, month, day, 0, 0, 0, 0, time.Local)
+}
+
+func TestAddProgressSnapshot(t *testing.T) {
+
+	history := []*ProgressSnapshot{}
+
+	history = addProgressSnapshot(history, &ProgressSnapshot{Date: progressTestDate(6, 1), Total: 5, Completed: 1})
+	history = addProgressSnapshot(history, &ProgressSnapshot{Date: progressTestDate(6, 2), Total: 5, Completed: 2})
+
+	// A day only has one snapshot, so this replaces the last one
+	history = addProgressSnapshot(history, &ProgressSnapshot{Date: progressTestDate(6, 2).Add(time.Hour * 12), Total: 6, Completed: 3})
+
+	if len(history) != 2 {
+		t.Fatalf("len(history) = %d, want 2", len(history))
+	}
+
+	if last := history[1]; last.Total != 6 || last.Completed != 3 || last.Remaining() != 3 {
+		t.Errorf("last snapshot = %+v, want 6 total and 3 completed", *last)
+	}
+
+}
+
+func TestPageProgressHistory(t *testing.T) {
+
+	project := &Project{Properties: NewProperties()}
+	project.Properties.Get(ProjectProgressHistory).Set(`[
+		{"page": 1, "history": [{"date": "2020-06-01", "total": 3, "completed": 1}, {"date": "2020-06-04", "total": 4, "completed": 4}]},
+		{"page": 2, "history": [{"date": "2020-06-02", "total": 8, "completed": 0}, {"date": "not a date", "total": 1, "completed": 1}]}
+	]`)
+
+	tests := []struct {
+		page uint64
+		want []ProgressSnapshot
+	}{
+		{1, []ProgressSnapshot{{progressTestDate(6, 1), 3, 1}, {progressTestDate(6, 4), 4, 4}}},
+		{2, []ProgressSnapshot{{progressTestDate(6, 2), 8, 0}}},
+		{3, []ProgressSnapshot{}},
+	}
+
+	for _, test := range tests {
+
+		history := []ProgressSnapshot{}
+		for _, snapshot := range (&Page{ID: test.page, Project: project}).ProgressHistory() {
+			history = append(history, *snapshot)
+		}
+
+		if !reflect.DeepEqual(history, test.want) {
+			t.Errorf("page %d ProgressHistory() = %+v, want %+v", test.page, history, test.want)
+		}
+
+	}
+
+}
+
+func TestWeeklyVelocity(t *testing.T) {
+
+	// June 1st, 2020 is a Monday
+	history := []*ProgressSnapshot{
+		{Date: progressTestDate(6, 1), Total: 10, Completed: 0},
+		{Date: progressTestDate(6, 3), Total: 10, Completed: 3},
+		{Date: progressTestDate(6, 9), Total: 10, Completed: 5},
+		{Date: progressTestDate(6, 24), Total: 10, Completed: 4},
+	}
+
+	weeks, counts := WeeklyVelocity(history)
+
+	wantWeeks := []time.Time{progressTestDate(6, 1), progressTestDate(6, 8), progressTestDate(6, 15), progressTestDate(6, 22)}
+
+	// Weeks without snapshots complete nothing, and uncompleting Cards doesn't count as negative progress
+	wantCounts := []int{3, 2, 0, 0}
+
+	if !reflect.DeepEqual(weeks, wantWeeks) || !reflect.DeepEqual(counts, wantCounts) {
+		t.Errorf("WeeklyVelocity() = %v, %v, want %v, %v", weeks, counts, wantWeeks, wantCounts)
+	}
+
+	// Weeks start on Monday, even when the history starts on a Sunday
+	weeks, _ = WeeklyVelocity([]*ProgressSnapshot{{Date: progressTestDate(6, 7)}})
+	if len(weeks) != 1 || !weeks[0].Equal(progressTestDate(6, 1)) {
+		t.Errorf("WeeklyVelocity() from a Sunday starts on %v, want %v", weeks, progressTestDate(6, 1))
+	}
+
+	if weeks, counts := WeeklyVelocity(nil); len(weeks) != 0 || len(counts) != 0 {
+		t.Errorf("WeeklyVelocity(nil) = %v, %v, want nothing", weeks, counts)
+	}
+
+}
+
+func TestProjectedCompletion(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		history []*ProgressSnapshot
+		want    time.Time
+		ok      bool
+	}{
+		{"empty", nil, time.Time{}, false},
+		{"one day", []*ProgressSnapshot{{progressTestDate(6, 1), 10, 2}}, time.Time{}, false},
+		{"no progress", []*ProgressSnapshot{{progressTestDate(6, 1), 10, 2}, {progressTestDate(6, 5), 12, 2}}, time.Time{}, false},
+		{"done", []*ProgressSnapshot{{progressTestDate(6, 1), 10, 2}, {progressTestDate(6, 5), 10, 10}}, progressTestDate(6, 5), true},
+		{"half a card a day", []*ProgressSnapshot{{progressTestDate(6, 1), 10, 0}, {progressTestDate(6, 11), 10, 5}}, progressTestDate(6, 21), true},
+		{"rounds up", []*ProgressSnapshot{{progressTestDate(6, 1), 10, 0}, {progressTestDate(6, 4), 10, 2}}, progressTestDate(6, 16), true},
+	}
+
+	for _, test := range tests {
+		if got, ok := ProjectedCompletion(test.history); !got.Equal(test.want) || ok != test.ok {
+			t.Errorf("%s: ProjectedCompletion() = %v, %v, want %v, %v", test.name, got, ok, test.want, test.ok)
+		}
+	}
+
+}
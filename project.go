@@ -48,7 +48,10 @@ const (
 
 	// Per-Project Properties
 
-	ProjectCacheDirectory = "CacheDirectory"
+	ProjectCacheDirectory  = "CacheDirectory"
+	ProjectSavedSearches   = "SavedSearches"
+	ProjectProgressHistory = "ProgressHistory"
+	ProjectDictionary      = "Dictionary"
 )
 
 type Project struct {
@@ -63,6 +66,7 @@ type Project struct {
 	LastCardType   string
 	Modified       bool
 	justModified   bool
+	tags           []string // The tags used in the Project, cached until the Project changes
 	HasOrphanPages bool
 	LinkingCard    *Card
 
@@ -72,6 +76,12 @@ type Project struct {
 	LastBackup time.Time
 
 	Properties *Properties
+
+	Reminders         []*Reminder
+	RemindersChanged  bool
+	lastReminderCheck time.Time
+
+	HighlightCriticalPath bool
 }
 
 func NewProject() *Project {
@@ -132,6 +142,127 @@ func (project *Project) PageIndex(page *Page) int {
 	return -1
 }
 
+// Tags returns a sorted list of every tag used on Cards across all valid Pages in the Project. The list is cached until the Project changes,
+// so it shouldn't be modified.
+func (project *Project) Tags() []string {
+
+	if project.tags != nil {
+		return project.tags
+	}
+
+	tags := []string{}
+	existing := map[string]bool{}
+
+	for _, page := range project.Pages {
+
+		if !page.Valid() {
+			continue
+		}
+
+		for _, card := range page.Cards {
+
+			for _, tag := range card.Tags() {
+
+				if !existing[strings.ToLower(tag)] {
+					existing[strings.ToLower(tag)] = true
+					tags = append(tags, tag)
+				}
+
+			}
+
+		}
+
+	}
+
+	sort.Slice(tags, func(i, j int) bool { return strings.ToLower(tags[i]) < strings.ToLower(tags[j]) })
+
+	project.tags = tags
+
+	return tags
+
+}
+
+// SavedSearches returns the searches saved to the Project as smart views.
+func (project *Project) SavedSearches() []*SavedSearch {
+
+	searches := []*SavedSearch{}
+
+	prop := project.Properties.GetIfExists(ProjectSavedSearches)
+
+	if prop == nil || !prop.IsString() {
+		return searches
+	}
+
+	for _, data := range gjson.Parse(prop.AsString()).Array() {
+		searches = append(searches, &SavedSearch{
+			Name:          data.Get("name").String(),
+			Query:         data.Get("query").String(),
+			Tags:          data.Get("tags").String(),
+			Regex:         data.Get("regex").Bool(),
+			CaseSensitive: data.Get("caseSensitive").Bool(),
+		})
+	}
+
+	return searches
+
+}
+
+// SetSavedSearches sets the searches saved to the Project as smart views.
+func (project *Project) SetSavedSearches(searches []*SavedSearch) {
+
+	data := "[]"
+
+	for _, search := range searches {
+		searchData, _ := sjson.Set("{}", "name", search.Name)
+		searchData, _ = sjson.Set(searchData, "query", search.Query)
+		searchData, _ = sjson.Set(searchData, "tags", search.Tags)
+		searchData, _ = sjson.Set(searchData, "regex", search.Regex)
+		searchData, _ = sjson.Set(searchData, "caseSensitive", search.CaseSensitive)
+		data, _ = sjson.SetRaw(data, "-1", searchData)
+	}
+
+	project.Properties.Get(ProjectSavedSearches).Set(data)
+
+	project.SetModifiedState()
+
+}
+
+// DictionaryWords returns the words added to the Project's dictionary, which the spell checker considers to be spelled correctly.
+func (project *Project) DictionaryWords() []string {
+
+	words := []string{}
+
+	prop := project.Properties.GetIfExists(ProjectDictionary)
+
+	if prop == nil || !prop.IsString() {
+		return words
+	}
+
+	for _, word := range gjson.Parse(prop.AsString()).Array() {
+		words = append(words, word.String())
+	}
+
+	return words
+
+}
+
+// AddToDictionary adds the word to the Project's dictionary.
+func (project *Project) AddToDictionary(word string) {
+
+	data := "[]"
+
+	if prop := project.Properties.GetIfExists(ProjectDictionary); prop != nil && prop.IsString() {
+		data = prop.AsString()
+	}
+
+	data, _ = sjson.Set(data, "-1", word)
+
+	project.Properties.Get(ProjectDictionary).Set(data)
+
+	project.SetModifiedState()
+
+}
+
 func (project *Project) CreateGridTexture() {
 
 	guiTex := globals.Resources.Get(LocalRelativePath("assets/gui.png")).AsImage()
@@ -255,6 +386,8 @@ func (project *Project) Update() {
 		}
 	}
 
+	project.CheckReminders()
+
 	globals.Mouse.HiddenPosition = false
 
 	project.GlobalShortcuts()
@@ -263,6 +396,11 @@ func (project *Project) Update() {
 
 	project.UndoHistory.Update()
 
+	// Tags are suggested in several menus, so they're only gathered again once the project's changed
+	if project.Loading || project.justModified {
+		project.tags = nil
+	}
+
 	// This should only be true for a total of essentially 1 or 2 frames, immediately after loading
 	project.Loading = false
 
@@ -377,6 +515,8 @@ func (project *Project) Save() {
 	saveData, _ = sjson.Set(saveData, "zoom", project.Camera.TargetZoom)
 	saveData, _ = sjson.Set(saveData, "currentPage", project.CurrentPage.ID)
 
+	project.RecordProgress()
+
 	if cache := project.Properties.Get(ProjectCacheDirectory); cache.AsString() != "" {
 		cache.Set(project.PathToRelative(cache.AsString(), true))
 	}
@@ -446,6 +586,10 @@ func (project *Project) Save() {
 				converted = append(converted, convertedFilepath{Original: run.AsString(), PropName: "run", Card: card})
 				run.Set(project.PathToRelative(run.AsString(), false))
 			}
+			if tableFile := card.Properties.GetIfExists("table file"); tableFile != nil && FileExists(tableFile.AsString()) {
+				converted = append(converted, convertedFilepath{Original: tableFile.AsString(), PropName: "table file", Card: card})
+				tableFile.Set(project.PathToRelative(tableFile.AsString(), false))
+			}
 		}
 
 		pageData += page.Serialize()
@@ -688,8 +832,7 @@ func OpenProjectFrom(filename string) {
 					// cardType = ContentTypeWhiteboard
 					continue
 				case 9:
-					// cardType = ContentTypeTable
-					continue
+					cardType = ContentTypeTable
 				}
 
 				card := newProject.Pages[boardIndex].CreateNewCard(cardType)
@@ -710,6 +853,10 @@ func OpenProjectFrom(filename string) {
 					card.Properties.Get("checked").Set(task.Get(`Checkbox\.Checked`).Bool())
 				}
 
+				if card.ContentType == ContentTypeTable && task.Get("TableData").Exists() {
+					card.Properties.Get("table").Set(ImportV07TableData(task.Get("TableData")).Serialize())
+				}
+
 				if card.Properties.Has("current") {
 					card.Properties.Get("current").Set(task.Get(`Progression\.Current`).Float())
 					card.Properties.Get("maximum").Set(task.Get(`Progression\.Max`).Float())
@@ -1304,6 +1451,11 @@ func (project *Project) GlobalShortcuts() {
 			newCard = project.CurrentPage.CreateNewCard(ContentTypeLink)
 			kb.Shortcuts[KBNewLinkCard].ConsumeKeys()
 
+		} else if kb.Pressed(KBNewTableCard) {
+
+			newCard = project.CurrentPage.CreateNewCard(ContentTypeTable)
+			kb.Shortcuts[KBNewTableCard].ConsumeKeys()
+
 		}
 
 		if newCard != nil {
@@ -1385,6 +1537,68 @@ func (project *Project) GlobalShortcuts() {
 			project.Camera.FocusOn(true, project.CurrentPage.Selection.AsSlice()...)
 		}
 
+		if kb.Pressed(KBCycleCardPriority) {
+
+			selection := project.CurrentPage.Selection.AsSlice()
+
+			// All selected Cards move to the priority after the highest one currently set in the selection
+			priority := PriorityNone
+			for _, card := range selection {
+				if card.Priority() > priority {
+					priority = card.Priority()
+				}
+			}
+
+			priority = (priority + 1) % len(priorityNames)
+
+			completableCount := 0
+			for _, card := range selection {
+				if card.SetPriority(priority) {
+					completableCount++
+				}
+			}
+
+			if completableCount > 0 {
+				globals.EventLog.Log("Priority set to %s on %d complete-able card(s).", false, priorityNames[priority], completableCount)
+			}
+
+			kb.Shortcuts[KBCycleCardPriority].ConsumeKeys()
+
+		}
+
+		if kb.Pressed(KBToggleTimeTracking) {
+
+			selection := project.CurrentPage.Selection.AsSlice()
+
+			// If time is being tracked on any of the selected Cards, tracking stops on all of them; otherwise, it starts on all of them
+			tracking := false
+			for _, card := range selection {
+				if card.IsTracking() {
+					tracking = true
+					break
+				}
+			}
+
+			for _, card := range selection {
+				if tracking {
+					card.StopTracking()
+				} else {
+					card.StartTracking()
+				}
+			}
+
+			if len(selection) > 0 {
+				if tracking {
+					globals.EventLog.Log("Stopped tracking time on %d card(s).", false, len(selection))
+				} else {
+					globals.EventLog.Log("Started tracking time on %d card(s).", false, len(selection))
+				}
+			}
+
+			kb.Shortcuts[KBToggleTimeTracking].ConsumeKeys()
+
+		}
+
 		if kb.Pressed(KBSubpageClose) {
 			project.GoUpFromSubpage()
 		}
@@ -0,0 +1,674 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// A Query is a search made up of space-separated terms, all of which must match a Card for the Card to be found. Terms can be:
+//
+//	text             Card description or filepath contains the text ("quoted text" can contain spaces)
+//	/regex/          Card description or filepath matches the regular expression
+//	type:timer       Card is of the given content type
+//	is:done          Card is completed (also is:open, is:overdue, is:completable, is:collapsed)
+//	due<2026-11-01   Card's deadline compares to the given date (<, <=, >, >=, =, or :; "today", "tomorrow" and "yesterday" also work,
+//	                 as do offsets from today like "+7d", "-1w", "+1m" or "+1y", and "this-week", "next-week", "this-month", etc.)
+//	overdue          Card's deadline has passed
+//	page:name        Card is on a Page whose path contains the name ("page:current" matches the current Page)
+//	tag:name         Card has the given tag
+//	priority>=high   Card's priority compares to the given level
+//	has:deadline     Card has the given property set (also has:tags, has:priority, has:links)
+//	name:value       Card's property of the given name contains the value (=, <, <=, >, and >= compare exactly or numerically);
+//	                 for Cards without the property, the whole term is searched for as text instead (so "C:\path" still works)
+//
+// Any term can be prefixed with "-" to negate it.
+type Query struct {
+	Text          string
+	CaseSensitive bool
+	Terms         []*QueryTerm
+}
+
+// QueryTerm is a single condition of a Query. Pattern is set for plain text and regular expression terms, and is what gets replaced when
+// using the Query to replace text; Literal indicates the replacement text should be used as-is, rather than expanding regular expression
+// submatches (like "$1").
+type QueryTerm struct {
+	Negate  bool
+	Match   func(card *Card) bool
+	Pattern *regexp.Regexp
+	Literal bool
+}
+
+var queryComparison = regexp.MustCompile(`^([\w\- ]+?)(<=|>=|<|>|=|:)(.+)$`)
+
+// ParseQuery parses the given text into a Query, returning an error if any term in the text is invalid.
+func ParseQuery(text string, caseSensitive bool) (*Query, error) {
+
+	query := &Query{
+		Text:          text,
+		CaseSensitive: caseSensitive,
+		Terms:         []*QueryTerm{},
+	}
+
+	for _, token := range tokenizeQuery(text) {
+
+		term, err := parseQueryTerm(token.Text, token.Quoted, caseSensitive)
+		if err != nil {
+			return nil, err
+		}
+
+		query.Terms = append(query.Terms, term)
+
+	}
+
+	return query, nil
+
+}
+
+// NewRegexQuery creates a Query that treats the entire given text as a single regular expression, rather than parsing it as search terms.
+func NewRegexQuery(text string, caseSensitive bool) (*Query, error) {
+
+	query := &Query{
+		Text:          text,
+		CaseSensitive: caseSensitive,
+		Terms:         []*QueryTerm{},
+	}
+
+	if text != "" {
+
+		term, err := regexQueryTerm(text, caseSensitive)
+		if err != nil {
+			return nil, err
+		}
+
+		query.Terms = append(query.Terms, term)
+
+	}
+
+	return query, nil
+
+}
+
+// Empty returns if the Query has no terms.
+func (query *Query) Empty() bool {
+	return len(query.Terms) == 0
+}
+
+// Matches returns if the Card satisfies all terms of the Query.
+func (query *Query) Matches(card *Card) bool {
+
+	for _, term := range query.Terms {
+		if term.Match(card) == term.Negate {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+// Replace replaces all text in the given string matched by the Query's (non-negated) plain text and regular expression terms with the replacement string.
+func (query *Query) Replace(text, replacement string) string {
+
+	for _, term := range query.Terms {
+
+		if term.Pattern == nil || term.Negate {
+			continue
+		}
+
+		if term.Literal {
+			text = term.Pattern.ReplaceAllLiteralString(text, replacement)
+		} else {
+			text = term.Pattern.ReplaceAllString(text, replacement)
+		}
+
+	}
+
+	return text
+
+}
+
+// CanReplace returns if the Query has any terms that can be used to replace text.
+func (query *Query) CanReplace() bool {
+
+	for _, term := range query.Terms {
+		if term.Pattern != nil && !term.Negate {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+// ReplaceCardText replaces the text matched by the Query in the Card's description with the replacement string, returning if any text was replaced.
+func ReplaceCardText(card *Card, query *Query, replacement string) bool {
+
+	prop := card.Properties.GetIfExists("description")
+
+	if prop == nil || !prop.IsString() {
+		return false
+	}
+
+	text := query.Replace(prop.AsString(), replacement)
+
+	if text == prop.AsString() {
+		return false
+	}
+
+	prop.Set(text)
+
+	// Capture the undo state immediately, as Cards on other Pages don't handle their undos until they're drawn
+	card.HandleUndos()
+
+	return true
+
+}
+
+// replacePreview returns a snippet of the Card's description around the first change that replacing text would make, or an empty string if nothing would change.
+func replacePreview(card *Card, query *Query, replacement string) string {
+
+	prop := card.Properties.GetIfExists("description")
+
+	if query == nil || prop == nil || !prop.IsString() {
+		return ""
+	}
+
+	original := []rune(prop.AsString())
+	replaced := []rune(query.Replace(prop.AsString(), replacement))
+
+	if string(original) == string(replaced) {
+		return ""
+	}
+
+	start := 0
+	for start < len(original) && start < len(replaced) && original[start] == replaced[start] {
+		start++
+	}
+
+	end := start + 32
+	start -= 12
+
+	prefix := "..."
+	if start <= 0 {
+		start = 0
+		prefix = ""
+	}
+
+	suffix := "..."
+	if end >= len(replaced) {
+		end = len(replaced)
+		suffix = ""
+	}
+
+	return prefix + strings.ReplaceAll(string(replaced[start:end]), "\n", " ") + suffix
+
+}
+
+// SavedSearch is a named search saved to a Project (a "smart view"), which can be opened to list the Cards that match it.
+type SavedSearch struct {
+	Name          string
+	Query         string
+	Tags          string
+	Regex         bool
+	CaseSensitive bool
+}
+
+// Compile parses the SavedSearch's query text into a Query.
+func (search *SavedSearch) Compile() (*Query, error) {
+	if search.Regex {
+		return NewRegexQuery(search.Query, search.CaseSensitive)
+	}
+	return ParseQuery(search.Query, search.CaseSensitive)
+}
+
+// Results returns the Cards on the Project's valid Pages that match the SavedSearch's query and tags.
+func (search *SavedSearch) Results(project *Project) ([]*Card, error) {
+
+	query, err := search.Compile()
+	if err != nil {
+		return nil, err
+	}
+
+	tags := ParseTags(search.Tags)
+
+	results := []*Card{}
+
+	for _, page := range project.Pages {
+
+		if !page.Valid() {
+			continue
+		}
+
+		for _, card := range page.Cards {
+			if card.HasTags(tags...) && query.Matches(card) {
+				results = append(results, card)
+			}
+		}
+
+	}
+
+	return results, nil
+
+}
+
+type queryToken struct {
+	Text   string
+	Quoted bool
+}
+
+func tokenizeQuery(text string) []queryToken {
+
+	tokens := []queryToken{}
+	current := []rune{}
+	quoted := false
+	inQuotes := false
+
+	finish := func() {
+		if len(current) > 0 || quoted {
+			tokens = append(tokens, queryToken{Text: string(current), Quoted: quoted})
+		}
+		current = []rune{}
+		quoted = false
+	}
+
+	for _, r := range text {
+
+		if r == '"' {
+			inQuotes = !inQuotes
+			quoted = true
+		} else if unicode.IsSpace(r) && !inQuotes {
+			finish()
+		} else {
+			current = append(current, r)
+		}
+
+	}
+
+	finish()
+
+	return tokens
+
+}
+
+func parseQueryTerm(token string, quoted bool, caseSensitive bool) (*QueryTerm, error) {
+
+	term := &QueryTerm{}
+
+	if len(token) > 1 && token[0] == '-' {
+		term.Negate = true
+		token = token[1:]
+	}
+
+	// Quoted text is always searched for as-is
+	if quoted {
+		return queryTextTerm(term, token, caseSensitive), nil
+	}
+
+	if len(token) > 2 && token[0] == '/' && token[len(token)-1] == '/' {
+
+		regexTerm, err := regexQueryTerm(token[1:len(token)-1], caseSensitive)
+		if err != nil {
+			return nil, err
+		}
+
+		regexTerm.Negate = term.Negate
+
+		return regexTerm, nil
+
+	}
+
+	if strings.EqualFold(token, "overdue") {
+		term.Match = func(card *Card) bool { return card.Completable() && card.DeadlineState() == DeadlineStateOverdue }
+		return term, nil
+	}
+
+	parts := queryComparison.FindStringSubmatch(token)
+
+	if parts == nil {
+		return queryTextTerm(term, token, caseSensitive), nil
+	}
+
+	key := strings.ToLower(parts[1])
+	op := parts[2]
+	value := parts[3]
+	lowerValue := strings.ToLower(value)
+
+	switch key {
+
+	case "type":
+
+		contentType := normalizeQueryName(value)
+
+		term.Match = func(card *Card) bool {
+			return strings.HasPrefix(normalizeQueryName(card.ContentType), contentType)
+		}
+
+	case "is":
+
+		switch lowerValue {
+		case "done", "complete", "completed":
+			term.Match = func(card *Card) bool { return card.Completable() && card.Completed() }
+		case "open", "incomplete", "todo":
+			term.Match = func(card *Card) bool { return card.Completable() && !card.Completed() }
+		case "overdue":
+			term.Match = func(card *Card) bool { return card.Completable() && card.DeadlineState() == DeadlineStateOverdue }
+		case "completable":
+			term.Match = func(card *Card) bool { return card.Completable() }
+		case "collapsed":
+			term.Match = func(card *Card) bool { return card.Collapsed != CollapsedNone }
+		default:
+			return nil, fmt.Errorf("unknown state %s", token)
+		}
+
+	case "due", "deadline":
+
+		start, end, err := parseQueryDate(lowerValue)
+		if err != nil {
+			return nil, err
+		}
+
+		term.Match = func(card *Card) bool {
+
+			// Deadlines are compared by date, regardless of any time of day they have
+			deadline, hasDeadline := card.Deadline()
+			if !hasDeadline {
+				return false
+			}
+
+			// Dates covering several days (like "this-week") match any day within them, and compare against their first or last day
+			switch op {
+			case "<", ">=":
+				return compareQueryValues(op, float64(deadline.Unix()), float64(start.Unix()))
+			case ">", "<=":
+				return compareQueryValues(op, float64(deadline.Unix()), float64(end.Unix()))
+			}
+
+			return !deadline.Before(start) && !deadline.After(end)
+
+		}
+
+	case "page":
+
+		term.Match = func(card *Card) bool {
+			if lowerValue == "current" {
+				return card.Page.IsCurrent()
+			}
+			return strings.Contains(strings.ToLower(card.Page.Path()), lowerValue)
+		}
+
+	case "tag", "tags":
+
+		term.Match = func(card *Card) bool { return card.HasTags(ParseTags(value)...) }
+
+	case "priority":
+
+		priority := -1
+		for i, name := range priorityNames {
+			if strings.EqualFold(name, value) {
+				priority = i
+			}
+		}
+
+		if priority < 0 {
+			p, err := strconv.Atoi(value)
+			if err != nil || p < PriorityNone || p > PriorityCritical {
+				return nil, fmt.Errorf("unknown priority %s", value)
+			}
+			priority = p
+		}
+
+		term.Match = func(card *Card) bool {
+			return card.Completable() && compareQueryValues(op, float64(card.Priority()), float64(priority))
+		}
+
+	case "has":
+
+		switch lowerValue {
+		case "tags", "tag":
+			term.Match = func(card *Card) bool { return len(card.Tags()) > 0 }
+		case "priority":
+			term.Match = func(card *Card) bool { return card.Priority() != PriorityNone }
+		case "links", "link":
+			term.Match = func(card *Card) bool { return len(card.Links) > 0 }
+		case "deadline":
+			term.Match = func(card *Card) bool { return card.Completable() && card.Properties.Has("deadline") }
+		default:
+			term.Match = func(card *Card) bool { return card.Properties.Has(value) }
+		}
+
+	default:
+
+		propName := parts[1]
+
+		// If the Card doesn't have the property, the token's most likely just text that happens to hold one of the operators (like a path
+		// or URL), so it's searched for as-is
+		textTerm := queryTextTerm(&QueryTerm{}, token, caseSensitive)
+		term.Pattern = textTerm.Pattern
+		term.Literal = true
+
+		term.Match = func(card *Card) bool {
+
+			prop := card.Properties.GetIfExists(propName)
+
+			if prop == nil {
+				return textTerm.Match(card)
+			}
+
+			propString := queryPropertyString(prop)
+
+			if op == ":" {
+				if caseSensitive {
+					return strings.Contains(propString, value)
+				}
+				return strings.Contains(strings.ToLower(propString), lowerValue)
+			}
+
+			if a, err := strconv.ParseFloat(propString, 64); err == nil {
+				if b, err := strconv.ParseFloat(value, 64); err == nil {
+					return compareQueryValues(op, a, b)
+				}
+			}
+
+			if !caseSensitive {
+				propString = strings.ToLower(propString)
+				value = lowerValue
+			}
+
+			return compareQueryValues(op, float64(strings.Compare(propString, value)), 0)
+
+		}
+
+	}
+
+	return term, nil
+
+}
+
+func queryTextTerm(term *QueryTerm, text string, caseSensitive bool) *QueryTerm {
+
+	expression := regexp.QuoteMeta(text)
+	if !caseSensitive {
+		expression = "(?i)" + expression
+	}
+
+	term.Pattern = regexp.MustCompile(expression)
+	term.Literal = true
+
+	if !caseSensitive {
+		text = strings.ToLower(text)
+	}
+
+	term.Match = func(card *Card) bool {
+
+		for _, propString := range querySearchableText(card) {
+
+			if !caseSensitive {
+				propString = strings.ToLower(propString)
+			}
+
+			if strings.Contains(propString, text) {
+				return true
+			}
+
+		}
+
+		return false
+
+	}
+
+	return term
+
+}
+
+func regexQueryTerm(expression string, caseSensitive bool) (*QueryTerm, error) {
+
+	flags := ""
+	if !caseSensitive {
+		flags = "(?i)"
+	}
+
+	regex, err := regexp.Compile(flags + expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression /%s/", expression)
+	}
+
+	term := &QueryTerm{Pattern: regex}
+
+	term.Match = func(card *Card) bool {
+		for _, text := range querySearchableText(card) {
+			if regex.MatchString(text) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return term, nil
+
+}
+
+// querySearchableText returns the text of a Card that should be searched through for plain text or regular expression searches.
+func querySearchableText(card *Card) []string {
+
+	texts := []string{}
+
+	for _, propName := range []string{"description", "filepath"} {
+		if prop := card.Properties.GetIfExists(propName); prop != nil && prop.IsString() {
+			texts = append(texts, prop.AsString())
+		}
+	}
+
+	return texts
+
+}
+
+func queryPropertyString(prop *Property) string {
+
+	if prop.IsString() {
+		return prop.AsString()
+	} else if prop.IsNumber() {
+		return strconv.FormatFloat(prop.AsFloat(), 'f', -1, 64)
+	} else if prop.IsBool() {
+		return strconv.FormatBool(prop.AsBool())
+	}
+
+	return ""
+
+}
+
+func normalizeQueryName(name string) string {
+	name = strings.ToLower(name)
+	name = strings.ReplaceAll(name, "-", "")
+	name = strings.ReplaceAll(name, " ", "")
+	return name
+}
+
+var queryDateOffset = regexp.MustCompile(`^([+-])(\d+)([dwmy])$`)
+
+// parseQueryDate parses a date in a query, returning the first and last days it covers; weeks and months cover several days. Besides
+// YYYY-MM-DD dates, dates can be relative to today, so saved searches using them stay up to date.
+func parseQueryDate(value string) (time.Time, time.Time, error) {
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	// Weeks start on Sunday, as they do in the calendar
+	week := today.AddDate(0, 0, -int(today.Weekday()))
+	month := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	switch value {
+	case "today":
+		return today, today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), today.AddDate(0, 0, -1), nil
+	case "this-week":
+		return week, week.AddDate(0, 0, 6), nil
+	case "next-week":
+		return week.AddDate(0, 0, 7), week.AddDate(0, 0, 13), nil
+	case "last-week":
+		return week.AddDate(0, 0, -7), week.AddDate(0, 0, -1), nil
+	case "this-month":
+		return month, month.AddDate(0, 1, -1), nil
+	case "next-month":
+		return month.AddDate(0, 1, 0), month.AddDate(0, 2, -1), nil
+	case "last-month":
+		return month.AddDate(0, -1, 0), month.AddDate(0, 0, -1), nil
+	}
+
+	// Offsets from today, like "+7d" or "-1w"
+	if match := queryDateOffset.FindStringSubmatch(value); match != nil {
+
+		amount, _ := strconv.Atoi(match[2])
+		if match[1] == "-" {
+			amount = -amount
+		}
+
+		date := today
+
+		switch match[3] {
+		case "d":
+			date = today.AddDate(0, 0, amount)
+		case "w":
+			date = today.AddDate(0, 0, amount*7)
+		case "m":
+			date = today.AddDate(0, amount, 0)
+		case "y":
+			date = today.AddDate(amount, 0, 0)
+		}
+
+		return date, date, nil
+
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", value, now.Location())
+	if err != nil {
+		return date, date, errors.New("dates should be formatted as YYYY-MM-DD, be relative to today (like +7d or -1w), or be one of today, tomorrow, yesterday, this-week, next-week, last-week, this-month, next-month, or last-month")
+	}
+
+	return date, date, nil
+
+}
+
+func compareQueryValues(op string, a, b float64) bool {
+
+	switch op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+
+	return a == b
+
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// newQueryTestCard creates a bare Card with the given content type and properties; it has no Contents or Page, so it only supports
+// queries that look at its properties.
+func newQueryTestCard(contentType string, properties map[string]interface{}) *Card {
+
+	card := &Card{ContentType: contentType, Properties: NewProperties()}
+
+	for name, value := range properties {
+		card.Properties.Get(name).Set(value)
+	}
+
+	return card
+
+}
+
+func TestParseQueryTerms(t *testing.T) {
+
+	tests := []struct {
+		text    string
+		terms   int
+		negated []bool
+	}{
+		{"", 0, nil},
+		{"   ", 0, nil},
+		{"groceries", 1, []bool{false}},
+		{"groceries -milk", 2, []bool{false, true}},
+		{`"buy milk" eggs`, 2, []bool{false, false}},
+		{`-"buy milk"`, 1, []bool{true}},
+		{"tag:home priority>=high due<2026-11-01", 3, []bool{false, false, false}},
+		{"/^buy.*$/ -/milk/", 2, []bool{false, true}},
+		{"-", 1, []bool{false}},
+	}
+
+	for _, test := range tests {
+
+		query, err := ParseQuery(test.text, false)
+		if err != nil {
+			t.Errorf("ParseQuery(%q) returned error: %s", test.text, err)
+			continue
+		}
+
+		if len(query.Terms) != test.terms {
+			t.Errorf("ParseQuery(%q) has %d terms, want %d", test.text, len(query.Terms), test.terms)
+			continue
+		}
+
+		for i, term := range query.Terms {
+			if term.Negate != test.negated[i] {
+				t.Errorf("ParseQuery(%q) term %d negated = %v, want %v", test.text, i, term.Negate, test.negated[i])
+			}
+		}
+
+	}
+
+}
+
+func TestParseQueryErrors(t *testing.T) {
+
+	for _, text := range []string{
+		"/[/",
+		"is:bogus",
+		"due<someday",
+		"due:2026-13-01",
+		"priority:extreme",
+		"priority>9",
+	} {
+		if _, err := ParseQuery(text, false); err == nil {
+			t.Errorf("ParseQuery(%q) should have returned an error", text)
+		}
+	}
+
+}
+
+func TestQueryMatches(t *testing.T) {
+
+	card := newQueryTestCard(ContentTypeCheckbox, map[string]interface{}{
+		"description": "Buy milk and Eggs from C:\\groceries\\list.txt, see http://example.com?a=b",
+		"tags":        "Home, errands",
+		"priority":    PriorityHigh,
+		"estimate":    3,
+	})
+
+	tests := []struct {
+		query         string
+		caseSensitive bool
+		want          bool
+	}{
+		{"milk", false, true},
+		{"MILK", false, true},
+		{"MILK", true, false},
+		{"eggs", true, false},
+		{"milk eggs", false, true},
+		{"milk -eggs", false, false},
+		{"bread", false, false},
+		{`"milk and eggs"`, false, true},
+		{`"milk eggs"`, false, false},
+		{"/m.lk/", false, true},
+		{"/^milk/", false, false},
+		{"type:check", false, true},
+		{"type:note", false, false},
+		{"tag:home", false, true},
+		{"tag:work", false, false},
+		{"-tag:work", false, true},
+		{"has:tags", false, true},
+		{"has:deadline", false, false},
+		{"has:estimate", false, true},
+		{"priority>=medium", false, true},
+		{"priority>high", false, false},
+		{"priority:3", false, true},
+		{"estimate>2", false, true},
+		{"estimate<=2", false, false},
+		{"estimate=3", false, true},
+		{"estimate:3", false, true},
+	}
+
+	for _, test := range tests {
+
+		query, err := ParseQuery(test.query, test.caseSensitive)
+		if err != nil {
+			t.Errorf("ParseQuery(%q) returned error: %s", test.query, err)
+			continue
+		}
+
+		if got := query.Matches(card); got != test.want {
+			t.Errorf("ParseQuery(%q, %v).Matches() = %v, want %v", test.query, test.caseSensitive, got, test.want)
+		}
+
+	}
+
+}
+
+func TestQueryPropertyTermTextFallback(t *testing.T) {
+
+	card := newQueryTestCard(ContentTypeNote, map[string]interface{}{
+		"description": "Notes are in C:\\work\\notes and at http://example.com/?page=2; x=y",
+	})
+
+	// None of these name a property the Card has, so they're searched for as text
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"C:\\work", true},
+		{"c:\\WORK\\notes", true},
+		{"http://example.com/?page=2", true},
+		{"x=y", true},
+		{"x=z", false},
+		{"D:\\work", false},
+		{"-x=y", false},
+	}
+
+	for _, test := range tests {
+
+		query, err := ParseQuery(test.query, false)
+		if err != nil {
+			t.Errorf("ParseQuery(%q) returned error: %s", test.query, err)
+			continue
+		}
+
+		if got := query.Matches(card); got != test.want {
+			t.Errorf("ParseQuery(%q).Matches() = %v, want %v", test.query, got, test.want)
+		}
+
+	}
+
+	// The text those terms match can be replaced, too
+	query, _ := ParseQuery("C:\\work", false)
+
+	if !query.CanReplace() {
+		t.Fatalf("ParseQuery(%q).CanReplace() = false, want true", "C:\\work")
+	}
+
+	if got, want := query.Replace("Open c:\\work\\notes", "$1D:\\home"), "Open $1D:\\home\\notes"; got != want {
+		t.Errorf("Replace() = %q, want %q", got, want)
+	}
+
+}
+
+func TestQueryReplace(t *testing.T) {
+
+	tests := []struct {
+		query       string
+		text        string
+		replacement string
+		want        string
+	}{
+		{"milk", "Buy Milk and milk", "bread", "Buy bread and bread"},
+		{"-milk eggs", "milk and eggs", "ham", "milk and ham"},
+		{"a.b", "a.b and axb", "c", "c and axb"},
+		{"/(\\w+)@example/", "mail bob@example.com", "$1@test", "mail bob@test.com"},
+		{"tag:home", "home", "away", "home"},
+	}
+
+	for _, test := range tests {
+
+		query, err := ParseQuery(test.query, false)
+		if err != nil {
+			t.Errorf("ParseQuery(%q) returned error: %s", test.query, err)
+			continue
+		}
+
+		if got := query.Replace(test.text, test.replacement); got != test.want {
+			t.Errorf("ParseQuery(%q).Replace(%q, %q) = %q, want %q", test.query, test.text, test.replacement, got, test.want)
+		}
+
+	}
+
+}
+
+func TestParseQueryDate(t *testing.T) {
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	week := today.AddDate(0, 0, -int(today.Weekday()))
+	month := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	tests := []struct {
+		value      string
+		start, end time.Time
+	}{
+		{"2026-11-01", time.Date(2026, 11, 1, 0, 0, 0, 0, now.Location()), time.Date(2026, 11, 1, 0, 0, 0, 0, now.Location())},
+		{"today", today, today},
+		{"tomorrow", today.AddDate(0, 0, 1), today.AddDate(0, 0, 1)},
+		{"yesterday", today.AddDate(0, 0, -1), today.AddDate(0, 0, -1)},
+		{"this-week", week, week.AddDate(0, 0, 6)},
+		{"next-week", week.AddDate(0, 0, 7), week.AddDate(0, 0, 13)},
+		{"last-week", week.AddDate(0, 0, -7), week.AddDate(0, 0, -1)},
+		{"this-month", month, month.AddDate(0, 1, -1)},
+		{"next-month", month.AddDate(0, 1, 0), month.AddDate(0, 2, -1)},
+		{"last-month", month.AddDate(0, -1, 0), month.AddDate(0, 0, -1)},
+		{"+0d", today, today},
+		{"+7d", today.AddDate(0, 0, 7), today.AddDate(0, 0, 7)},
+		{"-3d", today.AddDate(0, 0, -3), today.AddDate(0, 0, -3)},
+		{"+2w", today.AddDate(0, 0, 14), today.AddDate(0, 0, 14)},
+		{"-1w", today.AddDate(0, 0, -7), today.AddDate(0, 0, -7)},
+		{"+1m", today.AddDate(0, 1, 0), today.AddDate(0, 1, 0)},
+		{"-1y", today.AddDate(-1, 0, 0), today.AddDate(-1, 0, 0)},
+	}
+
+	for _, test := range tests {
+
+		start, end, err := parseQueryDate(test.value)
+
+		if err != nil {
+			t.Errorf("parseQueryDate(%q) returned error: %s", test.value, err)
+		} else if !start.Equal(test.start) || !end.Equal(test.end) {
+			t.Errorf("parseQueryDate(%q) = %s to %s, want %s to %s", test.value, start, end, test.start, test.end)
+		}
+
+	}
+
+	for _, value := range []string{"", "someday", "7d", "+7", "+d", "+7x", "2026-02-30", "11/01/2026"} {
+		if _, _, err := parseQueryDate(value); err == nil {
+			t.Errorf("parseQueryDate(%q) should have returned an error", value)
+		}
+	}
+
+}
+
+func TestQueryDueDates(t *testing.T) {
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	lastDayOfWeek := today.AddDate(0, 0, 6-int(today.Weekday()))
+
+	dueToday := newQueryTestCard(ContentTypeCheckbox, map[string]interface{}{"deadline": FormatDeadline(today, false)})
+	dueTodayAtTime := newQueryTestCard(ContentTypeCheckbox, map[string]interface{}{"deadline": FormatDeadline(today.Add(time.Hour*23), true)})
+	dueEndOfWeek := newQueryTestCard(ContentTypeCheckbox, map[string]interface{}{"deadline": FormatDeadline(lastDayOfWeek, false)})
+	noDeadline := newQueryTestCard(ContentTypeCheckbox, nil)
+
+	tests := []struct {
+		query string
+		card  *Card
+		want  bool
+	}{
+		{"due:today", dueToday, true},
+		{"due=today", dueToday, true},
+		{"due<today", dueToday, false},
+		{"due<=today", dueToday, true},
+		{"due>today", dueToday, false},
+		{"due>=today", dueToday, true},
+		{"due>yesterday", dueToday, true},
+		{"due<tomorrow", dueToday, true},
+		{"due:+0d", dueToday, true},
+		{"due<+1d", dueToday, true},
+		{"due>-1d", dueToday, true},
+		{"due:+1d", dueToday, false},
+
+		// Times of day are ignored when comparing deadlines
+		{"due:today", dueTodayAtTime, true},
+		{"due<=today", dueTodayAtTime, true},
+		{"due>today", dueTodayAtTime, false},
+
+		// Ranges match any day within them, and compare against their first or last day
+		{"due:this-week", dueToday, true},
+		{"due:this-week", dueEndOfWeek, true},
+		{"due:this-month", dueToday, true},
+		{"due:next-week", dueEndOfWeek, false},
+		{"due:last-week", dueToday, false},
+		{"due<this-week", dueEndOfWeek, false},
+		{"due<=this-week", dueEndOfWeek, true},
+		{"due>this-week", dueEndOfWeek, false},
+		{"due<next-week", dueEndOfWeek, true},
+		{"due>=this-week", dueToday, true},
+		{"due>last-week", dueToday, true},
+		{"due>=next-month", dueEndOfWeek, lastDayOfWeek.Month() != today.Month()},
+
+		{"due:today", noDeadline, false},
+		{"-due:today", noDeadline, true},
+	}
+
+	for _, test := range tests {
+
+		query, err := ParseQuery(test.query, false)
+		if err != nil {
+			t.Errorf("ParseQuery(%q) returned error: %s", test.query, err)
+			continue
+		}
+
+		if got := query.Matches(test.card); got != test.want {
+			t.Errorf("ParseQuery(%q).Matches(deadline %q) = %v, want %v", test.query, test.card.Properties.Get("deadline").AsString(), got, test.want)
+		}
+
+	}
+
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RecurrenceDaily = iota
+	RecurrenceWeekly
+	RecurrenceMonthly
+	RecurrenceEveryNDays
+)
+
+// RecurrenceHistoryLength is the maximum number of past completions remembered for a recurring Card.
+const RecurrenceHistoryLength = 10
+
+var recurrenceWeekdays = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+// RecurrenceRule describes how often a recurring Card repeats. It's stored in a Card's "recurrence" property as a string, formatted as
+// "daily", "weekly:Mon,Thu", "monthly" or "monthly:31" (on the 31st, or the last day of shorter months), or "every:3" (for every 3 days).
+type RecurrenceRule struct {
+	Kind     int
+	Weekdays []time.Weekday
+	Interval int
+	Day      int // The day of the month a monthly rule recurs on; if it's 0, it recurs on the same day as the date it's recurring from
+}
+
+// ParseRecurrence parses a RecurrenceRule from its string form.
+func ParseRecurrence(text string) (*RecurrenceRule, error) {
+
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	kind, value := text, ""
+	if i := strings.Index(text, ":"); i >= 0 {
+		kind, value = strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:])
+	}
+
+	rule := &RecurrenceRule{Interval: 1}
+
+	switch kind {
+
+	case "daily":
+		rule.Kind = RecurrenceDaily
+
+	case "weekly":
+
+		rule.Kind = RecurrenceWeekly
+
+		for _, day := range strings.Split(value, ",") {
+
+			day = strings.TrimSpace(day)
+			if day == "" {
+				continue
+			}
+
+			found := false
+			for i, weekday := range recurrenceWeekdays {
+				if strings.HasPrefix(day, strings.ToLower(weekday)) {
+					rule.Weekdays = append(rule.Weekdays, time.Weekday(i))
+					found = true
+				}
+			}
+
+			if !found {
+				return nil, errors.New("unknown weekday: " + day)
+			}
+
+		}
+
+	case "monthly":
+
+		rule.Kind = RecurrenceMonthly
+
+		if value != "" {
+			day, err := strconv.Atoi(value)
+			if err != nil || day < 1 || day > 31 {
+				return nil, errors.New("the day of the month to repeat on should be a whole number from 1 to 31")
+			}
+			rule.Day = day
+		}
+
+	case "every":
+
+		interval, err := strconv.Atoi(value)
+		if err != nil || interval < 1 {
+			return nil, errors.New("the number of days to repeat every should be a whole number above 0")
+		}
+
+		rule.Kind = RecurrenceEveryNDays
+		rule.Interval = interval
+
+	default:
+		return nil, errors.New("unknown recurrence: " + text)
+
+	}
+
+	return rule, nil
+
+}
+
+// String returns the RecurrenceRule in the form it's stored in.
+func (rule *RecurrenceRule) String() string {
+
+	switch rule.Kind {
+	case RecurrenceWeekly:
+		days := []string{}
+		for _, day := range rule.Weekdays {
+			days = append(days, recurrenceWeekdays[day])
+		}
+		if len(days) == 0 {
+			return "weekly"
+		}
+		return "weekly:" + strings.Join(days, ",")
+	case RecurrenceMonthly:
+		if rule.Day > 0 {
+			return "monthly:" + strconv.Itoa(rule.Day)
+		}
+		return "monthly"
+	case RecurrenceEveryNDays:
+		return "every:" + strconv.Itoa(rule.Interval)
+	}
+
+	return "daily"
+
+}
+
+// Description returns a human-readable description of the RecurrenceRule.
+func (rule *RecurrenceRule) Description() string {
+
+	switch rule.Kind {
+	case RecurrenceWeekly:
+		if len(rule.Weekdays) == 0 {
+			return "Repeats weekly"
+		}
+		return "Repeats weekly on " + strings.Join(strings.Split(strings.TrimPrefix(rule.String(), "weekly:"), ","), ", ")
+	case RecurrenceMonthly:
+		if rule.Day > 0 {
+			return "Repeats monthly on day " + strconv.Itoa(rule.Day)
+		}
+		return "Repeats monthly"
+	case RecurrenceEveryNDays:
+		return "Repeats every " + strconv.Itoa(rule.Interval) + " days"
+	}
+
+	return "Repeats daily"
+
+}
+
+// Next returns the next date the rule occurs on, strictly after the given date.
+func (rule *RecurrenceRule) Next(after time.Time) time.Time {
+
+	after = time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, after.Location())
+
+	switch rule.Kind {
+
+	case RecurrenceWeekly:
+
+		if len(rule.Weekdays) == 0 {
+			return after.AddDate(0, 0, 7)
+		}
+
+		for i := 1; i <= 7; i++ {
+			next := after.AddDate(0, 0, i)
+			for _, day := range rule.Weekdays {
+				if next.Weekday() == day {
+					return next
+				}
+			}
+		}
+
+	case RecurrenceMonthly:
+
+		day := rule.Day
+		if day == 0 {
+			day = after.Day()
+		}
+
+		// Months without the day (e.g. the 31st in February) recur on their last day instead; the rule's day is kept, so the months
+		// after go back to it
+		next := monthDay(after.Year(), after.Month(), day, after.Location())
+		if !next.After(after) {
+			next = monthDay(after.Year(), after.Month()+1, day, after.Location())
+		}
+
+		return next
+
+	case RecurrenceEveryNDays:
+		return after.AddDate(0, 0, rule.Interval)
+
+	}
+
+	return after.AddDate(0, 0, 1)
+
+}
+
+// monthDay returns the given day of the month, or the month's last day if it's shorter than that.
+func monthDay(year int, month time.Month, day int, location *time.Location) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, location)
+	if day > last.Day() {
+		return last
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, location)
+}
+
+// FitsMonthlyDay returns if the date is an occurrence of a monthly rule recurring on the given day, either falling on the day itself or
+// being the last day of a month that's too short for it.
+func FitsMonthlyDay(date time.Time, day int) bool {
+	return DatesAreEqual(date, monthDay(date.Year(), date.Month(), day, date.Location()))
+}
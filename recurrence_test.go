@@ -0,0 +1,135 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+
+	tests := []struct {
+		text string
+		want RecurrenceRule
+		str  string
+	}{
+		{"daily", RecurrenceRule{Kind: RecurrenceDaily, Interval: 1}, "daily"},
+		{"  Daily ", RecurrenceRule{Kind: RecurrenceDaily, Interval: 1}, "daily"},
+		{"weekly", RecurrenceRule{Kind: RecurrenceWeekly, Interval: 1}, "weekly"},
+		{"weekly:Mon,Thu", RecurrenceRule{Kind: RecurrenceWeekly, Interval: 1, Weekdays: []time.Weekday{time.Monday, time.Thursday}}, "weekly:Mon,Thu"},
+		{"weekly: monday, friday,", RecurrenceRule{Kind: RecurrenceWeekly, Interval: 1, Weekdays: []time.Weekday{time.Monday, time.Friday}}, "weekly:Mon,Fri"},
+		{"monthly", RecurrenceRule{Kind: RecurrenceMonthly, Interval: 1}, "monthly"},
+		{"monthly:31", RecurrenceRule{Kind: RecurrenceMonthly, Interval: 1, Day: 31}, "monthly:31"},
+		{"monthly: 1", RecurrenceRule{Kind: RecurrenceMonthly, Interval: 1, Day: 1}, "monthly:1"},
+		{"every:3", RecurrenceRule{Kind: RecurrenceEveryNDays, Interval: 3}, "every:3"},
+	}
+
+	for _, test := range tests {
+
+		rule, err := ParseRecurrence(test.text)
+		if err != nil {
+			t.Errorf("ParseRecurrence(%q) returned error: %s", test.text, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(*rule, test.want) {
+			t.Errorf("ParseRecurrence(%q) = %+v, want %+v", test.text, *rule, test.want)
+		}
+
+		if str := rule.String(); str != test.str {
+			t.Errorf("ParseRecurrence(%q).String() = %q, want %q", test.text, str, test.str)
+		}
+
+	}
+
+	for _, text := range []string{"", "yearly", "weekly:Someday", "monthly:0", "monthly:32", "monthly:first", "every:0", "every:-2", "every:"} {
+		if _, err := ParseRecurrence(text); err == nil {
+			t.Errorf("ParseRecurrence(%q) should have returned an error", text)
+		}
+	}
+
+}
+
+func TestRecurrenceRuleNext(t *testing.T) {
+
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		rule  string
+		after time.Time
+		want  time.Time
+	}{
+		{"daily", date(2026, 10, 19), date(2026, 10, 20)},
+		{"daily", date(2026, 12, 31), date(2027, 1, 1)},
+		{"every:3", date(2026, 10, 30), date(2026, 11, 2)},
+
+		// October 19th, 2026 is a Monday
+		{"weekly", date(2026, 10, 19), date(2026, 10, 26)},
+		{"weekly:Mon", date(2026, 10, 19), date(2026, 10, 26)},
+		{"weekly:Mon,Thu", date(2026, 10, 19), date(2026, 10, 22)},
+		{"weekly:Mon,Thu", date(2026, 10, 22), date(2026, 10, 26)},
+		{"weekly:Sun", date(2026, 10, 31), date(2026, 11, 1)},
+
+		{"monthly", date(2026, 10, 19), date(2026, 11, 19)},
+		{"monthly", date(2026, 12, 15), date(2027, 1, 15)},
+		{"monthly:15", date(2026, 10, 19), date(2026, 11, 15)},
+		{"monthly:15", date(2026, 10, 1), date(2026, 10, 15)},
+
+		// Months too short for the rule's day recur on their last day, and the months after go back to the rule's day
+		{"monthly", date(2027, 1, 31), date(2027, 2, 28)},
+		{"monthly:31", date(2027, 1, 31), date(2027, 2, 28)},
+		{"monthly:31", date(2027, 2, 28), date(2027, 3, 31)},
+		{"monthly:31", date(2027, 3, 31), date(2027, 4, 30)},
+		{"monthly:31", date(2027, 4, 30), date(2027, 5, 31)},
+		{"monthly:30", date(2028, 1, 30), date(2028, 2, 29)},
+		{"monthly:29", date(2028, 2, 29), date(2028, 3, 29)},
+		{"monthly:31", date(2026, 12, 31), date(2027, 1, 31)},
+	}
+
+	for _, test := range tests {
+
+		rule, err := ParseRecurrence(test.rule)
+		if err != nil {
+			t.Errorf("ParseRecurrence(%q) returned error: %s", test.rule, err)
+			continue
+		}
+
+		if got := rule.Next(test.after); !got.Equal(test.want) {
+			t.Errorf("%q.Next(%s) = %s, want %s", test.rule, test.after.Format("2006-01-02"), got.Format("2006-01-02"), test.want.Format("2006-01-02"))
+		}
+
+	}
+
+	// Times of day don't count, so the next occurrence is always on a later day
+	rule, _ := ParseRecurrence("daily")
+	if got, want := rule.Next(time.Date(2026, 10, 19, 23, 30, 0, 0, time.Local)), date(2026, 10, 20); !got.Equal(want) {
+		t.Errorf("daily.Next(2026-10-19 23:30) = %s, want %s", got, want)
+	}
+
+}
+
+func TestFitsMonthlyDay(t *testing.T) {
+
+	tests := []struct {
+		date time.Time
+		day  int
+		want bool
+	}{
+		{time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local), 19, true},
+		{time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local), 20, false},
+		{time.Date(2027, 2, 28, 0, 0, 0, 0, time.Local), 31, true},
+		{time.Date(2027, 2, 28, 0, 0, 0, 0, time.Local), 28, true},
+		{time.Date(2027, 2, 27, 0, 0, 0, 0, time.Local), 31, false},
+		{time.Date(2027, 4, 30, 0, 0, 0, 0, time.Local), 31, true},
+		{time.Date(2027, 3, 30, 0, 0, 0, 0, time.Local), 31, false},
+	}
+
+	for _, test := range tests {
+		if got := FitsMonthlyDay(test.date, test.day); got != test.want {
+			t.Errorf("FitsMonthlyDay(%s, %d) = %v, want %v", test.date.Format("2006-01-02"), test.day, got, test.want)
+		}
+	}
+
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gen2brain/beeep"
+	"github.com/hako/durafmt"
+)
+
+const (
+	DeadlineDateFormat     = "2006-01-02"
+	DeadlineDateTimeFormat = "2006-01-02 15:04"
+	DeadlineTimeFormat     = "15:04"
+)
+
+// ParseDeadline parses a deadline, which is either a date ("2006-01-02") or a date with a time of day ("2006-01-02 15:04"), returning
+// the deadline and whether it has a time of day.
+func ParseDeadline(text string) (time.Time, bool, error) {
+
+	text = strings.TrimSpace(text)
+
+	if deadline, err := time.ParseInLocation(DeadlineDateTimeFormat, text, time.Local); err == nil {
+		return deadline, true, nil
+	}
+
+	deadline, err := time.ParseInLocation(DeadlineDateFormat, text, time.Local)
+	return deadline, false, err
+
+}
+
+// FormatDeadline formats a deadline in the form it's stored in a Card's "deadline" property.
+func FormatDeadline(deadline time.Time, hasTime bool) string {
+	if hasTime {
+		return deadline.Format(DeadlineDateTimeFormat)
+	}
+	return deadline.Format(DeadlineDateFormat)
+}
+
+// ParseReminderLeadTimes parses a comma-separated list of reminder lead times (e.g. "1d, 1h, 30m"), sorted from longest to shortest.
+func ParseReminderLeadTimes(text string) ([]time.Duration, error) {
+
+	leads := []time.Duration{}
+
+	for _, lead := range strings.Split(text, ",") {
+
+		lead = strings.ToLower(strings.TrimSpace(lead))
+
+		if lead == "" {
+			continue
+		}
+
+		var duration time.Duration
+
+		if strings.HasSuffix(lead, "d") {
+			days, err := strconv.ParseFloat(strings.TrimSuffix(lead, "d"), 64)
+			if err != nil {
+				return nil, errors.New("invalid reminder lead time: " + lead)
+			}
+			duration = time.Duration(days * float64(time.Hour*24))
+		} else {
+			parsed, err := time.ParseDuration(lead)
+			if err != nil {
+				return nil, errors.New("invalid reminder lead time: " + lead)
+			}
+			duration = parsed
+		}
+
+		if duration < 0 {
+			return nil, errors.New("reminder lead times can't be negative: " + lead)
+		}
+
+		leads = append(leads, duration)
+
+	}
+
+	for i := 1; i < len(leads); i++ {
+		for j := i; j > 0 && leads[j] > leads[j-1]; j-- {
+			leads[j], leads[j-1] = leads[j-1], leads[j]
+		}
+	}
+
+	return leads, nil
+
+}
+
+// FormatLeadTime returns a human-readable version of a reminder lead time (e.g. "1 day", "2 hours", "30 minutes").
+func FormatLeadTime(lead time.Duration) string {
+
+	plural := func(count int64, unit string) string {
+		if count == 1 {
+			return "1 " + unit
+		}
+		return strconv.FormatInt(count, 10) + " " + unit + "s"
+	}
+
+	switch {
+	case lead >= time.Hour*24 && lead%(time.Hour*24) == 0:
+		return plural(int64(lead/(time.Hour*24)), "day")
+	case lead >= time.Hour && lead%time.Hour == 0:
+		return plural(int64(lead/time.Hour), "hour")
+	}
+
+	return plural(int64(lead/time.Minute), "minute")
+
+}
+
+// Reminder is a deadline reminder that has been sent for a Card, and is awaiting being dismissed or snoozed from the Deadlines menu.
+type Reminder struct {
+	Card    *Card
+	Message string
+	Time    time.Time
+}
+
+// CheckReminders sends reminders for Cards whose deadlines are within any of the reminder lead times set in the settings, as well as for
+// snoozed reminders that are due again. Which reminders have been sent is stored in each Card, so reminders aren't sent again after
+// reloading the project, while reminders that would have been sent while MasterPlan was closed are sent once the project is opened.
+func (project *Project) CheckReminders() {
+
+	if project.Loading || time.Since(project.lastReminderCheck) < time.Second {
+		return
+	}
+
+	project.lastReminderCheck = time.Now()
+
+	leads, err := ParseReminderLeadTimes(globals.Settings.Get(SettingsDeadlineReminders).AsString())
+	if err != nil {
+		leads = []time.Duration{}
+	}
+
+	now := time.Now()
+
+	// Reminders for deadlines that have already passed are gathered up into a single notification, so opening a project with many overdue
+	// Cards doesn't send a flood of them
+	overdue := []*Card{}
+
+	for _, page := range project.Pages {
+
+		if !page.Valid() {
+			continue
+		}
+
+		for _, card := range page.Cards {
+
+			due, hasDeadline := card.DeadlineTime()
+
+			if !hasDeadline || card.Completed() {
+				continue
+			}
+
+			if snooze := card.ReminderSnooze(); !snooze.IsZero() {
+				if now.After(snooze) {
+					card.setReminderState("reminder snooze", "")
+					project.SendReminder(card, due)
+				}
+				continue
+			}
+
+			deadline := card.Properties.Get("deadline").AsString()
+			sent := card.sentReminders(deadline)
+			reminded := false
+
+			for _, lead := range leads {
+
+				key := strconv.FormatInt(int64(lead/time.Minute), 10) + "m"
+
+				if !TagsContain(sent, key) && !now.Before(due.Add(-lead)) {
+					sent = append(sent, key)
+					reminded = true
+				}
+
+			}
+
+			if reminded {
+				// Only one reminder is sent even if several lead times have passed (e.g. after reopening the project)
+				card.setReminderState("reminders sent", deadline+"|"+strings.Join(sent, ","))
+				if now.After(due) {
+					overdue = append(overdue, card)
+				} else {
+					project.SendReminder(card, due)
+				}
+			}
+
+		}
+
+	}
+
+	if len(overdue) == 1 {
+		due, _ := overdue[0].DeadlineTime()
+		project.SendReminder(overdue[0], due)
+	} else if len(overdue) > 1 {
+		for _, card := range overdue {
+			due, _ := card.DeadlineTime()
+			project.addReminder(card, due)
+		}
+		message := strconv.Itoa(len(overdue)) + " Cards are overdue; see the Deadlines menu for the list."
+		beeep.Notify("MasterPlan", message, "")
+		globals.EventLog.Log("%s", true, message)
+	}
+
+}
+
+// SendReminder sends a desktop notification reminding of the given Card's deadline, and lists it in the Deadlines menu.
+func (project *Project) SendReminder(card *Card, due time.Time) {
+	message := project.addReminder(card, due)
+	beeep.Notify("MasterPlan", message, "")
+	globals.EventLog.Log("%s", true, message)
+}
+
+// addReminder lists a reminder of the given Card's deadline in the Deadlines menu, returning the reminder's message.
+func (project *Project) addReminder(card *Card, due time.Time) string {
+
+	name := []rune(strings.ReplaceAll(card.Name(), "\n", " - "))
+	if len(name) > 64 {
+		name = append(name[:64], []rune("...")...)
+	}
+
+	message := ""
+
+	if remaining := time.Until(due); remaining > 0 {
+		message = "[" + string(name) + "] is due in " + durafmt.Parse(remaining.Round(time.Minute)).LimitFirstN(2).String() + "."
+	} else {
+		message = "[" + string(name) + "] is overdue."
+	}
+
+	for i, reminder := range project.Reminders {
+		if reminder.Card == card {
+			project.Reminders = append(project.Reminders[:i], project.Reminders[i+1:]...)
+			break
+		}
+	}
+
+	project.Reminders = append(project.Reminders, &Reminder{Card: card, Message: message, Time: time.Now()})
+	project.RemindersChanged = true
+
+	return message
+
+}
+
+// DismissReminder removes a sent reminder from the Deadlines menu.
+func (project *Project) DismissReminder(reminder *Reminder) {
+
+	for i, r := range project.Reminders {
+		if r == reminder {
+			project.Reminders = append(project.Reminders[:i], project.Reminders[i+1:]...)
+			project.RemindersChanged = true
+			break
+		}
+	}
+
+}
+
+// SnoozeReminder dismisses a sent reminder, sending it again after the given duration.
+func (project *Project) SnoozeReminder(reminder *Reminder, duration time.Duration) {
+	reminder.Card.setReminderState("reminder snooze", time.Now().Add(duration).Format(time.RFC3339))
+	project.DismissReminder(reminder)
+	globals.EventLog.Log("Reminder for [%s] snoozed for %s.", false, reminder.Card.Name(), FormatLeadTime(duration))
+}
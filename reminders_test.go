@@ -0,0 +1,103 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseDeadline(t *testing.T) {
+
+	tests := []struct {
+		text    string
+		want    time.Time
+		hasTime bool
+	}{
+		{"2026-10-19", time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local), false},
+		{" 2026-10-19 ", time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local), false},
+		{"2026-10-19 15:04", time.Date(2026, 10, 19, 15, 4, 0, 0, time.Local), true},
+		{"2026-10-19 00:00", time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local), true},
+	}
+
+	for _, test := range tests {
+
+		deadline, hasTime, err := ParseDeadline(test.text)
+
+		if err != nil {
+			t.Errorf("ParseDeadline(%q) returned error: %s", test.text, err)
+			continue
+		}
+
+		if !deadline.Equal(test.want) || hasTime != test.hasTime {
+			t.Errorf("ParseDeadline(%q) = %s, %v, want %s, %v", test.text, deadline, hasTime, test.want, test.hasTime)
+		}
+
+		if formatted := FormatDeadline(deadline, hasTime); formatted != FormatDeadline(test.want, test.hasTime) {
+			t.Errorf("FormatDeadline(ParseDeadline(%q)) = %q", test.text, formatted)
+		}
+
+	}
+
+	for _, text := range []string{"", "tomorrow", "2026-10-19 25:00", "2026-10-19T15:04", "19/10/2026"} {
+		if _, _, err := ParseDeadline(text); err == nil {
+			t.Errorf("ParseDeadline(%q) should have returned an error", text)
+		}
+	}
+
+}
+
+func TestParseReminderLeadTimes(t *testing.T) {
+
+	tests := []struct {
+		text string
+		want []time.Duration
+	}{
+		{"", []time.Duration{}},
+		{"1d", []time.Duration{time.Hour * 24}},
+		{"30m, 1d, 1h", []time.Duration{time.Hour * 24, time.Hour, time.Minute * 30}},
+		{" 1.5d ,, 2H ", []time.Duration{time.Hour * 36, time.Hour * 2}},
+		{"1h30m, 0m", []time.Duration{time.Minute * 90, 0}},
+	}
+
+	for _, test := range tests {
+
+		leads, err := ParseReminderLeadTimes(test.text)
+
+		if err != nil {
+			t.Errorf("ParseReminderLeadTimes(%q) returned error: %s", test.text, err)
+		} else if !reflect.DeepEqual(leads, test.want) {
+			t.Errorf("ParseReminderLeadTimes(%q) = %v, want %v", test.text, leads, test.want)
+		}
+
+	}
+
+	for _, text := range []string{"soon", "1x", "d", "-1h", "-2d", "1d, later"} {
+		if _, err := ParseReminderLeadTimes(text); err == nil {
+			t.Errorf("ParseReminderLeadTimes(%q) should have returned an error", text)
+		}
+	}
+
+}
+
+func TestFormatLeadTime(t *testing.T) {
+
+	tests := []struct {
+		lead time.Duration
+		want string
+	}{
+		{time.Hour * 24, "1 day"},
+		{time.Hour * 48, "2 days"},
+		{time.Hour * 36, "36 hours"},
+		{time.Hour, "1 hour"},
+		{time.Minute * 90, "90 minutes"},
+		{time.Minute, "1 minute"},
+		{0, "0 minutes"},
+	}
+
+	for _, test := range tests {
+		if got := FormatLeadTime(test.lead); got != test.want {
+			t.Errorf("FormatLeadTime(%s) = %q, want %q", test.lead, got, test.want)
+		}
+	}
+
+}
@@ -36,7 +36,7 @@ func (selection *Selection) Update() {
 			if globals.Keybindings.Pressed(KBRemoveFromSelection) {
 
 				for _, card := range selection.Page.Cards {
-					if card.Rect.HasIntersection(selectionRect) {
+					if selection.inBox(card, selectionRect) {
 						selection.Remove(card)
 					}
 				}
@@ -44,7 +44,7 @@ func (selection *Selection) Update() {
 			} else {
 
 				for _, card := range selection.Page.Cards {
-					if card.Rect.HasIntersection(selectionRect) {
+					if selection.inBox(card, selectionRect) {
 						selection.Add(card)
 					}
 				}
@@ -59,6 +59,22 @@ func (selection *Selection) Update() {
 
 }
 
+// inBox returns if the Card is caught by a selection box. Frames are only selected when the box encloses them entirely, as they cover the Cards
+// inside of them, and Cards hidden inside of collapsed Frames can't be selected at all.
+func (selection *Selection) inBox(card *Card, box *sdl.FRect) bool {
+
+	if card.hidden {
+		return false
+	}
+
+	if card.ContentType == ContentTypeFrame {
+		return card.Rect.X >= box.X && card.Rect.Y >= box.Y && card.Rect.X+card.Rect.W <= box.X+box.W && card.Rect.Y+card.Rect.H <= box.Y+box.H
+	}
+
+	return card.Rect.HasIntersection(box)
+
+}
+
 func (selection *Selection) Add(card *Card) {
 	if !card.selected {
 		card.Page.Raise(card)
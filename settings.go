@@ -47,10 +47,15 @@ const (
 	SettingsCardShadows                  = "Card Shadows"
 	SettingsFlashDeadlines               = "Flash Deadlines"
 	SettingsDeadlineDisplay              = "Display Deadlines As"
+	SettingsDeadlineReminders            = "Deadline Reminders"
 	SettingsMaxInternalImageSize         = "Max Internal Image Buffer Size"
 	SettingsPlaceNewCardsInStack         = "Position New Cards in Stack"
 	SettingsHideGridOnZoomOut            = "Hide Grid on Zoom out"
 	SettingsDisplayNumberedPercentagesAs = "Display Numbered Percentages"
+	SettingsRenderMarkdown               = "Render Markdown in Notes"
+	SettingsSpellCheck                   = "Spell Check"
+	SettingsSpellCheckLanguage           = "Spell Check Language"
+	SettingsFallbackFonts                = "Fallback Fonts"
 
 	SettingsAudioVolume     = "AudioVolume"
 	SettingsAudioBufferSize = "Audio Playback Buffer Size"
@@ -165,10 +170,15 @@ func NewProgramSettings() *Properties {
 	props.Get(SettingsZoomToCursor).Set(true)
 	props.Get(SettingsCardShadows).Set(true)
 	props.Get(SettingsFlashDeadlines).Set(true)
+	props.Get(SettingsDeadlineReminders).Set("1d, 1h")
 	props.Get(SettingsMaxInternalImageSize).Set(ImageBufferSize2048)
 	props.Get(SettingsPlaceNewCardsInStack).Set(false)
 	props.Get(SettingsHideGridOnZoomOut).Set(true)
 	props.Get(SettingsDisplayNumberedPercentagesAs).Set(NumberedPercentagePercent)
+	props.Get(SettingsRenderMarkdown).Set(true)
+	props.Get(SettingsSpellCheck).Set(false)
+	props.Get(SettingsSpellCheckLanguage).Set("en_US")
+	props.Get(SettingsFallbackFonts).Set(DefaultFallbackFonts())
 
 	// Audio settings; not shown in MasterPlan because it's very rarely necessary to tweak
 	props.Get(SettingsAudioVolume).Set(80.0)
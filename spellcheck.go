@@ -0,0 +1,755 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/adrg/xdg"
+)
+
+// SpellCheckDictionaryDirectory is where Hunspell dictionaries (pairs of .aff and .dic files, i.e. "en_US.aff" and "en_US.dic") are loaded
+// from, relative to the config directory.
+const SpellCheckDictionaryDirectory = "MasterPlan/dictionaries"
+
+// How many corrections are offered for a misspelled word.
+const spellCheckMaxSuggestions = 8
+
+// SpellCheckDictionaryPath returns the full path of the directory dictionaries are loaded from.
+func SpellCheckDictionaryPath() string {
+	return filepath.Join(xdg.ConfigHome, filepath.FromSlash(SpellCheckDictionaryDirectory))
+}
+
+// AvailableDictionaries returns the names of the dictionaries in the dictionary directory, sorted alphabetically.
+func AvailableDictionaries() []string {
+
+	names := []string{}
+
+	dicFiles, _ := filepath.Glob(filepath.Join(SpellCheckDictionaryPath(), "*.dic"))
+
+	for _, dicFile := range dicFiles {
+		name := strings.TrimSuffix(filepath.Base(dicFile), filepath.Ext(dicFile))
+		if FileExists(strings.TrimSuffix(dicFile, filepath.Ext(dicFile)) + ".aff") {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+
+}
+
+type affixRule struct {
+	Strip        string
+	Add          string
+	Condition    *regexp.Regexp // nil if the rule applies to any word
+	Continuation []string       // Flags of suffixes that can be added on after this one
+}
+
+type affixClass struct {
+	Prefix bool
+	Cross  bool // Whether the affix can be combined with affixes of the other kind
+	Rules  []*affixRule
+}
+
+// apply returns the word with the affix rule applied to it, and if the rule could be applied.
+func (class *affixClass) apply(rule *affixRule, word string) (string, bool) {
+
+	if class.Prefix {
+		if strings.HasPrefix(word, rule.Strip) && (rule.Condition == nil || rule.Condition.MatchString(word)) {
+			return rule.Add + word[len(rule.Strip):], true
+		}
+	} else if strings.HasSuffix(word, rule.Strip) && (rule.Condition == nil || rule.Condition.MatchString(word)) {
+		return word[:len(word)-len(rule.Strip)] + rule.Add, true
+	}
+
+	return "", false
+
+}
+
+// Dictionary is a spell checking dictionary loaded from a pair of Hunspell .aff and .dic files. Words are expanded with their prefixes and
+// suffixes when the dictionary is loaded; compounding and morphological analysis aren't supported.
+type Dictionary struct {
+	Name      string
+	words     map[string]bool
+	noSuggest map[string]bool
+	try       []rune
+	replace   [][2]string
+
+	flagMode      string
+	flagAliases   [][]string
+	affixes       map[string]*affixClass
+	needAffix     string
+	forbidden     string
+	noSuggestFlag string
+}
+
+// LoadDictionary loads the dictionary of the given name from the dictionary directory.
+func LoadDictionary(name string) (*Dictionary, error) {
+
+	base := filepath.Join(SpellCheckDictionaryPath(), name)
+
+	affData, err := os.ReadFile(base + ".aff")
+	if err != nil {
+		return nil, err
+	}
+
+	dicData, err := os.ReadFile(base + ".dic")
+	if err != nil {
+		return nil, err
+	}
+
+	dict := &Dictionary{
+		Name:      name,
+		words:     map[string]bool{},
+		noSuggest: map[string]bool{},
+		affixes:   map[string]*affixClass{},
+	}
+
+	// Hunspell files are commonly encoded in UTF-8 or ISO 8859-1; the encoding is given in the affix file.
+	latin1 := false
+	if match := regexp.MustCompile(`(?m)^SET\s+(\S+)`).FindSubmatch(affData); match != nil {
+		encoding := strings.ToUpper(string(match[1]))
+		latin1 = strings.HasPrefix(encoding, "ISO8859") || strings.HasPrefix(encoding, "ISO-8859")
+	}
+
+	decode := func(data []byte) string {
+		if !latin1 {
+			return string(data)
+		}
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	}
+
+	dict.parseAffixes(decode(affData))
+
+	scanner := bufio.NewScanner(bytes.NewReader(dicData))
+	scanner.Buffer(make([]byte, 1024*64), 1024*1024)
+
+	for lineIndex := 0; scanner.Scan(); lineIndex++ {
+
+		fields := strings.Fields(decode(scanner.Bytes()))
+
+		// The first line is the (approximate) number of words in the dictionary
+		if len(fields) == 0 || (lineIndex == 0 && len(fields) == 1 && isNumber(fields[0])) {
+			continue
+		}
+
+		word := fields[0]
+		flags := ""
+
+		if slash := strings.Index(word, "/"); slash > 0 {
+			flags = word[slash+1:]
+			word = word[:slash]
+		}
+
+		dict.addWord(word, dict.parseFlags(flags))
+
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(dict.words) == 0 {
+		return nil, errors.New("no words found in dictionary")
+	}
+
+	if len(dict.try) == 0 {
+		dict.try = []rune("etaoinshrdlcumwfgypbvkjxqz")
+	}
+
+	return dict, nil
+
+}
+
+func isNumber(text string) bool {
+	_, err := strconv.Atoi(text)
+	return err == nil
+}
+
+// parseAffixes parses the contents of a Hunspell affix file.
+func (dict *Dictionary) parseAffixes(text string) {
+
+	for _, line := range strings.Split(text, "\n") {
+
+		fields := strings.Fields(line)
+
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		switch fields[0] {
+
+		case "FLAG":
+			dict.flagMode = fields[1]
+
+		case "AF":
+			// The first AF line gives the number of aliases, so it's skipped
+			if dict.flagAliases == nil && isNumber(fields[1]) {
+				dict.flagAliases = [][]string{}
+			} else {
+				dict.flagAliases = append(dict.flagAliases, dict.splitFlags(fields[1]))
+			}
+
+		case "TRY":
+			dict.try = []rune(fields[1])
+
+		case "REP":
+			if len(fields) >= 3 {
+				dict.replace = append(dict.replace, [2]string{strings.ReplaceAll(fields[1], "_", " "), strings.ReplaceAll(fields[2], "_", " ")})
+			}
+
+		case "NEEDAFFIX", "PSEUDOROOT":
+			dict.needAffix = fields[1]
+
+		case "FORBIDDENWORD":
+			dict.forbidden = fields[1]
+
+		case "NOSUGGEST":
+			dict.noSuggestFlag = fields[1]
+
+		case "PFX", "SFX":
+
+			if len(fields) < 4 {
+				continue
+			}
+
+			flag := fields[1]
+			class, exists := dict.affixes[fields[0]+flag]
+
+			if !exists {
+				// The first line of an affix is its header
+				dict.affixes[fields[0]+flag] = &affixClass{Prefix: fields[0] == "PFX", Cross: fields[2] == "Y"}
+				continue
+			}
+
+			rule := &affixRule{Strip: fields[2], Add: fields[3]}
+
+			if rule.Strip == "0" {
+				rule.Strip = ""
+			}
+
+			if slash := strings.Index(rule.Add, "/"); slash >= 0 {
+				rule.Continuation = dict.parseFlags(rule.Add[slash+1:])
+				rule.Add = rule.Add[:slash]
+			}
+
+			if rule.Add == "0" {
+				rule.Add = ""
+			}
+
+			if len(fields) >= 5 && fields[4] != "." {
+				condition := "(?:" + fields[4] + ")$"
+				if class.Prefix {
+					condition = "^(?:" + fields[4] + ")"
+				}
+				if regex, err := regexp.Compile(condition); err == nil {
+					rule.Condition = regex
+				} else {
+					continue // Skip rules with conditions we can't make sense of
+				}
+			}
+
+			class.Rules = append(class.Rules, rule)
+
+		}
+
+	}
+
+}
+
+// parseFlags returns a word's or affix's flags, which may be given as the number of a flag alias.
+func (dict *Dictionary) parseFlags(flags string) []string {
+
+	if len(dict.flagAliases) > 0 && isNumber(flags) {
+		if index, _ := strconv.Atoi(flags); index > 0 && index <= len(dict.flagAliases) {
+			return dict.flagAliases[index-1]
+		}
+	}
+
+	return dict.splitFlags(flags)
+
+}
+
+// splitFlags splits flags into individual flags according to the affix file's flag type.
+func (dict *Dictionary) splitFlags(flags string) []string {
+
+	if flags == "" {
+		return nil
+	}
+
+	parsed := []string{}
+
+	switch dict.flagMode {
+
+	case "long":
+		runes := []rune(flags)
+		for i := 0; i+1 < len(runes); i += 2 {
+			parsed = append(parsed, string(runes[i:i+2]))
+		}
+
+	case "num":
+		parsed = strings.Split(flags, ",")
+
+	default:
+		for _, r := range flags {
+			parsed = append(parsed, string(r))
+		}
+
+	}
+
+	return parsed
+
+}
+
+// addWord adds the word to the dictionary, along with every form it can take with the affixes given by its flags.
+func (dict *Dictionary) addWord(word string, flags []string) {
+
+	hasFlag := func(flag string) bool {
+		if flag == "" {
+			return false
+		}
+		for _, f := range flags {
+			if f == flag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if hasFlag(dict.forbidden) {
+		return
+	}
+
+	forms := []string{}
+
+	if !hasFlag(dict.needAffix) {
+		forms = append(forms, word)
+	}
+
+	for _, flag := range flags {
+
+		if suffix, exists := dict.affixes["SFX"+flag]; exists {
+
+			for _, rule := range suffix.Rules {
+
+				suffixed, ok := suffix.apply(rule, word)
+				if !ok {
+					continue
+				}
+
+				forms = append(forms, suffixed)
+
+				// Suffixed words can take prefixes, too, if both affixes can be combined
+				if suffix.Cross {
+					for _, prefixFlag := range flags {
+						if prefix, exists := dict.affixes["PFX"+prefixFlag]; exists && prefix.Cross {
+							for _, prefixRule := range prefix.Rules {
+								if prefixed, ok := prefix.apply(prefixRule, suffixed); ok {
+									forms = append(forms, prefixed)
+								}
+							}
+						}
+					}
+				}
+
+				for _, continuation := range rule.Continuation {
+					if second, exists := dict.affixes["SFX"+continuation]; exists {
+						for _, secondRule := range second.Rules {
+							if twiceSuffixed, ok := second.apply(secondRule, suffixed); ok {
+								forms = append(forms, twiceSuffixed)
+							}
+						}
+					}
+				}
+
+			}
+
+		}
+
+		if prefix, exists := dict.affixes["PFX"+flag]; exists {
+			for _, rule := range prefix.Rules {
+				if prefixed, ok := prefix.apply(rule, word); ok {
+					forms = append(forms, prefixed)
+				}
+			}
+		}
+
+	}
+
+	noSuggest := hasFlag(dict.noSuggestFlag)
+
+	for _, form := range forms {
+		dict.words[form] = true
+		if noSuggest {
+			dict.noSuggest[form] = true
+		}
+	}
+
+}
+
+// capitalize returns the word with its first letter in uppercase.
+func capitalize(word string) string {
+	runes := []rune(word)
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	return string(runes)
+}
+
+// Check returns if the word is spelled correctly. Words are also accepted capitalized (i.e. at the start of a sentence) or in all caps.
+func (dict *Dictionary) Check(word string) bool {
+
+	word = strings.ReplaceAll(word, "’", "'")
+
+	if dict.words[word] {
+		return true
+	}
+
+	lower := strings.ToLower(word)
+
+	if word == capitalize(lower) {
+		return dict.words[lower]
+	}
+
+	if word == strings.ToUpper(word) {
+		return dict.words[lower] || dict.words[capitalize(lower)]
+	}
+
+	return false
+
+}
+
+// Suggest returns corrections for a misspelled word; these are words in the dictionary that are one edit (a replacement, insertion, deletion,
+// or swap of letters) away from it, or that the affix file lists as common replacements.
+func (dict *Dictionary) Suggest(word string) []string {
+
+	suggestions := []string{}
+
+	lower := strings.ToLower(word)
+	capitalized := word == capitalize(lower) && word != lower
+
+	// Corrections are looked for in lowercase and capitalized again afterwards if the word was
+	if capitalized {
+		word = lower
+	}
+
+	seen := map[string]bool{word: true}
+
+	try := func(candidate string) {
+
+		if seen[candidate] || len(suggestions) >= spellCheckMaxSuggestions {
+			return
+		}
+
+		seen[candidate] = true
+
+		valid := dict.words[candidate]
+
+		if strings.Contains(candidate, " ") {
+			valid = true
+			for _, part := range strings.Fields(candidate) {
+				valid = valid && (dict.words[part] || dict.words[capitalize(part)])
+			}
+		}
+
+		if valid && !dict.noSuggest[candidate] {
+			if capitalized {
+				candidate = capitalize(candidate)
+			}
+			suggestions = append(suggestions, candidate)
+		}
+
+	}
+
+	// The word might just need to be capitalized (i.e. a name)
+	try(capitalize(word))
+
+	for _, rep := range dict.replace {
+		for start := 0; start < len(word); {
+			index := strings.Index(word[start:], rep[0])
+			if index < 0 {
+				break
+			}
+			index += start
+			try(word[:index] + rep[1] + word[index+len(rep[0]):])
+			start = index + 1
+		}
+	}
+
+	runes := []rune(word)
+
+	for i := 0; i < len(runes)-1; i++ {
+		swapped := append([]rune{}, runes...)
+		swapped[i], swapped[i+1] = swapped[i+1], swapped[i]
+		try(string(swapped))
+	}
+
+	for i := range runes {
+		for _, r := range dict.try {
+			if r != runes[i] {
+				try(string(runes[:i]) + string(r) + string(runes[i+1:]))
+			}
+		}
+	}
+
+	for i := range runes {
+		try(string(runes[:i]) + string(runes[i+1:]))
+	}
+
+	for i := 0; i <= len(runes); i++ {
+		for _, r := range dict.try {
+			try(string(runes[:i]) + string(r) + string(runes[i:]))
+		}
+	}
+
+	// Two words that have been run together
+	for i := 1; i < len(runes); i++ {
+		try(string(runes[:i]) + " " + string(runes[i:]))
+	}
+
+	return suggestions
+
+}
+
+// SpellingError is a misspelled word in a text; Start and End are the indices of the word's first rune and the rune after its last.
+type SpellingError struct {
+	Start, End int
+	Word       string
+}
+
+// SpellChecker checks text against the dictionary chosen in the settings, as well as against the current Project's dictionary. Dictionaries
+// are loaded in the background, so that the program doesn't stop while a large dictionary is read.
+type SpellChecker struct {
+	// The Label and misspelled word the spelling menu was opened for
+	CorrectingLabel *Label
+	Correcting      SpellingError
+
+	mutex          sync.Mutex
+	enabled        bool
+	language       string
+	dictionary     *Dictionary
+	loadError      error
+	generation     int
+	projectSource  string
+	projectWords   map[string]bool
+	currentProject *Project
+}
+
+func NewSpellChecker() *SpellChecker {
+	return &SpellChecker{projectWords: map[string]bool{}}
+}
+
+// Dictionary returns the dictionary for the language chosen in the settings, loading it if necessary. nil is returned if spell checking
+// is turned off or the dictionary isn't loaded (yet).
+func (sc *SpellChecker) Dictionary() *Dictionary {
+
+	enabled := globals.Settings.Get(SettingsSpellCheck).AsBool()
+	language := globals.Settings.Get(SettingsSpellCheckLanguage).AsString()
+
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	if enabled != sc.enabled {
+		sc.enabled = enabled
+		sc.generation++
+	}
+
+	if !enabled {
+		return nil
+	}
+
+	if sc.loadError != nil {
+		globals.EventLog.Log("Could not load spell checking dictionary [%s]: %s", true, sc.language, sc.loadError.Error())
+		sc.loadError = nil
+	}
+
+	if language != sc.language {
+
+		sc.language = language
+		sc.dictionary = nil
+		sc.generation++
+
+		go func() {
+
+			dict, err := LoadDictionary(language)
+
+			sc.mutex.Lock()
+			defer sc.mutex.Unlock()
+
+			// The language might have been changed again while loading
+			if sc.language == language {
+				sc.dictionary = dict
+				sc.loadError = err
+				sc.generation++
+			}
+
+		}()
+
+	}
+
+	return sc.dictionary
+
+}
+
+// Generation returns a number that changes whenever which words are spelled correctly might have changed (i.e. the dictionary finished
+// loading or a word was added to the Project's dictionary), so that checked text can be checked again.
+func (sc *SpellChecker) Generation() int {
+
+	sc.Dictionary()
+
+	source := ""
+	if globals.Project != nil {
+		if prop := globals.Project.Properties.GetIfExists(ProjectDictionary); prop != nil && prop.IsString() {
+			source = prop.AsString()
+		}
+	}
+
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	if source != sc.projectSource || globals.Project != sc.currentProject {
+		sc.projectSource = source
+		sc.currentProject = globals.Project
+		sc.projectWords = map[string]bool{}
+		if globals.Project != nil {
+			for _, word := range globals.Project.DictionaryWords() {
+				sc.projectWords[strings.ToLower(word)] = true
+			}
+		}
+		sc.generation++
+	}
+
+	return sc.generation
+
+}
+
+// Check returns if the word is spelled correctly, or if it's in the current Project's dictionary.
+func (sc *SpellChecker) Check(word string) bool {
+
+	dict := sc.Dictionary()
+
+	if dict == nil {
+		return true
+	}
+
+	sc.mutex.Lock()
+	inProject := sc.projectWords[strings.ToLower(word)]
+	sc.mutex.Unlock()
+
+	return inProject || dict.Check(word)
+
+}
+
+// OpenMenu opens the spelling menu under the mouse to offer corrections for a misspelled word in the Label.
+func (sc *SpellChecker) OpenMenu(label *Label, misspelling SpellingError) {
+	sc.CorrectingLabel = label
+	sc.Correcting = misspelling
+	menu := globals.MenuSystem.Get("spelling")
+	menu.Rect.X = globals.Mouse.Position().X
+	menu.Rect.Y = globals.Mouse.Position().Y
+	menu.Open()
+}
+
+// Suggest returns corrections for the misspelled word.
+func (sc *SpellChecker) Suggest(word string) []string {
+	if dict := sc.Dictionary(); dict != nil {
+		return dict.Suggest(word)
+	}
+	return nil
+}
+
+// Misspellings returns the misspelled words in the given text. Words that aren't regular words, like those in URLs, inline code or code blocks,
+// words containing digits, acronyms, or words in mixed case (i.e. names in code), aren't checked.
+func (sc *SpellChecker) Misspellings(text []rune) []SpellingError {
+
+	misspellings := []SpellingError{}
+
+	if sc.Dictionary() == nil {
+		return misspellings
+	}
+
+	isWordRune := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.Is(unicode.Mn, r)
+	}
+
+	inCode := false
+
+	for chunkStart := 0; chunkStart < len(text); {
+
+		if unicode.IsSpace(text[chunkStart]) {
+			chunkStart++
+			continue
+		}
+
+		chunkEnd := chunkStart
+		for chunkEnd < len(text) && !unicode.IsSpace(text[chunkEnd]) {
+			chunkEnd++
+		}
+
+		chunk := string(text[chunkStart:chunkEnd])
+
+		ticks := strings.Count(chunk, "`")
+		skip := inCode || ticks > 0 || strings.Contains(chunk, "://") || strings.Contains(chunk, "@") || strings.HasPrefix(chunk, "www.")
+		if ticks%2 == 1 {
+			inCode = !inCode
+		}
+
+		for i := chunkStart; i < chunkEnd && !skip; {
+
+			if !isWordRune(text[i]) {
+				i++
+				continue
+			}
+
+			start := i
+			for i < chunkEnd && (isWordRune(text[i]) || ((text[i] == '\'' || text[i] == '’') && i+1 < chunkEnd && isWordRune(text[i+1]))) {
+				i++
+			}
+
+			word := string(text[start:i])
+			runes := text[start:i]
+
+			// Words stuck to digits or underscores are most likely names or code rather than words
+			if (start > 0 && (unicode.IsDigit(text[start-1]) || text[start-1] == '_')) || (i < len(text) && (unicode.IsDigit(text[i]) || text[i] == '_')) {
+				continue
+			}
+
+			if len(runes) < 2 || word == strings.ToUpper(word) {
+				continue
+			}
+
+			mixedCase := false
+			for _, r := range runes[1:] {
+				if unicode.IsUpper(r) {
+					mixedCase = true
+					break
+				}
+			}
+
+			if !mixedCase && !sc.Check(word) {
+				misspellings = append(misspellings, SpellingError{Start: start, End: i, Word: word})
+			}
+
+		}
+
+		chunkStart = chunkEnd
+
+	}
+
+	return misspellings
+
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/adrg/xdg"
+)
+
+const spellCheckTestAffixes = `SET UTF-8
+REP 2
+REP f ph
+REP alot a_lot
+NEEDAFFIX X
+FORBIDDENWORD F
+NOSUGGEST N
+
+PFX A Y 1
+PFX A 0 re .
+
+SFX B Y 2
+SFX B 0 ed [^y]
+SFX B y ied y
+
+SFX C N 1
+SFX C 0 ful/D .
+
+SFX D N 1
+SFX D 0 ly .
+
+SFX E N 1
+SFX E 0 ness .
+`
+
+const spellCheckTestWords = `14
+work/AB
+try/B
+hope/C
+happi/XE
+go
+on
+a
+lot
+phone
+London
+don't
+damn/N
+colour/F
+`
+
+// useSpellCheckTestDirectory points the config directory at a temporary directory for the rest of the test, and writes the given files
+// to its dictionary directory.
+func useSpellCheckTestDirectory(t *testing.T, files map[string][]byte) {
+
+	configHome := xdg.ConfigHome
+	t.Cleanup(func() { xdg.ConfigHome = configHome })
+
+	xdg.ConfigHome = t.TempDir()
+
+	if err := os.MkdirAll(SpellCheckDictionaryPath(), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(SpellCheckDictionaryPath(), name), contents, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+}
+
+// loadSpellCheckTestDictionary loads a dictionary from the given affix and word files.
+func loadSpellCheckTestDictionary(t *testing.T, affixes, words string) *Dictionary {
+
+	useSpellCheckTestDirectory(t, map[string][]byte{"test.aff": []byte(affixes), "test.dic": []byte(words)})
+
+	dict, err := LoadDictionary("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dict
+
+}
+
+func TestAvailableDictionaries(t *testing.T) {
+
+	useSpellCheckTestDirectory(t, map[string][]byte{
+		"en_US.aff": nil,
+		"en_US.dic": nil,
+		"de_DE.aff": nil,
+		"de_DE.dic": nil,
+		"lone.dic":  nil, // Dictionaries need both files
+		"lone.txt":  nil,
+	})
+
+	if names := AvailableDictionaries(); !reflect.DeepEqual(names, []string{"de_DE", "en_US"}) {
+		t.Errorf("AvailableDictionaries() = %q, want %q", names, []string{"de_DE", "en_US"})
+	}
+
+	if _, err := LoadDictionary("lone"); err == nil {
+		t.Errorf("LoadDictionary() of a dictionary without an affix file should have returned an error")
+	}
+
+	if _, err := LoadDictionary("en_US"); err == nil {
+		t.Errorf("LoadDictionary() of a dictionary without words should have returned an error")
+	}
+
+}
+
+func TestDictionaryCheck(t *testing.T) {
+
+	dict := loadSpellCheckTestDictionary(t, spellCheckTestAffixes, spellCheckTestWords)
+
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{"work", true},
+		{"rework", true},
+		{"worked", true},
+		{"reworked", true},
+		{"works", false},
+		{"tried", true},
+		{"tryed", false},
+		{"hope", true},
+		{"hopeful", true},
+		{"hopefully", true},
+		{"hopely", false},
+
+		// Words that need an affix are only words with one
+		{"happiness", true},
+		{"happi", false},
+		{"colour", false},
+
+		// Words are accepted capitalized or in all caps, but names have to stay capitalized
+		{"Work", true},
+		{"WORK", true},
+		{"wORK", false},
+		{"London", true},
+		{"LONDON", true},
+		{"london", false},
+
+		{"don't", true},
+		{"don’t", true},
+		{"damn", true},
+		{"14", false},
+	}
+
+	for _, test := range tests {
+		if got := dict.Check(test.word); got != test.want {
+			t.Errorf("Check(%q) = %v, want %v", test.word, got, test.want)
+		}
+	}
+
+}
+
+func TestDictionarySuggest(t *testing.T) {
+
+	dict := loadSpellCheckTestDictionary(t, spellCheckTestAffixes, spellCheckTestWords)
+
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{"wrok", []string{"work"}},
+		{"Wrok", []string{"Work"}},
+		{"tryed", []string{"tried"}},
+		{"fone", []string{"phone"}},
+		{"london", []string{"London"}},
+
+		// Replacements and words that have been run together can be more than one word
+		{"alot", []string{"a lot", "lot"}},
+		{"Alot", []string{"A lot", "Lot"}},
+		{"gowork", []string{"go work"}},
+
+		// Words that shouldn't be suggested aren't
+		{"damb", []string{}},
+		{"xyzzy", []string{}},
+	}
+
+	for _, test := range tests {
+		if got := dict.Suggest(test.word); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Suggest(%q) = %q, want %q", test.word, got, test.want)
+		}
+	}
+
+}
+
+func TestDictionaryFlags(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		affixes string
+		words   string
+		want    map[string]bool
+	}{
+		{
+			"long flags",
+			"FLAG long\nSFX Aa Y 1\nSFX Aa 0 s .\nSFX Bb Y 1\nSFX Bb 0 ing .\n",
+			"walk/AaBb\ntalk/Bb\n",
+			map[string]bool{"walk": true, "walks": true, "walking": true, "talk": true, "talks": false, "talking": true},
+		},
+		{
+			"numbered flags",
+			"FLAG num\nSFX 101 Y 1\nSFX 101 0 s .\nSFX 102 Y 1\nSFX 102 0 ing .\n",
+			"walk/101,102\ntalk/102\n",
+			map[string]bool{"walk": true, "walks": true, "walking": true, "talk": true, "talks": false, "talking": true},
+		},
+		{
+			"flag aliases",
+			"FLAG long\nAF 2\nAF AaBb # walk\nAF Bb\nSFX Aa Y 1\nSFX Aa 0 s .\nSFX Bb Y 1\nSFX Bb 0 ing .\n",
+			"2\nwalk/1\ntalk/2\n",
+			map[string]bool{"walk": true, "walks": true, "walking": true, "talk": true, "talks": false, "talking": true, "2": false},
+		},
+		{
+			"Latin-1 encoding",
+			"SET ISO8859-1\nSFX A Y 1\nSFX A 0 s .\n",
+			"1\ncaf\xe9/A\n",
+			map[string]bool{"café": true, "cafés": true, "caf\xe9": false},
+		},
+	}
+
+	for _, test := range tests {
+
+		dict := loadSpellCheckTestDictionary(t, test.affixes, test.words)
+
+		for word, want := range test.want {
+			if got := dict.Check(word); got != want {
+				t.Errorf("%s: Check(%q) = %v, want %v", test.name, word, got, want)
+			}
+		}
+
+	}
+
+}
+
+func TestSpellCheckerMisspellings(t *testing.T) {
+
+	dict := loadSpellCheckTestDictionary(t, spellCheckTestAffixes, spellCheckTestWords)
+
+	settings := globals.Settings
+	defer func() { globals.Settings = settings }()
+
+	globals.Settings = NewProperties()
+	globals.Settings.Get(SettingsSpellCheck).Set(true)
+	globals.Settings.Get(SettingsSpellCheckLanguage).Set("test")
+
+	// The dictionary's already loaded, so it isn't loaded again in the background
+	checker := NewSpellChecker()
+	checker.enabled = true
+	checker.language = "test"
+	checker.dictionary = dict
+
+	text := []rune("Worked on wrok, `wrok code` wrok2 WROK McWrok https://wrok.example damn alot.")
+
+	// Code, URLs, words stuck to digits, acronyms and words in mixed case aren't checked
+	want := []SpellingError{
+		{Start: 10, End: 14, Word: "wrok"},
+		{Start: 72, End: 76, Word: "alot"},
+	}
+
+	if misspellings := checker.Misspellings(text); !reflect.DeepEqual(misspellings, want) {
+		t.Errorf("Misspellings() = %+v, want %+v", misspellings, want)
+	}
+
+	// Words in the Project's dictionary are spelled correctly, too
+	checker.projectWords["wrok"] = true
+
+	if misspellings := checker.Misspellings(text); !reflect.DeepEqual(misspellings, want[1:]) {
+		t.Errorf("Misspellings() with a Project dictionary = %+v, want %+v", misspellings, want[1:])
+	}
+
+	globals.Settings.Get(SettingsSpellCheck).Set(false)
+
+	if misspellings := checker.Misspellings(text); len(misspellings) != 0 {
+		t.Errorf("Misspellings() with spell checking off = %+v, want none", misspellings)
+	}
+
+}
@@ -40,6 +40,13 @@ func NewStack(card *Card) *Stack {
 
 func (stack *Stack) Update() {
 
+	// Frames don't stack with the Cards they hold
+	if stack.Card.ContentType == ContentTypeFrame {
+		stack.Above = nil
+		stack.Below = nil
+		return
+	}
+
 	grid := stack.Card.Page.Grid
 
 	var above *Card
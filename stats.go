@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	StatsScopePage = iota
+	StatsScopeSubpages
+	StatsScopeProject
+)
+
+var statsScopeNames = []string{"Current Page", "With Sub-Pages", "Whole Project"}
+
+// StatsPages returns the Pages the Stats menu covers for the given scope: the current Page, the current Page and its sub-Pages, or every
+// Page in the project.
+func StatsPages(project *Project, scope int) []*Page {
+
+	switch scope {
+
+	case StatsScopeSubpages:
+		return project.CurrentPage.WithSubpages()
+
+	case StatsScopeProject:
+		pages := []*Page{}
+		for _, page := range project.Pages {
+			if page.Valid() {
+				pages = append(pages, page)
+			}
+		}
+		return pages
+
+	}
+
+	return []*Page{project.CurrentPage}
+
+}
+
+// CompletionStats totals the number and completion of a set of Cards.
+type CompletionStats struct {
+	Cards           int
+	Completable     int
+	Completed       int
+	CompletionLevel float32
+	MaxLevel        float32
+	Tracked         time.Duration
+}
+
+// Add adds the Card to the totals.
+func (stats *CompletionStats) Add(card *Card) {
+
+	stats.Cards++
+	stats.Tracked += card.TrackedTime()
+
+	if card.Numberable() {
+
+		stats.Completable++
+		stats.MaxLevel += card.MaximumCompletionLevel()
+		stats.CompletionLevel += card.CompletionLevel()
+
+		if card.Completed() {
+			stats.Completed++
+		}
+
+	}
+
+}
+
+// String returns a summary of the totals (e.g. "3 / 5 completed (60%)"), or just the number of Cards if none of them are complete-able.
+func (stats *CompletionStats) String() string {
+
+	if stats.Completable == 0 {
+		return fmt.Sprintf("%d Card(s)", stats.Cards)
+	}
+
+	return fmt.Sprintf("%d / %d completed (%d%%)", stats.Completed, stats.Completable, int(float32(stats.Completed)/float32(stats.Completable)*100))
+
+}
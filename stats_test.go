@@ -0,0 +1,123 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newStatsTestProject creates a Project with a root Page, a sub-Page of it (the current Page), a sub-Page of that, and an orphaned Page
+// whose sub-Page Card was deleted.
+func newStatsTestProject() (*Project, []*Page) {
+
+	project := &Project{Properties: NewProperties()}
+
+	pages := []*Page{}
+	for i := 0; i < 4; i++ {
+		pages = append(pages, &Page{ID: uint64(i), Project: project})
+	}
+
+	addSubpage := func(page, subpage *Page, valid bool) {
+		card := &Card{Valid: valid, Page: page, ContentType: ContentTypeSubpage, Properties: NewProperties()}
+		card.Contents = &SubPageContents{SubPage: subpage}
+		page.Cards = append(page.Cards, card)
+		subpage.PointingSubpageCard = card
+	}
+
+	addSubpage(pages[0], pages[1], true)
+	addSubpage(pages[1], pages[2], true)
+	addSubpage(pages[0], pages[3], false)
+
+	project.Pages = pages
+	project.CurrentPage = pages[1]
+
+	return project, pages
+
+}
+
+func TestStatsPages(t *testing.T) {
+
+	project, pages := newStatsTestProject()
+
+	tests := []struct {
+		scope int
+		want  []*Page
+	}{
+		{StatsScopePage, []*Page{pages[1]}},
+		{StatsScopeSubpages, []*Page{pages[1], pages[2]}},
+		{StatsScopeProject, []*Page{pages[0], pages[1], pages[2]}},
+	}
+
+	for _, test := range tests {
+
+		ids := []uint64{}
+		for _, page := range StatsPages(project, test.scope) {
+			ids = append(ids, page.ID)
+		}
+
+		wantIDs := []uint64{}
+		for _, page := range test.want {
+			wantIDs = append(wantIDs, page.ID)
+		}
+
+		if !reflect.DeepEqual(ids, wantIDs) {
+			t.Errorf("StatsPages(%s) = pages %v, want pages %v", statsScopeNames[test.scope], ids, wantIDs)
+		}
+
+	}
+
+}
+
+func TestCombinedProgressHistory(t *testing.T) {
+
+	project, pages := newStatsTestProject()
+
+	project.Properties.Get(ProjectProgressHistory).Set(`[
+		{"page": 0, "history": [{"date": "2020-06-01", "total": 10, "completed": 2}, {"date": "2020-06-05", "total": 10, "completed": 6}]},
+		{"page": 1, "history": [{"date": "2020-06-03", "total": 4, "completed": 1}, {"date": "2020-06-05", "total": 4, "completed": 4}]},
+		{"page": 2, "history": [{"date": "2020-06-02", "total": 100, "completed": 100}]}
+	]`)
+
+	combined := CombinedProgressHistory(pages[:2])
+
+	// Each Page counts with its latest snapshot up to each day, and the Pages' current states (they have no complete-able Cards) come last
+	want := []ProgressSnapshot{
+		{progressTestDate(6, 1), 10, 2},
+		{progressTestDate(6, 3), 14, 3},
+		{progressTestDate(6, 5), 14, 10},
+	}
+
+	if len(combined) != len(want)+1 {
+		t.Fatalf("len(CombinedProgressHistory()) = %d, want %d", len(combined), len(want)+1)
+	}
+
+	for i, snapshot := range want {
+		if !reflect.DeepEqual(*combined[i], snapshot) {
+			t.Errorf("CombinedProgressHistory()[%d] = %+v, want %+v", i, *combined[i], snapshot)
+		}
+	}
+
+	if last := combined[len(combined)-1]; last.Total != 0 || last.Completed != 0 {
+		t.Errorf("last combined snapshot = %+v, want no Cards", *last)
+	}
+
+}
+
+func TestCompletionStatsString(t *testing.T) {
+
+	tests := []struct {
+		stats CompletionStats
+		want  string
+	}{
+		{CompletionStats{}, "0 Card(s)"},
+		{CompletionStats{Cards: 4}, "4 Card(s)"},
+		{CompletionStats{Cards: 6, Completable: 5, Completed: 3}, "3 / 5 completed (60%)"},
+		{CompletionStats{Cards: 3, Completable: 3, Completed: 1}, "1 / 3 completed (33%)"},
+	}
+
+	for _, test := range tests {
+		if got := test.stats.String(); got != test.want {
+			t.Errorf("%+v.String() = %q, want %q", test.stats, got, test.want)
+		}
+	}
+
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ncruces/zenity"
+)
+
+// How many rows are measured to find a column's width when importing; this keeps importing large files quick.
+const tableImportMeasuredRows = 100
+
+// parseDelimitedRecords parses comma- or tab-separated text into records. If ragged is false, every record has to have the same number of fields.
+func parseDelimitedRecords(text string, delimiter rune, ragged bool) ([][]string, error) {
+
+	text = strings.TrimPrefix(text, "\ufeff") // Spreadsheet programs like to start CSV files with a byte order mark
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = delimiter
+	reader.LazyQuotes = true
+	if ragged {
+		reader.FieldsPerRecord = -1
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("no data found")
+	}
+
+	return records, nil
+
+}
+
+// tableCheckboxValue returns if a cell's text reads as a checkbox's value, and if so, whether it's checked.
+func tableCheckboxValue(text string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(text)) {
+	case "true", "x", "yes", "[x]":
+		return true, true
+	case "false", "no", "[ ]", "":
+		return true, false
+	}
+	return false, false
+}
+
+// TableDataFromRecords creates a TableData from delimited records, using the first record as the column names. Columns that only hold values
+// like "TRUE" / "FALSE" or "x" become checkbox columns.
+func TableDataFromRecords(records [][]string) *TableData {
+	return tableDataFromRecords(records, func(text string) float32 {
+		return globals.TextRenderer.MeasureText([]rune(text), 1).X
+	})
+}
+
+// tableDataFromRecords creates a TableData from delimited records, sizing the columns to fit their contents using the given function to
+// measure the width of text.
+func tableDataFromRecords(records [][]string, measure func(text string) float32) *TableData {
+
+	gs := globals.GridSize
+
+	data := &TableData{}
+
+	columnCount := 0
+	for _, record := range records {
+		if len(record) > columnCount {
+			columnCount = len(record)
+		}
+	}
+
+	cell := func(record []string, column int) string {
+		if column < len(record) {
+			return strings.TrimSpace(record[column])
+		}
+		return ""
+	}
+
+	for c := 0; c < columnCount; c++ {
+
+		column := &TableColumn{Name: cell(records[0], c)}
+
+		checkbox := false
+
+		for _, record := range records[1:] {
+			value := cell(record, c)
+			if isCheckbox, _ := tableCheckboxValue(value); !isCheckbox {
+				checkbox = false
+				break
+			} else if value != "" {
+				checkbox = true
+			}
+		}
+
+		width := measure(column.Name)
+
+		if checkbox {
+			column.Checkbox = true
+			width = float32(math.Max(float64(width), float64(gs*2)))
+		} else {
+			for r, record := range records[1:] {
+				if r >= tableImportMeasuredRows {
+					break
+				}
+				width = float32(math.Max(float64(width), float64(measure(cell(record, c)))))
+			}
+		}
+
+		column.Width = float32(math.Ceil(float64((width+16)/gs))) * gs
+		column.Width = float32(math.Min(math.Max(float64(column.Width), float64(gs*2)), float64(gs*10)))
+
+		data.Columns = append(data.Columns, column)
+
+	}
+
+	for _, record := range records[1:] {
+
+		cells := make([]string, columnCount)
+
+		for c, column := range data.Columns {
+			if column.Checkbox {
+				if _, checked := tableCheckboxValue(cell(record, c)); checked {
+					cells[c] = "x"
+				}
+			} else {
+				cells[c] = cell(record, c)
+			}
+		}
+
+		data.Rows = append(data.Rows, cells)
+
+	}
+
+	if len(data.Rows) == 0 {
+		data.AddRow()
+	}
+
+	return data
+
+}
+
+// ReadTableFile reads a CSV or TSV file into a TableData. TSV files are recognized by their extension, or by their first line being split by
+// tabs rather than commas.
+func ReadTableFile(filePath string) (*TableData, error) {
+
+	contents, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	text := string(contents)
+
+	delimiter := ','
+
+	ext := strings.ToLower(filepath.Ext(filePath))
+	firstLine := strings.SplitN(text, "\n", 2)[0]
+
+	if ext == ".tsv" || ext == ".tab" || (ext != ".csv" && strings.Count(firstLine, "\t") > strings.Count(firstLine, ",")) {
+		delimiter = '\t'
+	}
+
+	records, err := parseDelimitedRecords(text, delimiter, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return TableDataFromRecords(records), nil
+
+}
+
+// IsTableFile returns if the file is a CSV or TSV file that can be imported into a Table Card.
+func IsTableFile(filePath string) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	return ext == ".csv" || ext == ".tsv" || ext == ".tab"
+}
+
+// ParseTabSeparatedTable parses text copied from a spreadsheet (rows of tab-separated cells) into a TableData, returning false if the text
+// doesn't look like it came from one.
+func ParseTabSeparatedTable(text string) (*TableData, bool) {
+
+	records, ok := tabSeparatedRecords(text)
+	if !ok {
+		return nil, false
+	}
+
+	return TableDataFromRecords(records), true
+
+}
+
+// tabSeparatedRecords parses text copied from a spreadsheet into records, returning false if the text doesn't look like it came from one.
+// The text has to have at least two rows and two columns with something in them, so that tab-indented text (like code) isn't mistaken
+// for a table.
+func tabSeparatedRecords(text string) ([][]string, bool) {
+
+	if !strings.Contains(strings.SplitN(text, "\n", 2)[0], "\t") {
+		return nil, false
+	}
+
+	records, err := parseDelimitedRecords(strings.TrimRight(text, "\r\n"), '\t', false)
+	if err != nil || len(records) < 2 || len(records[0]) < 2 {
+		return nil, false
+	}
+
+	filledColumns := 0
+
+	for c := range records[0] {
+		for _, record := range records {
+			if strings.TrimSpace(record[c]) != "" {
+				filledColumns++
+				break
+			}
+		}
+	}
+
+	if filledColumns < 2 {
+		return nil, false
+	}
+
+	return records, true
+
+}
+
+// SetData replaces the table's contents.
+func (tc *TableContents) SetData(data *TableData) {
+	tc.Data = data
+	tc.save()
+	tc.rebuildNeeded = true
+}
+
+// ImportFile loads a CSV or TSV file into the table. If sync is true, the table is reloaded whenever the file changes.
+func (tc *TableContents) ImportFile(filePath string, sync bool) {
+
+	data, err := ReadTableFile(filePath)
+	if err != nil {
+		globals.EventLog.Log("Could not import [%s] into table: %s", true, filePath, err.Error())
+		return
+	}
+
+	tc.Card.Properties.Get("table file").Set(filePath)
+	tc.Card.Properties.Get("table file sync").Set(sync)
+
+	if modified, err := tableFileModified(filePath); err == nil {
+		tc.Card.Properties.Get("table file modified").Set(modified)
+	}
+
+	tc.SetData(data)
+
+	globals.EventLog.Log("Imported %d rows from [%s] into table.", false, len(data.Rows), filepath.Base(filePath))
+
+}
+
+// BrowseForFile asks the user for a CSV or TSV file to import into the table.
+func (tc *TableContents) BrowseForFile() {
+
+	filePath, err := zenity.SelectFile(zenity.Title("Select CSV or TSV file..."), zenity.FileFilters{{Name: "Spreadsheet files", Patterns: []string{"*.csv", "*.tsv", "*.tab"}}})
+	if err != nil {
+		if err != zenity.ErrCanceled {
+			globals.EventLog.Log(err.Error(), false)
+		}
+	} else {
+		tc.ImportFile(filePath, false)
+	}
+
+}
+
+// tableFileModified returns the file's modification time, formatted as it's saved in a Table Card's "table file modified" property.
+func tableFileModified(filePath string) (string, error) {
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	return info.ModTime().Format(time.RFC3339Nano), nil
+
+}
+
+// syncWithFile reloads the table if it's set to stay in sync with its file and the file has changed.
+func (tc *TableContents) syncWithFile() {
+
+	filePath := tc.Card.Properties.Get("table file").AsString()
+
+	if filePath == "" || !tc.Card.Properties.Get("table file sync").AsBool() || time.Since(tc.lastFileCheck) < time.Second {
+		return
+	}
+
+	tc.lastFileCheck = time.Now()
+
+	// The file's modification time is saved with the table so that the table isn't reloaded (losing any changes made to it since) when
+	// the project's opened again, unless the file's actually changed
+	modified, err := tableFileModified(filePath)
+	if err != nil || modified == tc.Card.Properties.Get("table file modified").AsString() {
+		return
+	}
+
+	tc.Card.Properties.Get("table file modified").Set(modified)
+
+	data, err := ReadTableFile(filePath)
+	if err != nil {
+		globals.EventLog.Log("Could not reload [%s] into table: %s", false, filePath, err.Error())
+		return
+	}
+
+	// Columns keep their widths if they're still there
+	for _, column := range data.Columns {
+		for _, existing := range tc.Data.Columns {
+			if existing.Name == column.Name {
+				column.Width = existing.Width
+				break
+			}
+		}
+	}
+
+	if data.Serialize() != tc.serializedData {
+		tc.SetData(data)
+		globals.EventLog.Log("Reloaded table from [%s].", false, filepath.Base(filePath))
+	}
+
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestParseDelimitedRecords(t *testing.T) {
+
+	tests := []struct {
+		text      string
+		delimiter rune
+		ragged    bool
+		want      [][]string
+	}{
+		{"a,b\nc,d", ',', false, [][]string{{"a", "b"}, {"c", "d"}}},
+		{"a,b\r\nc,d\r\n", ',', false, [][]string{{"a", "b"}, {"c", "d"}}},
+		{"\ufeffName,Done\nTask,TRUE", ',', false, [][]string{{"Name", "Done"}, {"Task", "TRUE"}}},
+		{"\"a, b\",\"line\nbreak\"\n\"say \"\"hi\"\"\",c", ',', false, [][]string{{"a, b", "line\nbreak"}, {"say \"hi\"", "c"}}},
+		{"a \"quoted\" word,b", ',', false, [][]string{{"a \"quoted\" word", "b"}}},
+		{"a\tb,c\nd\te", '\t', false, [][]string{{"a", "b,c"}, {"d", "e"}}},
+		{"a,b,c\nd", ',', true, [][]string{{"a", "b", "c"}, {"d"}}},
+	}
+
+	for _, test := range tests {
+
+		records, err := parseDelimitedRecords(test.text, test.delimiter, test.ragged)
+
+		if err != nil {
+			t.Errorf("parseDelimitedRecords(%q) returned error: %s", test.text, err)
+		} else if !reflect.DeepEqual(records, test.want) {
+			t.Errorf("parseDelimitedRecords(%q) = %q, want %q", test.text, records, test.want)
+		}
+
+	}
+
+	for _, text := range []string{"", "\ufeff", "a,b,c\nd"} {
+		if _, err := parseDelimitedRecords(text, ',', false); err == nil {
+			t.Errorf("parseDelimitedRecords(%q) should have returned an error", text)
+		}
+	}
+
+}
+
+func TestTableCheckboxValue(t *testing.T) {
+
+	tests := []struct {
+		text       string
+		isCheckbox bool
+		checked    bool
+	}{
+		{"TRUE", true, true},
+		{"true", true, true},
+		{" x ", true, true},
+		{"X", true, true},
+		{"Yes", true, true},
+		{"[x]", true, true},
+		{"FALSE", true, false},
+		{"no", true, false},
+		{"[ ]", true, false},
+		{"", true, false},
+		{"  ", true, false},
+		{"done", false, false},
+		{"1", false, false},
+		{"xx", false, false},
+	}
+
+	for _, test := range tests {
+		if isCheckbox, checked := tableCheckboxValue(test.text); isCheckbox != test.isCheckbox || checked != test.checked {
+			t.Errorf("tableCheckboxValue(%q) = %v, %v, want %v, %v", test.text, isCheckbox, checked, test.isCheckbox, test.checked)
+		}
+	}
+
+}
+
+func TestTableDataFromRecords(t *testing.T) {
+
+	gs := globals.GridSize
+
+	// Every character is a quarter of a grid space wide
+	measure := func(text string) float32 {
+		return float32(utf8.RuneCountInString(text)) * gs / 4
+	}
+
+	data := tableDataFromRecords([][]string{
+		{"Task", " Done ", "Notes", "Empty", "Maybe"},
+		{" Write tests ", "TRUE", "", "", "x"},
+		{"Run them", "", "These notes are long enough that the column is as wide as columns can be when they're imported"},
+		{"Fix them", "x", "short", "", "sometimes"},
+	}, measure)
+
+	wantColumns := []TableColumn{
+		{Name: "Task", Width: gs * 4},
+		{Name: "Done", Width: gs * 3, Checkbox: true},
+		{Name: "Notes", Width: gs * 10},
+
+		// Columns only become checkbox columns if they have a checkbox value in them, and all of their values are checkbox values
+		{Name: "Empty", Width: gs * 2},
+		{Name: "Maybe", Width: gs * 3},
+	}
+
+	columns := []TableColumn{}
+	for _, column := range data.Columns {
+		columns = append(columns, *column)
+	}
+
+	if !reflect.DeepEqual(columns, wantColumns) {
+		t.Errorf("columns = %+v, want %+v", columns, wantColumns)
+	}
+
+	// Cells are trimmed, missing cells are empty, and checkbox cells are "x" when they're checked
+	wantRows := [][]string{
+		{"Write tests", "x", "", "", "x"},
+		{"Run them", "", "These notes are long enough that the column is as wide as columns can be when they're imported", "", ""},
+		{"Fix them", "x", "short", "", "sometimes"},
+	}
+
+	if !reflect.DeepEqual(data.Rows, wantRows) {
+		t.Errorf("rows = %q, want %q", data.Rows, wantRows)
+	}
+
+	// Tables with only column names get an empty row to fill in
+	data = tableDataFromRecords([][]string{{"A", "B"}}, measure)
+	if !reflect.DeepEqual(data.Rows, [][]string{{"", ""}}) {
+		t.Errorf("rows of a table without any = %q, want one empty row", data.Rows)
+	}
+
+}
+
+func TestTabSeparatedRecords(t *testing.T) {
+
+	tests := []struct {
+		text string
+		want [][]string
+	}{
+		{"Name\tDone\nTask\tTRUE", [][]string{{"Name", "Done"}, {"Task", "TRUE"}}},
+		{"a\tb\r\nc\td\r\n", [][]string{{"a", "b"}, {"c", "d"}}},
+		{"a\t\n\tb\n", [][]string{{"a", ""}, {"", "b"}}},
+
+		// Pasted text that doesn't look like it came from a spreadsheet isn't a table
+		{"no tabs\nat all", nil},
+		{"one\trow", nil},
+		{"one\trow\n", nil},
+		{"\tfunc() {\n\t\treturn\n\t}", nil},
+		{"\tfoo()\n\tbar()", nil},
+		{"a\t\nb\t", nil},
+		{"a\tb\nc", nil},
+		{"text\nwith\ta tab later", nil},
+	}
+
+	for _, test := range tests {
+
+		records, ok := tabSeparatedRecords(test.text)
+
+		if ok != (test.want != nil) {
+			t.Errorf("tabSeparatedRecords(%q) returned %v, want %v", test.text, ok, test.want != nil)
+		} else if ok && !reflect.DeepEqual(records, test.want) {
+			t.Errorf("tabSeparatedRecords(%q) = %q, want %q", test.text, records, test.want)
+		}
+
+	}
+
+}
+
+func TestIsTableFile(t *testing.T) {
+
+	tests := []struct {
+		filePath string
+		want     bool
+	}{
+		{"tasks.csv", true},
+		{"/home/user/Tasks.CSV", true},
+		{"C:\\data\\tasks.tsv", true},
+		{"tasks.tab", true},
+		{"tasks.txt", false},
+		{"csv", false},
+		{"tasks.csv.bak", false},
+	}
+
+	for _, test := range tests {
+		if got := IsTableFile(test.filePath); got != test.want {
+			t.Errorf("IsTableFile(%q) = %v, want %v", test.filePath, got, test.want)
+		}
+	}
+
+}
+
+func TestTableFileModified(t *testing.T) {
+
+	filePath := filepath.Join(t.TempDir(), "tasks.csv")
+
+	if _, err := tableFileModified(filePath); err == nil {
+		t.Errorf("tableFileModified() of a missing file should have returned an error")
+	}
+
+	if err := os.WriteFile(filePath, []byte("Task,Done\nWrite tests,TRUE\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	modTime := time.Date(2026, 10, 19, 12, 30, 15, 123456789, time.Local)
+	if err := os.Chtimes(filePath, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	modified, err := tableFileModified(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The saved time is compared as text after the project's reopened, so it has to read back as the same time
+	// (Some file systems don't store times as precisely as others, so it only has to be within a second)
+	if parsed, err := time.Parse(time.RFC3339Nano, modified); err != nil || parsed.Sub(modTime) > time.Second || modTime.Sub(parsed) > time.Second {
+		t.Errorf("tableFileModified() = %q, which doesn't parse back to %s", modified, modTime)
+	}
+
+	if again, _ := tableFileModified(filePath); again != modified {
+		t.Errorf("tableFileModified() changed from %q to %q without the file changing", modified, again)
+	}
+
+	later := modTime.Add(time.Second * 5)
+	if err := os.Chtimes(filePath, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if changed, _ := tableFileModified(filePath); changed == modified {
+		t.Errorf("tableFileModified() = %q after the file changed", changed)
+	}
+
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// TagSeparator is used to split and join the tags stored in a Card's "tags" property.
+const TagSeparator = ","
+
+// ParseTags splits a comma-separated string of tags into a list, trimming whitespace and dropping empty or duplicate (case-insensitively) entries.
+func ParseTags(text string) []string {
+
+	tags := []string{}
+	existing := map[string]bool{}
+
+	for _, tag := range strings.Split(text, TagSeparator) {
+
+		tag = strings.TrimSpace(tag)
+
+		if tag == "" || existing[strings.ToLower(tag)] {
+			continue
+		}
+
+		existing[strings.ToLower(tag)] = true
+		tags = append(tags, tag)
+
+	}
+
+	return tags
+
+}
+
+// TagsToString joins a list of tags back into the form stored in a Card's "tags" property.
+func TagsToString(tags []string) string {
+	return strings.Join(tags, TagSeparator+" ")
+}
+
+// TagsContain returns if the list of tags contains the given tag; tags are compared case-insensitively.
+func TagsContain(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// TagSuggestions is a row of buttons that offers to complete the tag currently being typed into a Label using the tags already used in the project.
+type TagSuggestions struct {
+	Row     *ContainerRow
+	Label   *Label
+	Buttons []*Button
+}
+
+const tagSuggestionCount = 4
+
+// NewTagSuggestions creates a row of tag suggestions for the provided Label and adds it to the given Container; TagSuggestions.Update() should be called
+// from the Container's OnUpdate() function to keep the suggestions current.
+func NewTagSuggestions(container *Container, label *Label) *TagSuggestions {
+
+	ts := &TagSuggestions{
+		Row:   container.AddRow(AlignCenter),
+		Label: label,
+	}
+
+	ts.Row.ExpandAllElements = true
+	ts.Row.Visible = false
+
+	for i := 0; i < tagSuggestionCount; i++ {
+
+		var button *Button
+		button = NewButton("", &sdl.FRect{0, 0, 96, 32}, nil, false, func() {
+
+			tags := strings.Split(ts.Label.TextAsString(), TagSeparator)
+			tags[len(tags)-1] = button.Label.TextAsString()
+			text := strings.TrimSpace(TagsToString(ParseTags(strings.Join(tags, TagSeparator)))) + TagSeparator + " "
+			ts.Label.SetText([]rune(text))
+			ts.Label.Selection.SelectEnd()
+
+		})
+
+		ts.Buttons = append(ts.Buttons, button)
+		ts.Row.Add("", button)
+
+	}
+
+	return ts
+
+}
+
+// Update refreshes the suggested tags according to the last (partially typed) tag in the Label.
+func (ts *TagSuggestions) Update() {
+
+	tags := strings.Split(ts.Label.TextAsString(), TagSeparator)
+	prefix := strings.ToLower(strings.TrimSpace(tags[len(tags)-1]))
+
+	suggestions := []string{}
+
+	if prefix != "" && globals.Project != nil {
+
+		for _, tag := range globals.Project.Tags() {
+
+			if len(suggestions) >= len(ts.Buttons) {
+				break
+			}
+
+			if lower := strings.ToLower(tag); strings.HasPrefix(lower, prefix) && lower != prefix {
+				suggestions = append(suggestions, tag)
+			}
+
+		}
+
+	}
+
+	for i, button := range ts.Buttons {
+		if i < len(suggestions) {
+			button.Label.SetText([]rune(suggestions[i]))
+			button.Disabled = false
+		} else {
+			button.Label.SetText([]rune(""))
+			button.Disabled = true
+		}
+	}
+
+	ts.Row.Visible = len(suggestions) > 0
+
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{}},
+		{"work", []string{"work"}},
+		{"work, home", []string{"work", "home"}},
+		{"  work ,home  ,", []string{"work", "home"}},
+		{",, ,", []string{}},
+		{"Work, work, WORK, home", []string{"Work", "home"}},
+		{"two words, tag", []string{"two words", "tag"}},
+	}
+
+	for _, test := range tests {
+		if got := ParseTags(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseTags(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+
+}
+
+func TestTagsToStringRoundTrip(t *testing.T) {
+
+	tags := []string{"work", "two words", "home"}
+
+	if got := ParseTags(TagsToString(tags)); !reflect.DeepEqual(got, tags) {
+		t.Errorf("ParseTags(TagsToString(%q)) = %q", tags, got)
+	}
+
+}
+
+func TestTagsContain(t *testing.T) {
+
+	tags := []string{"Work", "home"}
+
+	tests := []struct {
+		tag  string
+		want bool
+	}{
+		{"Work", true},
+		{"work", true},
+		{"HOME", true},
+		{"hom", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if got := TagsContain(tags, test.tag); got != test.want {
+			t.Errorf("TagsContain(%q, %q) = %v, want %v", tags, test.tag, got, test.want)
+		}
+	}
+
+}
@@ -1,15 +1,69 @@
 package main
 
 import (
+	"bytes"
 	"math"
 	"strings"
 
 	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
 )
 
+// TextStyle is a set of flags describing how a run of text should be rendered.
+type TextStyle int
+
+const (
+	TextStyleBold TextStyle = 1 << iota
+	TextStyleItalic
+	TextStyleUnderline
+	TextStyleStrikethrough
+	TextStyleCode      // Drawn over a highlight
+	TextStyleCodeBlock // The entire line is drawn over a highlight
+	TextStyleQuote     // A bar is drawn to the left of the line
+	TextStyleMonospace // Rendered using the monospace font
+)
+
+// How many spaces wide a tab character is drawn.
+const TextTabWidth = 4
+
+// fontStyle returns the TTF font style necessary to render glyphs in the given TextStyle; the other styles are drawn by the TextRenderer.
+func (style TextStyle) fontStyle() int {
+	fs := ttf.STYLE_NORMAL
+	if style&TextStyleBold > 0 {
+		fs |= ttf.STYLE_BOLD
+	}
+	if style&TextStyleItalic > 0 {
+		fs |= ttf.STYLE_ITALIC
+	}
+	return fs
+}
+
+// TextIconRune is the placeholder character for each icon drawn by a TextRun.
+const TextIconRune = '\uFFFC'
+
+// TextRun is a run of text rendered in a single style; if Link is set, the run links to that URL. If Color is set, the run is drawn in that
+// color, rather than in the color the resulting image is drawn in. If Icon is set, each character of the run is drawn as that icon from the
+// GUI texture instead.
+type TextRun struct {
+	Text  string
+	Style TextStyle
+	Link  string
+	Color Color
+	Icon  *sdl.Rect
+}
+
+// TextLink is the area a linked TextRun covers within a TextRendererResult's image.
+type TextLink struct {
+	Rect *sdl.FRect
+	URL  string
+}
+
 type Glyph struct {
-	Rune  rune
-	Image Image
+	Rune      rune
+	Style     int  // The TTF font style the glyph is rendered in
+	Monospace bool // Whether the glyph is rendered using the monospace font
+	Colored   bool // Whether the glyph has colors of its own (i.e. a color emoji) rather than taking on the color it's drawn in
+	Image     Image
 }
 
 func (glyph *Glyph) Texture() *sdl.Texture {
@@ -18,9 +72,22 @@ func (glyph *Glyph) Texture() *sdl.Texture {
 		return glyph.Image.Texture
 	}
 
-	surf, err := globals.Font.RenderUTF8Shaded(string(glyph.Rune), sdl.Color{255, 255, 255, 255}, sdl.Color{0, 0, 0, 255})
+	font := globals.Font
 
-	if err != nil {
+	if glyph.Monospace && globals.MonospaceFont != nil {
+		font = globals.MonospaceFont
+	}
+
+	text := string(glyph.Rune)
+
+	// Tabs are drawn as a run of spaces
+	if glyph.Rune == '\t' {
+		text = strings.Repeat(" ", TextTabWidth)
+	}
+
+	surf, blended := globals.TextRenderer.renderGlyph(font, glyph.Style, text)
+
+	if surf == nil {
 		// If there's an error rendering a glyph, we just assume it doesn't exist in the fontset
 		return nil
 	}
@@ -36,15 +103,42 @@ func (glyph *Glyph) Texture() *sdl.Texture {
 
 	pixels := newSurf.Pixels()
 
-	for y := 0; y < int(surf.H); y++ {
-		for x := 0; x < int(surf.W); x++ {
-			// Format seems to be AGBR, not RGBA?
-			i := int32(y)*newSurf.Pitch + int32(x)*int32(newSurf.Format.BytesPerPixel)
+	// Format seems to be AGBR, not RGBA?
+	pixelIndex := func(x, y int) int32 {
+		return int32(y)*newSurf.Pitch + int32(x)*int32(newSurf.Format.BytesPerPixel)
+	}
 
-			// This would be to get the color unmodified.
-			// return color.RGBA{pixels[i+3], pixels[i+2], pixels[i+1], pixels[i]}
-			newSurf.Set(x, y, sdl.RGBA8888{0xff, 0xff, 0xff, pixels[i+3]})
+	// Blended glyphs come from fallback fonts, which might be color fonts; colored glyphs are left as they are
+	glyph.Colored = false
+
+	if blended {
+		for y := 0; y < int(surf.H) && !glyph.Colored; y++ {
+			for x := 0; x < int(surf.W); x++ {
+				i := pixelIndex(x, y)
+				if pixels[i] > 0 && (pixels[i+1] != pixels[i+2] || pixels[i+2] != pixels[i+3]) {
+					glyph.Colored = true
+					break
+				}
+			}
+		}
+	}
+
+	if !glyph.Colored {
+
+		for y := 0; y < int(surf.H); y++ {
+			for x := 0; x < int(surf.W); x++ {
+				i := pixelIndex(x, y)
+
+				// This would be to get the color unmodified.
+				// return color.RGBA{pixels[i+3], pixels[i+2], pixels[i+1], pixels[i]}
+				if blended {
+					newSurf.Set(x, y, sdl.RGBA8888{0xff, 0xff, 0xff, pixels[i]})
+				} else {
+					newSurf.Set(x, y, sdl.RGBA8888{0xff, 0xff, 0xff, pixels[i+3]})
+				}
+			}
 		}
+
 	}
 
 	// newSurf.SetBlendMode(sdl.BLENDMODE_ADD)
@@ -93,6 +187,9 @@ type TextRendererResult struct {
 	LineSizes       []Point
 	TextSize        Point
 	AlignmentOffset Point
+	Links           []TextLink
+	Colored         bool  // Whether any of the text was rendered in its own color
+	FontColor       Color // The color text without a color of its own was rendered in if the result is Colored
 }
 
 func (trr *TextRendererResult) Destroy() {
@@ -102,16 +199,125 @@ func (trr *TextRendererResult) Destroy() {
 	}
 }
 
+type styledGlyphKey struct {
+	Rune      rune
+	Style     int
+	Monospace bool
+}
+
+// missingGlyphKey identifies how a font renders characters it doesn't have a glyph for.
+type missingGlyphKey struct {
+	Font    *ttf.Font
+	Style   int
+	Blended bool
+}
+
+type missingGlyph struct {
+	W, H   int32
+	Pixels []byte
+}
+
 type TextRenderer struct {
-	Glyphs map[rune]*Glyph
+	Glyphs        map[rune]*Glyph
+	StyledGlyphs  map[styledGlyphKey]*Glyph
+	missingGlyphs map[missingGlyphKey]*missingGlyph
 }
 
 func NewTextRenderer() *TextRenderer {
 	return &TextRenderer{
-		Glyphs: map[rune]*Glyph{},
+		Glyphs:        map[rune]*Glyph{},
+		StyledGlyphs:  map[styledGlyphKey]*Glyph{},
+		missingGlyphs: map[missingGlyphKey]*missingGlyph{},
 	}
 }
 
+// renderText renders text using the given font and style. Blended text is rendered with transparency, which keeps the colors of color
+// fonts; otherwise, it's rendered shaded (white on black).
+func renderText(font *ttf.Font, style int, text string, blended bool) *sdl.Surface {
+
+	if style != ttf.STYLE_NORMAL {
+		prevStyle := font.GetStyle()
+		font.SetStyle(style)
+		defer font.SetStyle(prevStyle)
+	}
+
+	var surf *sdl.Surface
+	var err error
+
+	if blended {
+		surf, err = font.RenderUTF8Blended(text, sdl.Color{255, 255, 255, 255})
+	} else {
+		surf, err = font.RenderUTF8Shaded(text, sdl.Color{255, 255, 255, 255}, sdl.Color{0, 0, 0, 255})
+	}
+
+	if err != nil {
+		return nil
+	}
+
+	return surf
+
+}
+
+// isMissingGlyph returns if the rendered text is the glyph the font draws for characters it doesn't have (usually a box).
+func (tr *TextRenderer) isMissingGlyph(font *ttf.Font, style int, blended bool, surf *sdl.Surface) bool {
+
+	key := missingGlyphKey{Font: font, Style: style, Blended: blended}
+
+	missing, exists := tr.missingGlyphs[key]
+
+	if !exists {
+
+		missing = &missingGlyph{W: -1, H: -1}
+
+		// U+FFFF is guaranteed not to be a character, so no font has a glyph for it
+		if missingSurf := renderText(font, style, "\uFFFF", blended); missingSurf != nil {
+			missing.W = missingSurf.W
+			missing.H = missingSurf.H
+			missing.Pixels = append([]byte{}, missingSurf.Pixels()...)
+			missingSurf.Free()
+		}
+
+		tr.missingGlyphs[key] = missing
+
+	}
+
+	return surf.W == missing.W && surf.H == missing.H && bytes.Equal(surf.Pixels(), missing.Pixels)
+
+}
+
+// renderGlyph renders the text using the given font, or if the font doesn't have a glyph for it, the first of the fallback fonts that does.
+// Text from fallback fonts is rendered blended, which is returned as well. If no font has the glyph, the font's missing glyph is used.
+func (tr *TextRenderer) renderGlyph(font *ttf.Font, style int, text string) (*sdl.Surface, bool) {
+
+	surf := renderText(font, style, text, false)
+
+	if surf != nil && !tr.isMissingGlyph(font, style, false, surf) {
+		return surf, false
+	}
+
+	for _, fallback := range globals.FallbackFonts {
+
+		fallbackSurf := renderText(fallback, style, text, true)
+
+		if fallbackSurf == nil {
+			continue
+		}
+
+		if !tr.isMissingGlyph(fallback, style, true, fallbackSurf) {
+			if surf != nil {
+				surf.Free()
+			}
+			return fallbackSurf, true
+		}
+
+		fallbackSurf.Free()
+
+	}
+
+	return surf, false
+
+}
+
 func (tr *TextRenderer) Glyph(char rune) *Glyph {
 
 	glyph, exists := tr.Glyphs[char]
@@ -131,6 +337,34 @@ func (tr *TextRenderer) Glyph(char rune) *Glyph {
 
 }
 
+// StyledGlyph returns the glyph for the given character rendered in the given style (i.e. bold, italic, or monospace).
+func (tr *TextRenderer) StyledGlyph(char rune, style TextStyle) *Glyph {
+
+	fontStyle := style.fontStyle()
+	monospace := style&TextStyleMonospace > 0
+
+	if fontStyle == ttf.STYLE_NORMAL && !monospace {
+		return tr.Glyph(char)
+	}
+
+	key := styledGlyphKey{Rune: char, Style: fontStyle, Monospace: monospace}
+
+	glyph, exists := tr.StyledGlyphs[key]
+
+	if !exists {
+
+		glyph = &Glyph{Rune: char, Style: fontStyle, Monospace: monospace}
+		if glyph.Texture() == nil {
+			return nil
+		}
+		tr.StyledGlyphs[key] = glyph
+
+	}
+
+	return glyph
+
+}
+
 func (tr *TextRenderer) GlyphsForRunes(word []rune) []*Glyph {
 	glyphs := []*Glyph{}
 	for _, char := range word {
@@ -239,14 +473,45 @@ func (tr *TextRenderer) MeasureTextAutowrap(maxWidth float32, text string) Point
 }
 
 func (tr *TextRenderer) RenderText(text string, maxSize Point, horizontalAlignment string) *TextRendererResult {
+	return tr.RenderStyledText([]TextRun{{Text: text}}, maxSize, horizontalAlignment)
+}
+
+// RenderStyledText renders the given runs of text as a single block, wrapping lines to fit within maxSize's width if it's greater than 0.
+func (tr *TextRenderer) RenderStyledText(runs []TextRun, maxSize Point, horizontalAlignment string) *TextRendererResult {
+
+	type styledRune struct {
+		Rune  rune
+		Style TextStyle
+		Link  string
+		Color Color
+		Icon  *sdl.Rect
+	}
+
+	text := []styledRune{}
 
 	result := &TextRendererResult{}
 
+	for _, run := range runs {
+		if run.Color != nil {
+			result.Colored = true
+		}
+		for _, c := range run.Text {
+			// Color glyphs (i.e. emoji) are drawn in their own colors, so the rest of the text has to be colored in as well
+			if glyph := tr.StyledGlyph(c, run.Style); run.Icon == nil && glyph != nil && glyph.Colored {
+				result.Colored = true
+			}
+			text = append(text, styledRune{Rune: c, Style: run.Style, Link: run.Link, Color: run.Color, Icon: run.Icon})
+		}
+	}
+
 	renderTexture := NewRenderTexture()
 
 	result.Image = renderTexture
 
 	renderTexture.RenderFunc = func() {
+
+		result.FontColor = getThemeColor(GUIFontColor)
+
 		hint := sdl.GetHint(sdl.HINT_RENDER_SCALE_QUALITY)
 		sdl.SetHint(sdl.HINT_RENDER_SCALE_QUALITY, "2")
 		finalW := globals.GridSize
@@ -256,56 +521,63 @@ func (tr *TextRenderer) RenderText(text string, maxSize Point, horizontalAlignme
 
 		result.TextLines = [][]rune{}
 		result.LineSizes = []Point{}
+		result.Links = []TextLink{}
 
 		line := []rune{}
+		lineStyle := TextStyle(0)
+		lineStyles := []TextStyle{}
 
 		type renderPair struct {
 			Glyph *Glyph
+			Icon  *sdl.Rect
 			Rect  *sdl.Rect
+			Style TextStyle
+			Link  string
+			Color Color
 		}
 
 		toRender := []*renderPair{}
 
+		endLine := func() {
+			result.LineSizes = append(result.LineSizes, Point{float32(x), globals.GridSize})
+			lineStyles = append(lineStyles, lineStyle)
+			lineStyle = 0
+			x = 0
+			y += int(globals.GridSize)
+			toRender = append(toRender, nil)
+		}
+
 		for i, c := range text {
 
-			line = append(line, c)
+			line = append(line, c.Rune)
+			lineStyle |= c.Style
 
-			if c == '\n' {
-				x = 0
-				y += int(globals.GridSize)
+			if c.Rune == '\n' {
+				endLine()
 				result.TextLines = append(result.TextLines, line)
 				line = []rune{}
-				toRender = append(toRender, nil)
 				continue
 			} else {
 
-				if c == ' ' && maxSize.X > 0 {
-
-					end := strings.IndexAny(text[i+1:], " \n")
-					if len(text)-i < end || end < 0 {
-						end = len(text) - i
-					}
-
-					nextStart := i
-					nextEnd := nextStart + end + 1
+				if c.Rune == ' ' && maxSize.X > 0 {
 
-					if nextStart > len(text) {
-						nextStart = len(text)
-					}
+					// Measure the space and the word following it
+					wordWidth := int32(0)
 
-					if nextEnd > len(text) {
-						nextEnd = len(text)
+					for j := i; j < len(text); j++ {
+						if j > i && (text[j].Rune == ' ' || text[j].Rune == '\n') {
+							break
+						}
+						if text[j].Icon != nil {
+							wordWidth += int32(globals.GridSize)
+						} else if glyph := tr.StyledGlyph(text[j].Rune, text[j].Style); glyph != nil {
+							wordWidth += glyph.Width()
+						}
 					}
 
-					nextWord := text[nextStart:nextEnd]
-
-					wordWidth := int32(tr.MeasureText([]rune(nextWord), 1).X)
-
 					if float32(x+int(wordWidth)) > maxSize.X {
 
-						x = 0
-						y += int(globals.GridSize)
-						toRender = append(toRender, nil)
+						endLine()
 						line = append(line[:len(line)-1], '\n') // Swap out the space for a newline character
 						result.TextLines = append(result.TextLines, line)
 						line = []rune{}
@@ -317,19 +589,38 @@ func (tr *TextRenderer) RenderText(text string, maxSize Point, horizontalAlignme
 
 			}
 
-			glyph := tr.Glyph(c)
-			if glyph == nil {
-				continue
+			var glyph *Glyph
+			rect := &sdl.Rect{int32(x), int32(y), int32(globals.GridSize), int32(globals.GridSize)}
+
+			if c.Icon == nil {
+				glyph = tr.StyledGlyph(c.Rune, c.Style)
+				if glyph == nil {
+					continue
+				}
+				rect.W = glyph.Width()
+				rect.H = glyph.Height()
+			}
+
+			color := c.Color
+			if glyph != nil && glyph.Colored {
+				color = ColorWhite
+			} else if color == nil {
+				color = ColorWhite
+				if result.Colored {
+					color = result.FontColor
+				}
 			}
-			glyph.Texture().SetColorMod(255, 255, 255)
-			glyph.Texture().SetAlphaMod(255)
 
 			toRender = append(toRender, &renderPair{
 				Glyph: glyph,
-				Rect:  &sdl.Rect{int32(x), int32(y), glyph.Width(), glyph.Height()},
+				Icon:  c.Icon,
+				Rect:  rect,
+				Style: c.Style,
+				Link:  c.Link,
+				Color: color,
 			})
 
-			x += int(glyph.Width())
+			x += int(rect.W)
 			if float32(x) > finalW {
 				finalW = float32(x)
 			}
@@ -338,26 +629,21 @@ func (tr *TextRenderer) RenderText(text string, maxSize Point, horizontalAlignme
 
 		sdl.SetHint(sdl.HINT_RENDER_SCALE_QUALITY, hint)
 		result.TextLines = append(result.TextLines, line)
+		result.LineSizes = append(result.LineSizes, Point{float32(x), globals.GridSize})
+		lineStyles = append(lineStyles, lineStyle)
 		result.TextSize.X = finalW
 		result.TextSize.Y = float32(math.Max(float64(globals.GridSize), float64(len(result.TextLines)*int(globals.GridSize))))
 
-		maxLineWidth := float32(0)
-
-		for _, l := range result.TextLines {
-			size := tr.MeasureText(l, 1)
-			result.LineSizes = append(result.LineSizes, size)
-			if maxLineWidth < size.X {
-				maxLineWidth = size.X
-			}
-		}
-
 		lineIndex := 0
 
 		var lw int32
 
+		var link *TextLink
+
 		for _, ch := range toRender {
 			if ch == nil {
 				lineIndex++
+				link = nil
 				continue
 			}
 
@@ -369,6 +655,19 @@ func (tr *TextRenderer) RenderText(text string, maxSize Point, horizontalAlignme
 
 			ch.Rect.X += lw
 
+			// Neighboring characters in the same link on the same line share a single link rectangle
+			if ch.Link == "" {
+				link = nil
+			} else if link != nil && link.URL == ch.Link {
+				link.Rect.W = float32(ch.Rect.X+ch.Rect.W) - link.Rect.X
+			} else {
+				result.Links = append(result.Links, TextLink{
+					Rect: &sdl.FRect{float32(ch.Rect.X), float32(ch.Rect.Y), float32(ch.Rect.W), float32(ch.Rect.H)},
+					URL:  ch.Link,
+				})
+				link = &result.Links[len(result.Links)-1]
+			}
+
 		}
 
 		result.AlignmentOffset.X = float32(lw)
@@ -388,11 +687,57 @@ func (tr *TextRenderer) RenderText(text string, maxSize Point, horizontalAlignme
 
 		globals.Renderer.Clear()
 
+		// Highlights and lines are drawn in white as well, so that they take on the color the text is drawn in
+		globals.Renderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
+
+		gs := int32(globals.GridSize)
+
+		for i, style := range lineStyles {
+			if style&TextStyleCodeBlock > 0 {
+				globals.Renderer.SetDrawColor(255, 255, 255, 48)
+				globals.Renderer.FillRect(&sdl.Rect{0, int32(i) * gs, int32(finalW), gs})
+			}
+			if style&TextStyleQuote > 0 {
+				globals.Renderer.SetDrawColor(255, 255, 255, 128)
+				globals.Renderer.FillRect(&sdl.Rect{0, int32(i) * gs, 4, gs})
+			}
+		}
+
+		globals.Renderer.SetDrawColor(255, 255, 255, 48)
+
+		for _, r := range toRender {
+			if r != nil && r.Style&TextStyleCode > 0 && r.Style&TextStyleCodeBlock == 0 {
+				globals.Renderer.FillRect(r.Rect)
+			}
+		}
+
+		for _, r := range toRender {
+			if r == nil {
+				continue
+			}
+			// Glyph textures are shared, so the color has to be set just before drawing each glyph
+			if r.Icon != nil {
+				globals.GUITexture.Texture.SetColorMod(r.Color.RGB())
+				globals.GUITexture.Texture.SetAlphaMod(r.Color[3])
+				globals.Renderer.Copy(globals.GUITexture.Texture, r.Icon, r.Rect)
+			} else {
+				r.Glyph.Texture().SetColorMod(r.Color.RGB())
+				r.Glyph.Texture().SetAlphaMod(r.Color[3])
+				globals.Renderer.Copy(r.Glyph.Texture(), nil, r.Rect)
+			}
+		}
+
 		for _, r := range toRender {
 			if r == nil {
 				continue
 			}
-			globals.Renderer.Copy(r.Glyph.Texture(), nil, r.Rect)
+			globals.Renderer.SetDrawColor(r.Color.RGBA())
+			if r.Style&TextStyleUnderline > 0 {
+				globals.Renderer.FillRect(&sdl.Rect{r.Rect.X, r.Rect.Y + gs - 4, r.Rect.W, 2})
+			}
+			if r.Style&TextStyleStrikethrough > 0 {
+				globals.Renderer.FillRect(&sdl.Rect{r.Rect.X, r.Rect.Y + gs/2, r.Rect.W, 2})
+			}
 		}
 
 	}
@@ -434,7 +779,7 @@ func (tr *TextRenderer) QuickRenderText(text string, pos Point, sizeMultiplier f
 		tex := glyph.Texture()
 		tex.SetBlendMode(sdl.BLENDMODE_BLEND) // We set the blend mode here as well
 
-		if outlineColor != nil {
+		if outlineColor != nil && !glyph.Colored {
 			for y := -1; y <= 1; y++ {
 				for x := -1; x <= 1; x++ {
 					if x == 0 && y == 0 {
@@ -450,7 +795,11 @@ func (tr *TextRenderer) QuickRenderText(text string, pos Point, sizeMultiplier f
 
 		dst := &sdl.FRect{pos.X, pos.Y, float32(glyph.Width()) * sizeMultiplier, float32(glyph.Height()) * sizeMultiplier}
 
-		tex.SetColorMod(color.RGB())
+		if glyph.Colored {
+			tex.SetColorMod(255, 255, 255)
+		} else {
+			tex.SetColorMod(color.RGB())
+		}
 		tex.SetAlphaMod(color[3])
 
 		globals.Renderer.CopyF(tex, nil, dst)
@@ -465,4 +814,9 @@ func (tr *TextRenderer) DestroyGlyphs() {
 		glyph.Destroy()
 	}
 	tr.Glyphs = map[rune]*Glyph{}
+	for _, glyph := range tr.StyledGlyphs {
+		glyph.Destroy()
+	}
+	tr.StyledGlyphs = map[styledGlyphKey]*Glyph{}
+	tr.missingGlyphs = map[missingGlyphKey]*missingGlyph{}
 }
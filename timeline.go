@@ -0,0 +1,515 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	TimelineScaleDays = iota
+	TimelineScaleWeeks
+	TimelineScaleMonths
+)
+
+const (
+	timelineDragNone = iota
+	timelineDragMove
+	timelineDragStart
+	timelineDragEnd
+	timelineDragPan
+)
+
+const (
+	timelineHeaderHeight = 32
+	timelineRowHeight    = 32
+	timelineHandleWidth  = 6
+)
+
+type timelineBar struct {
+	Card  *Card
+	Start time.Time
+	End   time.Time
+	Rect  *sdl.FRect
+}
+
+// Timeline is a Gantt-style MenuElement that draws every complete-able Card with a deadline as a horizontal bar spanning from its start date
+// (if it has one) to its deadline, along with any links between those Cards as dependency arrows. Bars can be dragged to move a Card's dates
+// (or dragged by their ends to change just the start date or deadline), and clicking a bar focuses on its Card.
+type Timeline struct {
+	Rect     *sdl.FRect
+	Scale    int
+	Position float64 // The day at the left edge of the Timeline, counted in days since the Unix epoch
+	Scroll   float32
+
+	bars         []*timelineBar
+	dragMode     int
+	dragBar      *timelineBar
+	dragOrigin   Point
+	dragPosition float64
+	dragScroll   float32
+	dragDays     int
+	dragged      bool
+}
+
+func NewTimeline(rect *sdl.FRect) *Timeline {
+
+	timeline := &Timeline{
+		Rect:  rect,
+		Scale: TimelineScaleDays,
+	}
+
+	timeline.GoToDate(time.Now())
+
+	return timeline
+
+}
+
+// DayWidth returns how wide a single day is on the Timeline, in pixels, according to its scale.
+func (timeline *Timeline) DayWidth() float32 {
+	switch timeline.Scale {
+	case TimelineScaleWeeks:
+		return 8
+	case TimelineScaleMonths:
+		return 2
+	}
+	return 32
+}
+
+// SetScale sets the scale of the Timeline's axis, keeping the date at the center of the Timeline in place.
+func (timeline *Timeline) SetScale(scale int) {
+	center := timeline.Position + float64(timeline.Rect.W/2/timeline.DayWidth())
+	timeline.Scale = scale
+	timeline.Position = center - float64(timeline.Rect.W/2/timeline.DayWidth())
+}
+
+// GoToDate centers the Timeline on the given date.
+func (timeline *Timeline) GoToDate(date time.Time) {
+	timeline.Position = float64(timelineDay(date)) - float64(timeline.Rect.W/2/timeline.DayWidth())
+}
+
+// Move scrolls the Timeline horizontally by the given number of days.
+func (timeline *Timeline) Move(days float64) {
+	timeline.Position += days
+}
+
+func (timeline *Timeline) Update() {
+
+	timeline.bars = timelineBars()
+	timeline.layoutBars()
+
+	pos := globals.Mouse.Position()
+	button := globals.Mouse.Button(sdl.BUTTON_LEFT)
+
+	maxScroll := float32(math.Max(0, float64(float32(len(timeline.bars)*timelineRowHeight)-(timeline.Rect.H-timelineHeaderHeight))))
+
+	if pos.Inside(timeline.Rect) {
+
+		if wheel := globals.Mouse.Wheel(); wheel != 0 {
+			timeline.Scroll -= wheel * timelineRowHeight
+			globals.Mouse.wheel = 0 // Consume the wheel movement
+		}
+
+		hovered, mode := timeline.barAt(pos)
+
+		if mode == timelineDragStart || mode == timelineDragEnd {
+			globals.Mouse.SetCursor(CursorResizeHorizontal)
+		} else if hovered != nil {
+			globals.Mouse.SetCursor(CursorHand)
+		}
+
+		if button.Pressed() {
+			button.Consume()
+			timeline.dragBar = hovered
+			timeline.dragMode = mode
+			timeline.dragOrigin = pos
+			timeline.dragPosition = timeline.Position
+			timeline.dragScroll = timeline.Scroll
+			timeline.dragDays = 0
+			timeline.dragged = false
+		}
+
+	}
+
+	if timeline.dragMode != timelineDragNone {
+
+		delta := pos.Sub(timeline.dragOrigin)
+
+		if math.Abs(float64(delta.X)) > 4 || math.Abs(float64(delta.Y)) > 4 {
+			timeline.dragged = true
+		}
+
+		if timeline.dragMode == timelineDragPan {
+			globals.Mouse.SetCursor(CursorHandGrab)
+			timeline.Position = timeline.dragPosition - float64(delta.X/timeline.DayWidth())
+			timeline.Scroll = timeline.dragScroll - delta.Y
+		} else {
+			timeline.dragDays = int(math.Round(float64(delta.X / timeline.DayWidth())))
+		}
+
+		if !button.HeldRaw() {
+
+			if timeline.dragBar != nil {
+
+				if !timeline.dragged {
+					card := timeline.dragBar.Card
+					card.Page.Project.Camera.FocusOn(false, card)
+					card.Page.Selection.Clear()
+					card.Page.Selection.Add(card)
+				} else if timeline.dragDays != 0 {
+					timeline.applyDrag()
+				}
+
+			}
+
+			timeline.dragMode = timelineDragNone
+			timeline.dragBar = nil
+			timeline.dragDays = 0
+
+		}
+
+	}
+
+	if timeline.Scroll > maxScroll {
+		timeline.Scroll = maxScroll
+	}
+
+	if timeline.Scroll < 0 {
+		timeline.Scroll = 0
+	}
+
+	timeline.layoutBars()
+
+}
+
+func (timeline *Timeline) layoutBars() {
+
+	for i, bar := range timeline.bars {
+		start, end := timeline.barDates(bar)
+		bar.Rect = &sdl.FRect{
+			X: timeline.dateToX(start),
+			Y: timeline.Rect.Y + timelineHeaderHeight + float32(i*timelineRowHeight) - timeline.Scroll + 4,
+			W: float32(timelineDay(end)-timelineDay(start)+1) * timeline.DayWidth(),
+			H: timelineRowHeight - 8,
+		}
+	}
+
+}
+
+func (timeline *Timeline) Draw() {
+
+	rect := &sdl.Rect{int32(timeline.Rect.X), int32(timeline.Rect.Y), int32(timeline.Rect.W), int32(timeline.Rect.H)}
+
+	// Combine our clipping rectangle with the Container's, so we don't draw outside of it
+	if len(globals.ClipRects) > 0 {
+		if clipped, ok := rect.Intersect(globals.ClipRects[len(globals.ClipRects)-1]); ok {
+			rect = &clipped
+		} else {
+			return
+		}
+	}
+
+	globals.Renderer.SetClipRect(rect)
+	globals.ClipRects = append(globals.ClipRects, rect)
+
+	fontColor := getThemeColor(GUIFontColor)
+	menuColor := getThemeColor(GUIMenuColor)
+
+	FillRect(timeline.Rect.X, timeline.Rect.Y, timeline.Rect.W, timeline.Rect.H, getThemeColor(GUIBGColor))
+
+	// Grid lines
+
+	firstDay := int(math.Floor(timeline.Position))
+	lastDay := int(math.Ceil(timeline.Position + float64(timeline.Rect.W/timeline.DayWidth())))
+
+	type tick struct {
+		X     float32
+		Label string
+		Major bool
+	}
+
+	ticks := []tick{}
+
+	for day := firstDay; day <= lastDay; day++ {
+
+		date := timelineDate(day)
+		x := timeline.dateToX(date)
+
+		switch timeline.Scale {
+		case TimelineScaleDays:
+			if date.Day() == 1 {
+				ticks = append(ticks, tick{x, date.Format("Jan 2006"), true})
+			} else {
+				ticks = append(ticks, tick{x, strconv.Itoa(date.Day()), false})
+			}
+		case TimelineScaleWeeks:
+			if date.Weekday() == time.Monday {
+				ticks = append(ticks, tick{x, date.Format("Jan 2"), date.Day() <= 7})
+			}
+		case TimelineScaleMonths:
+			if date.Day() == 1 {
+				ticks = append(ticks, tick{x, date.Format("Jan 2006"), date.Month() == time.January})
+			}
+		}
+
+	}
+
+	for _, t := range ticks {
+		lineColor := fontColor.Clone()
+		lineColor[3] = 32
+		if t.Major {
+			lineColor[3] = 96
+		}
+		FillRect(t.X, timeline.Rect.Y, 1, timeline.Rect.H, lineColor)
+	}
+
+	// Today
+
+	todayColor := priorityColors[PriorityCritical]
+	FillRect(timeline.dateToX(time.Now()), timeline.Rect.Y, 2, timeline.Rect.H, todayColor)
+
+	// Bars
+
+	for _, bar := range timeline.bars {
+
+		card := bar.Card
+
+		color := card.Color()
+		if card.Completed() {
+			color[3] = 96
+		}
+
+		FillRect(bar.Rect.X, bar.Rect.Y, bar.Rect.W, bar.Rect.H, color)
+
+		outlineColor := fontColor
+		if card.DeadlineState() == DeadlineStateOverdue {
+			outlineColor = todayColor
+		}
+
+		if card.selected {
+			ThickRect(int32(bar.Rect.X), int32(bar.Rect.Y), int32(bar.Rect.W), int32(bar.Rect.H), 3, outlineColor)
+		} else {
+			globals.Renderer.SetDrawColor(outlineColor.RGBA())
+			globals.Renderer.DrawRectF(bar.Rect)
+		}
+
+		textColor := ColorBlack
+		if color.IsDark() {
+			textColor = ColorWhite
+		}
+
+		globals.TextRenderer.QuickRenderText(strings.ReplaceAll(card.Name(), "\n", " - "), Point{bar.Rect.X + 4, bar.Rect.Y + 2}, 0.5, textColor, nil, AlignLeft)
+
+	}
+
+	// Dependency arrows, going from the Card each link starts from to the Card it ends at
+
+	barsByCard := map[*Card]*timelineBar{}
+	for _, bar := range timeline.bars {
+		barsByCard[bar.Card] = bar
+	}
+
+	for _, bar := range timeline.bars {
+
+		for _, link := range bar.Card.Links {
+
+			if link.Start != bar.Card {
+				continue
+			}
+
+			target, exists := barsByCard[link.End]
+			if !exists {
+				continue
+			}
+
+			start := Point{bar.Rect.X + bar.Rect.W, bar.Rect.Y + bar.Rect.H/2}
+			end := Point{target.Rect.X, target.Rect.Y + target.Rect.H/2}
+
+			ThickLine(start, start.AddF(8, 0), 2, fontColor)
+			ThickLine(start.AddF(8, 0), end.AddF(-8, 0), 2, fontColor)
+			ThickLine(end.AddF(-8, 0), end, 2, fontColor)
+			ThickLine(end, end.AddF(-6, -6), 2, fontColor)
+			ThickLine(end, end.AddF(-6, 6), 2, fontColor)
+
+		}
+
+	}
+
+	// Header, drawn last so bars scroll underneath it
+
+	FillRect(timeline.Rect.X, timeline.Rect.Y, timeline.Rect.W, timelineHeaderHeight, menuColor)
+
+	for _, t := range ticks {
+		if t.Major || timeline.Scale != TimelineScaleDays {
+			globals.TextRenderer.QuickRenderText(t.Label, Point{t.X + 4, timeline.Rect.Y + 4}, 0.5, fontColor, nil, AlignLeft)
+		} else {
+			globals.TextRenderer.QuickRenderText(t.Label, Point{t.X + timeline.DayWidth()/2, timeline.Rect.Y + 4}, 0.5, fontColor, nil, AlignCenter)
+		}
+	}
+
+	globals.ClipRects[len(globals.ClipRects)-1] = nil
+	globals.ClipRects = globals.ClipRects[:len(globals.ClipRects)-1]
+	if len(globals.ClipRects) > 0 {
+		globals.Renderer.SetClipRect(globals.ClipRects[len(globals.ClipRects)-1])
+	} else {
+		globals.Renderer.SetClipRect(nil)
+	}
+
+}
+
+func (timeline *Timeline) Rectangle() *sdl.FRect {
+	return timeline.Rect
+}
+
+func (timeline *Timeline) SetRectangle(rect *sdl.FRect) {
+	timeline.Rect = rect
+}
+
+func (timeline *Timeline) Destroy() {}
+
+// barAt returns the bar under the given position, along with what dragging it would do.
+func (timeline *Timeline) barAt(pos Point) (*timelineBar, int) {
+
+	if pos.Y < timeline.Rect.Y+timelineHeaderHeight {
+		return nil, timelineDragPan
+	}
+
+	for _, bar := range timeline.bars {
+
+		if bar.Rect == nil || !pos.Inside(bar.Rect) {
+			continue
+		}
+
+		if bar.Rect.W > timelineHandleWidth*3 {
+			if pos.X < bar.Rect.X+timelineHandleWidth {
+				return bar, timelineDragStart
+			} else if pos.X > bar.Rect.X+bar.Rect.W-timelineHandleWidth {
+				return bar, timelineDragEnd
+			}
+		}
+
+		return bar, timelineDragMove
+
+	}
+
+	return nil, timelineDragPan
+
+}
+
+// barDates returns the dates a bar spans, taking into account if it's currently being dragged.
+func (timeline *Timeline) barDates(bar *timelineBar) (time.Time, time.Time) {
+
+	start := bar.Start
+	end := bar.End
+
+	if timeline.dragBar == nil || timeline.dragBar.Card != bar.Card {
+		return start, end
+	}
+
+	switch timeline.dragMode {
+	case timelineDragMove:
+		start = start.AddDate(0, 0, timeline.dragDays)
+		end = end.AddDate(0, 0, timeline.dragDays)
+	case timelineDragStart:
+		start = start.AddDate(0, 0, timeline.dragDays)
+		if start.After(end) {
+			start = end
+		}
+	case timelineDragEnd:
+		end = end.AddDate(0, 0, timeline.dragDays)
+		if end.Before(start) {
+			end = start
+		}
+	}
+
+	return start, end
+
+}
+
+func (timeline *Timeline) applyDrag() {
+
+	card := timeline.dragBar.Card
+	start, end := timeline.barDates(timeline.dragBar)
+
+	_, hasStart := card.StartDate()
+
+	if hasStart || !DatesAreEqual(start, end) {
+		card.Properties.Get("start date").Set(start.Format("2006-01-02"))
+	}
+
+	card.SetDeadlineDate(end)
+
+	// Capture the undo state immediately, as the Card may not be on the current Page
+	card.HandleUndos()
+
+	globals.EventLog.Log("Moved dates for [%s] to %s - %s.", false, card.Name(), start.Format("2006-01-02"), end.Format("2006-01-02"))
+
+}
+
+func (timeline *Timeline) dateToX(date time.Time) float32 {
+	return timeline.Rect.X + float32(float64(timelineDay(date))-timeline.Position)*timeline.DayWidth()
+}
+
+// timelineBars returns bars for all complete-able Cards with deadlines in the current project, sorted by their start dates.
+func timelineBars() []*timelineBar {
+
+	bars := []*timelineBar{}
+
+	if globals.Project == nil {
+		return bars
+	}
+
+	for _, page := range globals.Project.Pages {
+
+		if !page.Valid() {
+			continue
+		}
+
+		for _, card := range page.Cards {
+
+			end, hasDeadline := card.Deadline()
+
+			if !hasDeadline {
+				continue
+			}
+
+			start, hasStart := card.StartDate()
+
+			if !hasStart || start.After(end) {
+				start = end
+			}
+
+			bars = append(bars, &timelineBar{Card: card, Start: start, End: end})
+
+		}
+
+	}
+
+	sort.SliceStable(bars, func(i, j int) bool {
+		if !bars[i].Start.Equal(bars[j].Start) {
+			return bars[i].Start.Before(bars[j].Start)
+		}
+		if !bars[i].End.Equal(bars[j].End) {
+			return bars[i].End.Before(bars[j].End)
+		}
+		return bars[i].Card.ID < bars[j].Card.ID
+	})
+
+	return bars
+
+}
+
+// timelineDay returns the number of days between the Unix epoch and the given date.
+func timelineDay(date time.Time) int64 {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+}
+
+// timelineDate returns the local date that's the given number of days after the Unix epoch.
+func timelineDate(day int) time.Time {
+	date := time.Unix(int64(day)*86400, 0).UTC()
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	TimeReportByPage = iota
+	TimeReportByTag
+	TimeReportByDay
+)
+
+var timeReportGroupNames = []string{"Page", "Tag", "Day"}
+
+// TimeSession is a span of time that was tracked on a Card.
+type TimeSession struct {
+	Start, End time.Time
+}
+
+// parseTimeSessions parses tracked time sessions, stored as comma-separated pairs of Unix timestamps (e.g. "1700000000-1700003600").
+func parseTimeSessions(text string) []TimeSession {
+
+	sessions := []TimeSession{}
+
+	for _, session := range strings.Split(text, ",") {
+
+		times := strings.Split(strings.TrimSpace(session), "-")
+		if len(times) != 2 {
+			continue
+		}
+
+		start, startErr := strconv.ParseInt(times[0], 10, 64)
+		end, endErr := strconv.ParseInt(times[1], 10, 64)
+
+		if startErr == nil && endErr == nil && end >= start {
+			sessions = append(sessions, TimeSession{Start: time.Unix(start, 0), End: time.Unix(end, 0)})
+		}
+
+	}
+
+	return sessions
+
+}
+
+// FormatTrackedTime formats an amount of tracked time as hours, minutes, and seconds (e.g. "1:05:30").
+func FormatTrackedTime(duration time.Duration) string {
+	seconds := int64(duration.Round(time.Second) / time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", seconds/3600, (seconds/60)%60, seconds%60)
+}
+
+// TimeSessions returns the finished sessions of time tracked on the Card.
+func (card *Card) TimeSessions() []TimeSession {
+	if !card.Properties.Has("time sessions") {
+		return []TimeSession{}
+	}
+	return parseTimeSessions(card.Properties.Get("time sessions").AsString())
+}
+
+// TrackingSince returns when time tracking was started on the Card, along with whether time is being tracked on it.
+func (card *Card) TrackingSince() (time.Time, bool) {
+
+	if !card.Properties.Has("tracking since") || !card.Properties.Get("tracking since").IsNumber() {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(card.Properties.Get("tracking since").AsFloat()), 0), true
+
+}
+
+// IsTracking returns if time is currently being tracked on the Card.
+func (card *Card) IsTracking() bool {
+	_, tracking := card.TrackingSince()
+	return tracking
+}
+
+// StartTracking starts tracking time on the Card.
+func (card *Card) StartTracking() {
+	if !card.IsTracking() {
+		card.Properties.Get("tracking since").Set(float64(time.Now().Unix()))
+	}
+}
+
+// StopTracking stops tracking time on the Card, storing the tracked session.
+func (card *Card) StopTracking() {
+
+	since, tracking := card.TrackingSince()
+	if !tracking {
+		return
+	}
+
+	card.AddTimeSession(since, time.Now())
+
+	// The property is emptied rather than removed so that undoing and redoing the change works.
+	card.Properties.Get("tracking since").Set("")
+
+}
+
+// AddTimeSession stores a session of time tracked on the Card.
+func (card *Card) AddTimeSession(start, end time.Time) {
+
+	session := strconv.FormatInt(start.Unix(), 10) + "-" + strconv.FormatInt(end.Unix(), 10)
+
+	if sessions := card.Properties.Get("time sessions").AsString(); sessions != "" {
+		session = sessions + "," + session
+	}
+
+	card.Properties.Get("time sessions").Set(session)
+
+}
+
+// TrackedTime returns the total time tracked on the Card itself, including any session currently being tracked.
+func (card *Card) TrackedTime() time.Duration {
+
+	total := time.Duration(0)
+
+	for _, session := range card.TimeSessions() {
+		total += session.End.Sub(session.Start)
+	}
+
+	if since, tracking := card.TrackingSince(); tracking {
+		total += time.Since(since)
+	}
+
+	return total
+
+}
+
+// TotalTrackedTime returns the time tracked on the Card, along with the time tracked on the Cards in its Stack below it and, for Sub-Page
+// Cards, on the Cards in its sub-page.
+func (card *Card) TotalTrackedTime() time.Duration {
+
+	total := time.Duration(0)
+	visited := map[*Page]bool{card.Page: true}
+
+	for _, c := range append([]*Card{card}, card.Stack.Children()...) {
+		total += c.TrackedTime() + subpageTrackedTime(c, visited)
+	}
+
+	return total
+
+}
+
+// subpageTrackedTime returns the time tracked on the Cards in a Sub-Page Card's sub-page (and its sub-pages, in turn).
+func subpageTrackedTime(card *Card, visited map[*Page]bool) time.Duration {
+
+	subpage, ok := card.Contents.(*SubPageContents)
+	if !ok || subpage.SubPage == nil || visited[subpage.SubPage] {
+		return 0
+	}
+
+	visited[subpage.SubPage] = true
+
+	total := time.Duration(0)
+
+	for _, c := range subpage.SubPage.Cards {
+		if c.Valid {
+			total += c.TrackedTime() + subpageTrackedTime(c, visited)
+		}
+	}
+
+	return total
+
+}
+
+// TimeReportEntry is a row in a time report; the time tracked for a page, tag, or day.
+type TimeReportEntry struct {
+	Name     string
+	Duration time.Duration
+}
+
+// BuildTimeReport totals the time tracked on all Cards in the project between the start of the from date and the end of the to date,
+// grouped by page, tag, or day.
+func BuildTimeReport(project *Project, from, to time.Time, groupBy int) []*TimeReportEntry {
+
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+
+	totals := map[string]time.Duration{}
+
+	for _, page := range project.Pages {
+
+		if !page.Valid() {
+			continue
+		}
+
+		for _, card := range page.Cards {
+
+			if !card.Valid {
+				continue
+			}
+
+			sessions := card.TimeSessions()
+			if since, tracking := card.TrackingSince(); tracking {
+				sessions = append(sessions, TimeSession{Start: since, End: time.Now()})
+			}
+
+			for _, session := range sessions {
+
+				// Sessions are clipped to the report's range, and split by day
+				start, end := session.Start, session.End
+				if start.Before(from) {
+					start = from
+				}
+				if end.After(to) {
+					end = to
+				}
+
+				for start.Before(end) {
+
+					dayEnd := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+					if dayEnd.After(end) {
+						dayEnd = end
+					}
+
+					duration := dayEnd.Sub(start)
+
+					switch groupBy {
+					case TimeReportByPage:
+						totals[page.Path()] += duration
+					case TimeReportByTag:
+						tags := card.Tags()
+						if len(tags) == 0 {
+							tags = []string{"Untagged"}
+						}
+						for _, tag := range tags {
+							totals[tag] += duration
+						}
+					case TimeReportByDay:
+						totals[start.Format(DeadlineDateFormat)] += duration
+					}
+
+					start = dayEnd
+
+				}
+
+			}
+
+		}
+
+	}
+
+	entries := []*TimeReportEntry{}
+
+	for name, duration := range totals {
+		entries = append(entries, &TimeReportEntry{Name: name, Duration: duration})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if groupBy == TimeReportByDay || entries[i].Duration == entries[j].Duration {
+			return entries[i].Name < entries[j].Name
+		}
+		return entries[i].Duration > entries[j].Duration
+	})
+
+	return entries
+
+}
+
+// ExportTimeReportCSV writes a time report to a CSV file, with the time tracked given in both hours (as a decimal number) and H:MM:SS.
+func ExportTimeReportCSV(filename string, entries []*TimeReportEntry, groupBy int) error {
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+
+	records := [][]string{{timeReportGroupNames[groupBy], "Hours", "Time"}}
+
+	for _, entry := range entries {
+		records = append(records, []string{entry.Name, strconv.FormatFloat(entry.Duration.Hours(), 'f', 2, 64), FormatTrackedTime(entry.Duration)})
+	}
+
+	return writer.WriteAll(records)
+
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseTimeSessions(t *testing.T) {
+
+	session := func(start, end int64) TimeSession {
+		return TimeSession{Start: time.Unix(start, 0), End: time.Unix(end, 0)}
+	}
+
+	tests := []struct {
+		text string
+		want []TimeSession
+	}{
+		{"", []TimeSession{}},
+		{"1700000000-1700003600", []TimeSession{session(1700000000, 1700003600)}},
+		{"1700000000-1700003600, 1700010000-1700010000", []TimeSession{session(1700000000, 1700003600), session(1700010000, 1700010000)}},
+
+		// Sessions that can't be read, or that end before they start, are skipped
+		{"1700000000-1700003600,garbage,1700000000,1700003600-1700000000,1-2-3,5-x", []TimeSession{session(1700000000, 1700003600)}},
+	}
+
+	for _, test := range tests {
+		if got := parseTimeSessions(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parseTimeSessions(%q) = %v, want %v", test.text, got, test.want)
+		}
+	}
+
+}
+
+func TestCardTimeSessions(t *testing.T) {
+
+	card := &Card{Properties: NewProperties()}
+
+	if tracked := card.TrackedTime(); tracked != 0 {
+		t.Errorf("TrackedTime() of a new Card = %s, want 0", tracked)
+	}
+
+	start := time.Unix(1700000000, 0)
+	card.AddTimeSession(start, start.Add(time.Hour))
+	card.AddTimeSession(start.Add(time.Hour*2), start.Add(time.Hour*2+time.Minute*30))
+
+	if sessions := card.TimeSessions(); len(sessions) != 2 || !sessions[1].Start.Equal(start.Add(time.Hour*2)) {
+		t.Errorf("TimeSessions() = %v, want the 2 sessions added", sessions)
+	}
+
+	if tracked := card.TrackedTime(); tracked != time.Minute*90 {
+		t.Errorf("TrackedTime() = %s, want 1h30m", tracked)
+	}
+
+	card.StartTracking()
+	if !card.IsTracking() {
+		t.Fatalf("IsTracking() = false after StartTracking()")
+	}
+
+	// Pretend tracking started an hour ago
+	card.Properties.Get("tracking since").Set(float64(time.Now().Add(-time.Hour).Unix()))
+
+	if tracked := card.TrackedTime().Round(time.Minute); tracked != time.Minute*150 {
+		t.Errorf("TrackedTime() while tracking = %s, want 2h30m", tracked)
+	}
+
+	card.StopTracking()
+
+	if card.IsTracking() {
+		t.Errorf("IsTracking() = true after StopTracking()")
+	}
+
+	if sessions := card.TimeSessions(); len(sessions) != 3 {
+		t.Errorf("StopTracking() left %d sessions, want 3", len(sessions))
+	}
+
+	if tracked := card.TrackedTime().Round(time.Minute); tracked != time.Minute*150 {
+		t.Errorf("TrackedTime() after tracking = %s, want 2h30m", tracked)
+	}
+
+}
+
+func TestFormatTrackedTime(t *testing.T) {
+
+	tests := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{0, "0:00:00"},
+		{time.Second * 59, "0:00:59"},
+		{time.Millisecond * 59600, "0:01:00"},
+		{time.Hour + time.Minute*5 + time.Second*30, "1:05:30"},
+		{time.Hour * 100, "100:00:00"},
+	}
+
+	for _, test := range tests {
+		if got := FormatTrackedTime(test.duration); got != test.want {
+			t.Errorf("FormatTrackedTime(%s) = %q, want %q", test.duration, got, test.want)
+		}
+	}
+
+}